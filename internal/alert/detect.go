@@ -0,0 +1,125 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// Detect turns the field changes from a refresh into listing events.
+// Changes that are not interesting to the household (e.g. a corrected
+// sqft) produce no event.
+func Detect(propertyID int64, changes []property.FieldChange) []*Event {
+	var events []*Event
+	for _, c := range changes {
+		var e *Event
+		switch c.Field {
+		case "price":
+			e = detectPrice(c)
+		case "status":
+			e = detectStatus(c)
+		}
+		if e != nil {
+			e.PropertyID = propertyID
+			e.OldValue = c.OldValue
+			e.NewValue = c.NewValue
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// detectPrice reports a price drop or increase when both values are known.
+func detectPrice(c property.FieldChange) *Event {
+	if c.OldValue == nil || c.NewValue == nil {
+		return nil
+	}
+	oldPrice, err := strconv.ParseInt(*c.OldValue, 10, 64)
+	if err != nil || oldPrice <= 0 {
+		return nil
+	}
+	newPrice, err := strconv.ParseInt(*c.NewValue, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	pct := float64(oldPrice-newPrice) / float64(oldPrice) * 100
+	switch {
+	case newPrice < oldPrice:
+		return &Event{
+			Kind:    KindPriceDrop,
+			Message: fmt.Sprintf("Price dropped %.1f%% ($%s → $%s)", pct, property.FormatDollars(oldPrice), property.FormatDollars(newPrice)),
+		}
+	case newPrice > oldPrice:
+		return &Event{
+			Kind:    KindPriceIncrease,
+			Message: fmt.Sprintf("Price increased %.1f%% ($%s → $%s)", -pct, property.FormatDollars(oldPrice), property.FormatDollars(newPrice)),
+		}
+	}
+	return nil
+}
+
+// detectStatus reports transitions between listing statuses.
+func detectStatus(c property.FieldChange) *Event {
+	if c.NewValue == nil {
+		return nil
+	}
+	newStatus := normalizeStatus(*c.NewValue)
+	oldStatus := ""
+	if c.OldValue != nil {
+		oldStatus = normalizeStatus(*c.OldValue)
+	}
+	if newStatus == oldStatus {
+		return nil
+	}
+
+	switch newStatus {
+	case "pending":
+		return &Event{Kind: KindPending, Message: "Went pending"}
+	case "sold":
+		return &Event{Kind: KindSold, Message: "Sold"}
+	case "off_market":
+		return &Event{Kind: KindOffMarket, Message: "Taken off the market"}
+	case "active":
+		if oldStatus != "" {
+			return &Event{Kind: KindBackOnMarket, Message: "Back on market"}
+		}
+	}
+	return nil
+}
+
+// normalizeStatus folds the many realtor.com status strings into
+// active, pending, sold, or off_market.
+func normalizeStatus(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "for_sale", "active", "ready_to_build", "coming_soon", "new_construction":
+		return "active"
+	case "pending", "contingent", "under_contract":
+		return "pending"
+	case "sold", "recently_sold":
+		return "sold"
+	case "off_market", "withdrawn", "expired", "cancelled":
+		return "off_market"
+	}
+	return s
+}
+
+// Watchable reports whether a property's listing is still worth re-fetching.
+// Sold and off-market listings are skipped to save API calls, and manual
+// entries have no listing to fetch.
+func Watchable(p *property.Property) bool {
+	if p.IsManual() {
+		return false
+	}
+	if p.Status == nil {
+		return true
+	}
+	switch normalizeStatus(*p.Status) {
+	case "sold", "off_market":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		changes  []property.FieldChange
+		wantKind []Kind
+		wantMsg  string
+	}{
+		{
+			name:     "price drop",
+			changes:  []property.FieldChange{{Field: "price", OldValue: ptr("250000"), NewValue: ptr("242500")}},
+			wantKind: []Kind{KindPriceDrop},
+			wantMsg:  "Price dropped 3.0% ($250,000 → $242,500)",
+		},
+		{
+			name:     "price increase",
+			changes:  []property.FieldChange{{Field: "price", OldValue: ptr("200000"), NewValue: ptr("210000")}},
+			wantKind: []Kind{KindPriceIncrease},
+			wantMsg:  "Price increased 5.0% ($200,000 → $210,000)",
+		},
+		{
+			name:     "first price is not an event",
+			changes:  []property.FieldChange{{Field: "price", NewValue: ptr("200000")}},
+			wantKind: nil,
+		},
+		{
+			name:     "went pending",
+			changes:  []property.FieldChange{{Field: "status", OldValue: ptr("for_sale"), NewValue: ptr("pending")}},
+			wantKind: []Kind{KindPending},
+		},
+		{
+			name:     "contingent counts as pending",
+			changes:  []property.FieldChange{{Field: "status", OldValue: ptr("for_sale"), NewValue: ptr("contingent")}},
+			wantKind: []Kind{KindPending},
+		},
+		{
+			name:     "back on market",
+			changes:  []property.FieldChange{{Field: "status", OldValue: ptr("pending"), NewValue: ptr("for_sale")}},
+			wantKind: []Kind{KindBackOnMarket},
+		},
+		{
+			name:     "sold",
+			changes:  []property.FieldChange{{Field: "status", OldValue: ptr("pending"), NewValue: ptr("sold")}},
+			wantKind: []Kind{KindSold},
+		},
+		{
+			name:     "status spelling change is not an event",
+			changes:  []property.FieldChange{{Field: "status", OldValue: ptr("active"), NewValue: ptr("for_sale")}},
+			wantKind: nil,
+		},
+		{
+			name:     "other fields ignored",
+			changes:  []property.FieldChange{{Field: "sqft", OldValue: ptr("1500"), NewValue: ptr("1550")}},
+			wantKind: nil,
+		},
+		{
+			name: "multiple events",
+			changes: []property.FieldChange{
+				{Field: "price", OldValue: ptr("300000"), NewValue: ptr("290000")},
+				{Field: "status", OldValue: ptr("for_sale"), NewValue: ptr("pending")},
+			},
+			wantKind: []Kind{KindPriceDrop, KindPending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := Detect(42, tt.changes)
+			if len(events) != len(tt.wantKind) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.wantKind))
+			}
+			for i, e := range events {
+				if e.Kind != tt.wantKind[i] {
+					t.Errorf("event %d kind = %q, want %q", i, e.Kind, tt.wantKind[i])
+				}
+				if e.PropertyID != 42 {
+					t.Errorf("event %d property_id = %d, want 42", i, e.PropertyID)
+				}
+			}
+			if tt.wantMsg != "" && events[0].Message != tt.wantMsg {
+				t.Errorf("message = %q, want %q", events[0].Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestWatchable(t *testing.T) {
+	tests := []struct {
+		status *string
+		want   bool
+	}{
+		{nil, true},
+		{ptr("for_sale"), true},
+		{ptr("pending"), true},
+		{ptr("sold"), false},
+		{ptr("off_market"), false},
+	}
+
+	for _, tt := range tests {
+		got := Watchable(&property.Property{Status: tt.status})
+		if got != tt.want {
+			t.Errorf("Watchable(%v) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+
+	if Watchable(&property.Property{Source: property.SourceManual}) {
+		t.Error("manual entries should not be watchable")
+	}
+}
@@ -0,0 +1,28 @@
+// Package alert detects notable listing changes and notifies the household.
+package alert
+
+import "time"
+
+// Kind identifies the type of listing change.
+type Kind string
+
+const (
+	KindPriceDrop     Kind = "price_drop"
+	KindPriceIncrease Kind = "price_increase"
+	KindPending       Kind = "pending"
+	KindBackOnMarket  Kind = "back_on_market"
+	KindSold          Kind = "sold"
+	KindOffMarket     Kind = "off_market"
+)
+
+// Event is a notable change detected on a tracked listing.
+type Event struct {
+	ID         int64      `json:"id"`
+	PropertyID int64      `json:"property_id"`
+	Kind       Kind       `json:"kind"`
+	Message    string     `json:"message"`
+	OldValue   *string    `json:"old_value,omitempty"`
+	NewValue   *string    `json:"new_value,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
@@ -0,0 +1,129 @@
+package alert
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Repository provides data access for listing events.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates an event repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const selectColumns = `id, property_id, kind, message, old_value, new_value, created_at, notified_at`
+
+// Add stores a new event and fills in its ID and timestamps.
+func (r *Repository) Add(e *Event) error {
+	result, err := r.db.Exec(
+		"INSERT INTO listing_events (property_id, kind, message, old_value, new_value) VALUES (?, ?, ?, ?, ?)",
+		e.PropertyID, string(e.Kind), e.Message, e.OldValue, e.NewValue,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting insert id: %w", err)
+	}
+
+	row := r.db.QueryRow(fmt.Sprintf("SELECT %s FROM listing_events WHERE id = ?", selectColumns), id)
+	saved, err := scanEvent(row)
+	if err != nil {
+		return fmt.Errorf("reading back event: %w", err)
+	}
+	*e = *saved
+
+	return nil
+}
+
+// List returns the most recent events, newest first.
+// If propertyID is non-zero only that property's events are returned.
+func (r *Repository) List(propertyID int64, limit int) ([]*Event, error) {
+	query := fmt.Sprintf("SELECT %s FROM listing_events", selectColumns)
+	var args []interface{}
+	if propertyID != 0 {
+		query += " WHERE property_id = ?"
+		args = append(args, propertyID)
+	}
+	query += " ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return r.query(query, args...)
+}
+
+// ListUnnotified returns events that have not been emailed yet, oldest first.
+func (r *Repository) ListUnnotified() ([]*Event, error) {
+	return r.query(fmt.Sprintf("SELECT %s FROM listing_events WHERE notified_at IS NULL ORDER BY id", selectColumns))
+}
+
+// MarkNotified records that the given events were sent.
+func (r *Repository) MarkNotified(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf("UPDATE listing_events SET notified_at = CURRENT_TIMESTAMP WHERE id IN (%s)",
+		strings.Join(placeholders, ", "))
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("marking events notified: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) query(query string, args ...interface{}) (_ []*Event, err error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var events []*Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// scanEvent scans an event from a database row.
+func scanEvent(row interface{ Scan(...interface{}) error }) (*Event, error) {
+	var e Event
+	var kind string
+	var notifiedAt sql.NullTime
+	if err := row.Scan(&e.ID, &e.PropertyID, &kind, &e.Message, &e.OldValue, &e.NewValue, &e.CreatedAt, &notifiedAt); err != nil {
+		return nil, err
+	}
+	e.Kind = Kind(kind)
+	if notifiedAt.Valid {
+		e.NotifiedAt = &notifiedAt.Time
+	}
+	return &e, nil
+}
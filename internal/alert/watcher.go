@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// Notifier delivers an alert email to the household.
+type Notifier func(subject, body string) error
+
+// Watcher refreshes active listings, records change events, and emails
+// any events that have not been sent yet. The server runs Check and
+// Notify as scheduled jobs.
+type Watcher struct {
+	props   *property.Repository
+	service *property.Service
+	events  *Repository
+	notify  Notifier
+	baseURL string
+}
+
+// NewWatcher creates a listing watcher. notify may be nil, in which case
+// events are recorded but never sent.
+func NewWatcher(props *property.Repository, service *property.Service, events *Repository, notify Notifier, baseURL string) *Watcher {
+	return &Watcher{props: props, service: service, events: events, notify: notify, baseURL: baseURL}
+}
+
+// Record detects and stores events for a completed refresh.
+func (w *Watcher) Record(res *property.RefreshResult) ([]*Event, error) {
+	events := Detect(res.Property.ID, res.Changes)
+	for _, e := range events {
+		if err := w.events.Add(e); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// Check refreshes every watchable listing once and then sends pending events.
+// A failed refresh is logged and skipped so one bad listing doesn't block the rest.
+// Cancelling ctx stops the check between listings.
+func (w *Watcher) Check(ctx context.Context) error {
+	props, err := w.props.List(property.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing properties: %w", err)
+	}
+
+	for _, p := range props {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !Watchable(p) {
+			continue
+		}
+		// The watcher exists to notice changes, so it never reads the cache.
+		res, err := w.service.Refresh(ctx, p.ID, property.FetchOptions{NoCache: true})
+		if err != nil {
+			slog.Warn("listing refresh failed", "id", p.ID, "err", err)
+			continue
+		}
+		events, err := w.Record(res)
+		if err != nil {
+			return fmt.Errorf("recording events for property %d: %w", p.ID, err)
+		}
+		for _, e := range events {
+			slog.Info("listing event", "id", p.ID, "kind", e.Kind, "message", e.Message)
+		}
+	}
+
+	return w.Notify()
+}
+
+// Notify emails all unsent events as a single digest and marks them sent.
+func (w *Watcher) Notify() error {
+	if w.notify == nil {
+		return nil
+	}
+
+	pending, err := w.events.ListUnnotified()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	props := make(map[int64]*property.Property)
+	for _, e := range pending {
+		if _, ok := props[e.PropertyID]; ok {
+			continue
+		}
+		p, err := w.props.GetByID(e.PropertyID)
+		if err != nil {
+			return err
+		}
+		props[e.PropertyID] = p
+	}
+
+	subject := fmt.Sprintf("Listing updates (%d)", len(pending))
+	if err := w.notify(subject, FormatDigest(pending, props, w.baseURL)); err != nil {
+		return fmt.Errorf("sending listing alerts: %w", err)
+	}
+
+	ids := make([]int64, len(pending))
+	for i, e := range pending {
+		ids[i] = e.ID
+	}
+	return w.events.MarkNotified(ids)
+}
+
+// FormatDigest builds a plain-text email body listing events per property.
+func FormatDigest(events []*Event, props map[int64]*property.Property, baseURL string) string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "Hi,\n\nThere are %d updates on houses we're tracking:\n\n", len(events))
+
+	var order []int64
+	byProperty := make(map[int64][]*Event)
+	for _, e := range events {
+		if _, ok := byProperty[e.PropertyID]; !ok {
+			order = append(order, e.PropertyID)
+		}
+		byProperty[e.PropertyID] = append(byProperty[e.PropertyID], e)
+	}
+
+	for _, id := range order {
+		if p, ok := props[id]; ok {
+			fmt.Fprintf(&buf, "%s", p.Address)
+			if p.Rating != nil {
+				fmt.Fprintf(&buf, " (%s)", strings.Repeat("★", int(*p.Rating)))
+			}
+			fmt.Fprintln(&buf)
+		} else {
+			fmt.Fprintf(&buf, "Property #%d\n", id)
+		}
+		for _, e := range byProperty[id] {
+			fmt.Fprintf(&buf, "   - %s\n", e.Message)
+		}
+		if baseURL != "" {
+			fmt.Fprintf(&buf, "   %s/property/%d\n", strings.TrimSuffix(baseURL, "/"), id)
+		}
+		fmt.Fprintln(&buf)
+	}
+
+	return buf.String()
+}
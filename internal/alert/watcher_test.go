@@ -0,0 +1,172 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestWatcherCheck(t *testing.T) {
+	rapidResponse := `{"list_price": 250000, "prop_status": "for_sale"}`
+	var fetches int
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if _, err := fmt.Fprint(w, rapidResponse); err != nil {
+			t.Errorf("write response: %v", err)
+		}
+	}))
+	defer rapidServer.Close()
+
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+
+	client, err := mls.NewClient("test-key")
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	mls.SetTestURLs(client, "", "", rapidServer.URL)
+
+	props := property.NewRepository(d)
+	events := NewRepository(d)
+
+	var sent []string
+	notify := func(subject, body string) error {
+		sent = append(sent, body)
+		return nil
+	}
+	w := NewWatcher(props, property.NewService(props, client), events, notify, "http://localhost:8080")
+
+	price := int64(260000)
+	status := "for_sale"
+	active, err := props.Insert(&property.Property{
+		Address: "1 Active St", MprID: "M-ACTIVE", RealtorURL: "/detail/active",
+		Price: &price, Status: &status, RawJSON: json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	sold := "sold"
+	if _, err := props.Insert(&property.Property{
+		Address: "2 Sold St", MprID: "M-SOLD", RealtorURL: "/detail/sold",
+		Status: &sold, RawJSON: json.RawMessage(`{}`),
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1 (sold listing skipped)", fetches)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sent))
+	}
+	if !strings.Contains(sent[0], "1 Active St") || !strings.Contains(sent[0], "Price dropped") {
+		t.Errorf("unexpected email body:\n%s", sent[0])
+	}
+	if !strings.Contains(sent[0], fmt.Sprintf("http://localhost:8080/property/%d", active.ID)) {
+		t.Errorf("expected property link in body:\n%s", sent[0])
+	}
+
+	// No new changes means no new email.
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatalf("second check: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Errorf("sent %d emails after unchanged check, want 1", len(sent))
+	}
+
+	rapidResponse = `{"list_price": 250000, "prop_status": "pending"}`
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatalf("third check: %v", err)
+	}
+	if len(sent) != 2 || !strings.Contains(sent[1], "Went pending") {
+		t.Errorf("expected pending alert, got %v", sent)
+	}
+
+	all, err := events.List(active.ID, 0)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d events, want 2", len(all))
+	}
+	for _, e := range all {
+		if e.NotifiedAt == nil {
+			t.Errorf("event %d not marked notified", e.ID)
+		}
+	}
+}
+
+func TestWatcherNotifyRetriesOnFailure(t *testing.T) {
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+
+	props := property.NewRepository(d)
+	events := NewRepository(d)
+	p, err := props.Insert(&property.Property{
+		Address: "3 Retry St", MprID: "M-RETRY", RealtorURL: "/detail/retry", RawJSON: json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := events.Add(&Event{PropertyID: p.ID, Kind: KindSold, Message: "Sold"}); err != nil {
+		t.Fatalf("add event: %v", err)
+	}
+
+	fail := true
+	notify := func(subject, body string) error {
+		if fail {
+			return fmt.Errorf("smtp down")
+		}
+		return nil
+	}
+	w := NewWatcher(props, nil, events, notify, "")
+
+	if err := w.Notify(); err == nil {
+		t.Fatal("expected error from failing notifier")
+	}
+	pending, err := events.ListUnnotified()
+	if err != nil {
+		t.Fatalf("list unnotified: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("got %d pending events, want 1", len(pending))
+	}
+
+	fail = false
+	if err := w.Notify(); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	pending, err = events.ListUnnotified()
+	if err != nil {
+		t.Fatalf("list unnotified: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("got %d pending events after send, want 0", len(pending))
+	}
+}
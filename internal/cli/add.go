@@ -1,34 +1,124 @@
 package cli
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/property"
 )
 
+// maxCandidates is how many geocoder matches hf add offers to pick from.
+const maxCandidates = 5
+
 func newAddCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "add <address>",
-		Short: "Add a property by address",
-		Long:  "Look up a property by address using the realtor.com API, fetch its details, and store it.",
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  runAdd,
+	var (
+		noCache, first, manual, force bool
+		price, sqft                   int64
+		beds, baths                   float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <address|url|mpr_id>",
+		Short: "Add a property by address, realtor.com URL, or property ID",
+		Long: `Look up a property using the realtor.com API, fetch its details, and store it.
+
+When run in a terminal, an address that matches several listings (e.g.
+units in the same building) shows the candidates and asks which one to add
+before the RapidAPI call is made. Use --first to take the top match.
+
+A realtor.com listing URL or property ID (M-number) skips the address
+lookup entirely.
+
+Use --manual for houses the MLS doesn't have (for-sale-by-owner, pocket
+listings). Nothing is looked up; the details come from the flags. Link the
+entry to a listing later with hf link.
+
+A house that looks like one already tracked (the same address written
+differently, or relisted under a new ID) is not added; the matches are
+listed instead. Use --force to add it anyway.
+
+Examples:
+  hf add "123 Main St, Yukon, OK 73099"
+  hf add https://www.realtor.com/realestateandhomes-detail/123-Main-St_Yukon_OK_73099_M75364-50927
+  hf add M75364-50927
+  hf add --manual "789 Elm St, Yukon, OK 73099" --price 240000 --beds 3 --baths 2 --sqft 1600`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address := strings.Join(args, " ")
+			if !manual {
+				for _, name := range []string{"price", "beds", "baths", "sqft"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s requires --manual", name)
+					}
+				}
+				return runAdd(address, noCache, first, force)
+			}
+
+			in := property.ManualInput{Address: address, Force: force}
+			if cmd.Flags().Changed("price") {
+				in.Price = &price
+			}
+			if cmd.Flags().Changed("beds") {
+				in.Bedrooms = &beds
+			}
+			if cmd.Flags().Changed("baths") {
+				in.Bathrooms = &baths
+			}
+			if cmd.Flags().Changed("sqft") {
+				in.Sqft = &sqft
+			}
+			return runAddManual(in)
+		},
 	}
-}
 
-func runAdd(cmd *cobra.Command, args []string) error {
-	address := strings.Join(args, " ")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "ignore cached listing data and make a fresh RapidAPI call")
+	cmd.Flags().BoolVar(&first, "first", false, "use the top address match without prompting")
+	cmd.Flags().BoolVar(&manual, "manual", false, "enter the property by hand instead of looking it up")
+	cmd.Flags().BoolVar(&force, "force", false, "add the property even if it looks like one already tracked")
+	cmd.Flags().Int64Var(&price, "price", 0, "asking price (with --manual)")
+	cmd.Flags().Float64Var(&beds, "beds", 0, "bedrooms (with --manual)")
+	cmd.Flags().Float64Var(&baths, "baths", 0, "bathrooms (with --manual)")
+	cmd.Flags().Int64Var(&sqft, "sqft", 0, "square footage (with --manual)")
 
+	return cmd
+}
+
+func runAdd(address string, noCache, first, force bool) error {
 	c := newAPIClient()
 
+	ref := address
+	if _, _, isRef := mls.ParseListingRef(address); !isRef && !first && !isJSON() && stdinIsTerminal() {
+		candidates, err := c.Suggest(address, maxCandidates)
+		if err != nil {
+			return fmt.Errorf("looking up address: %w", err)
+		}
+		if len(candidates) == 0 {
+			return fmt.Errorf("no property found for address: %s", address)
+		}
+		picked, err := chooseCandidate(os.Stdin, os.Stdout, candidates)
+		if err != nil {
+			return err
+		}
+		if ref, err = candidateRef(picked); err != nil {
+			return err
+		}
+	}
+
 	if !isJSON() {
 		fmt.Printf("Looking up: %s\n", address)
 	}
 
-	p, err := c.AddProperty(address)
+	p, err := c.AddProperty(ref, noCache, force)
 	if err != nil {
-		return fmt.Errorf("adding property: %w", err)
+		return addError(err)
 	}
 
 	if isJSON() {
@@ -39,3 +129,90 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	printPropertySummary(p)
 	return nil
 }
+
+func runAddManual(in property.ManualInput) error {
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	p, err := newAPIClient().AddManualProperty(in)
+	if err != nil {
+		return addError(err)
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	fmt.Println("Property added manually.")
+	printPropertySummary(p)
+	return nil
+}
+
+// addError explains a failed add. When the house looks like one already
+// tracked, the matches are listed first.
+func addError(err error) error {
+	var dupErr *property.DuplicateError
+	if !errors.As(err, &dupErr) {
+		return fmt.Errorf("adding property: %w", err)
+	}
+	if !isJSON() {
+		printDuplicates(dupErr.Duplicates)
+	}
+	return fmt.Errorf("not added: %w; use --force to add it anyway", err)
+}
+
+// chooseCandidate lists candidates on out and reads a choice from in.
+// A single candidate is returned without prompting; an empty answer picks
+// the first.
+func chooseCandidate(in io.Reader, out io.Writer, candidates []mls.Candidate) (mls.Candidate, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	fmt.Fprintln(out, "Multiple properties match:")
+	for i, cand := range candidates {
+		fmt.Fprintf(out, "  %d) %s  [%s]\n", i+1, cand.Address, cand.MprID)
+	}
+	fmt.Fprintf(out, "Add which one? [1-%d, Enter for 1, q to cancel]: ", len(candidates))
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return mls.Candidate{}, fmt.Errorf("reading input: %w", err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	switch {
+	case answer == "":
+		return candidates[0], nil
+	case strings.EqualFold(answer, "q"):
+		return mls.Candidate{}, fmt.Errorf("cancelled")
+	}
+
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(candidates) {
+		return mls.Candidate{}, fmt.Errorf("invalid choice: %s", answer)
+	}
+	return candidates[n-1], nil
+}
+
+// candidateRef returns the property ID to send to the server for a picked
+// candidate, so the server adds that listing without asking the geocoder
+// again. Sending the address instead could resolve to a different unit or
+// town, so an ID that can't be sent is an error.
+func candidateRef(c mls.Candidate) (string, error) {
+	mprID, _, ok := mls.ParseListingRef(c.MprID)
+	if !ok {
+		return "", fmt.Errorf("cannot add %s by its property ID %q; add it by its realtor.com URL instead", c.Address, c.MprID)
+	}
+	return mprID, nil
+}
+
+// stdinIsTerminal reports whether stdin is interactive.
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestChooseCandidate(t *testing.T) {
+	candidates := []mls.Candidate{
+		{MprID: "1111111111", Address: "100 Main St Apt 1, Yukon, OK 73099"},
+		{MprID: "2222222222", Address: "100 Main St Apt 2, Yukon, OK 73099"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"pick second", "2\n", "2222222222", false},
+		{"enter picks first", "\n", "1111111111", false},
+		{"eof picks first", "", "1111111111", false},
+		{"cancel", "q\n", "", true},
+		{"out of range", "3\n", "", true},
+		{"not a number", "abc\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := chooseCandidate(strings.NewReader(tt.input), &out, candidates)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MprID != tt.want {
+				t.Errorf("picked %q, want %q", got.MprID, tt.want)
+			}
+			if !strings.Contains(out.String(), "Apt 2") {
+				t.Errorf("prompt did not list candidates: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestChooseSingleCandidateDoesNotPrompt(t *testing.T) {
+	var out bytes.Buffer
+	got, err := chooseCandidate(strings.NewReader(""), &out, []mls.Candidate{{MprID: "1111111111"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MprID != "1111111111" || out.Len() != 0 {
+		t.Errorf("got %+v, output %q", got, out.String())
+	}
+}
+
+func TestCandidateRef(t *testing.T) {
+	got, err := candidateRef(mls.Candidate{MprID: "M75364-50927", Address: "1 Elm St"})
+	if err != nil || got != "7536450927" {
+		t.Errorf("ref = %q, %v; want the property ID", got, err)
+	}
+	if got, err := candidateRef(mls.Candidate{MprID: "abc", Address: "1 Elm St"}); err == nil {
+		t.Errorf("ref = %q, want an error rather than the address", got)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <id>",
+		Short: "Archive a property",
+		Long: `Archive a property you are done with. It drops out of "hf list" but is
+kept, with its comments, visits and history, for later reference. See
+archived houses with "hf list --archived"; bring one back with
+"hf restore <id>".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runArchive(id)
+		},
+	}
+}
+
+func runArchive(id int64) error {
+	p, err := newAPIClient().ArchiveProperty(id)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	fmt.Printf("Property #%d archived: %s\n", p.ID, p.Address)
+	return nil
+}
@@ -113,6 +113,82 @@ func TestRemoveRequiresID(t *testing.T) {
 	}
 }
 
+func TestRefreshArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no ids or --all", []string{"refresh"}},
+		{"ids and --all", []string{"refresh", "1", "--all"}},
+		{"non-numeric id", []string{"refresh", "abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestAddManualFlagsRequireManual(t *testing.T) {
+	_, err := executeCommand("add", "123 Main St", "--price", "200000")
+	if err == nil {
+		t.Fatal("expected error for --price without --manual")
+	}
+}
+
+func TestLinkArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no args", []string{"link"}},
+		{"id only", []string{"link", "1"}},
+		{"non-numeric id", []string{"link", "abc", "M1234567890"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestEditArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no id", []string{"edit", "--beds", "4"}},
+		{"no fields", []string{"edit", "1"}},
+		{"bad value", []string{"edit", "1", "--beds", "four"}},
+		{"unknown reset", []string{"edit", "1", "--reset", "pool"}},
+		{"set and reset", []string{"edit", "1", "--beds", "4", "--reset", "beds"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestReparseAcceptsNoArgs(t *testing.T) {
+	_, err := executeCommand("reparse", "1")
+	if err == nil {
+		t.Fatal("expected error for extra args")
+	}
+}
+
 func TestServeAcceptsNoArgs(t *testing.T) {
 	// serve should reject extra args
 	_, err := executeCommand("serve", "extra")
@@ -120,3 +196,234 @@ func TestServeAcceptsNoArgs(t *testing.T) {
 		t.Fatal("expected error for extra args")
 	}
 }
+
+func TestCacheClearAcceptsAtMostOneArg(t *testing.T) {
+	_, err := executeCommand("cache", "clear", "M1", "M2")
+	if err == nil {
+		t.Fatal("expected error for two mpr_ids")
+	}
+}
+
+func TestPlaceArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"add missing coordinates", []string{"place", "add", "work"}},
+		{"add bad latitude", []string{"place", "add", "work", "north", "-97.5"}},
+		{"add bad longitude", []string{"place", "add", "work", "35.4", "west"}},
+		{"rm no name", []string{"place", "rm"}},
+		{"ls extra args", []string{"place", "ls", "work"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestTagArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"add no tag", []string{"tag", "add", "1"}},
+		{"add bad id", []string{"tag", "add", "abc", "needs-roof"}},
+		{"rm no tag", []string{"tag", "rm", "1"}},
+		{"rm bad id", []string{"tag", "rm", "abc", "needs-roof"}},
+		{"rename one arg", []string{"tag", "rename", "needs-roof"}},
+		{"delete no name", []string{"tag", "delete"}},
+		{"ls extra args", []string{"tag", "ls", "needs-roof"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestScoreArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no id", []string{"score"}},
+		{"bad id", []string{"score", "abc"}},
+		{"score without value", []string{"score", "1", "kitchen"}},
+		{"score not a number", []string{"score", "1", "kitchen=great"}},
+		{"score without name", []string{"score", "1", "=4"}},
+		{"criteria add no name", []string{"criteria", "add"}},
+		{"criteria add bad weight", []string{"criteria", "add", "kitchen", "--weight", "11"}},
+		{"criteria add unknown field", []string{"criteria", "add", "pool", "--field", "pool", "--best", "1", "--worst", "0"}},
+		{"criteria add without range", []string{"criteria", "add", "price", "--field", "price"}},
+		{"criteria set nothing", []string{"criteria", "set", "kitchen"}},
+		{"criteria rm no name", []string{"criteria", "rm"}},
+		{"list bad sort", []string{"list", "--sort", "pool"}},
+		{"list bad sort direction", []string{"list", "--sort", "price:up"}},
+		{"list negative min beds", []string{"list", "--min-beds", "-1"}},
+		{"list bad rating-by", []string{"list", "--rating", "3", "--rating-by", "you"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestCompareArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no ids", []string{"compare"}},
+		{"one id", []string{"compare", "1"}},
+		{"too many ids", []string{"compare", "1", "2", "3", "4", "5", "6", "7"}},
+		{"bad id", []string{"compare", "1", "abc"}},
+		{"search no words", []string{"search"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestListRejectsInvalidMaxDistance(t *testing.T) {
+	_, err := executeCommand("list", "--max-distance", "work")
+	if err == nil {
+		t.Fatal("expected error for a limit without a distance")
+	}
+}
+
+func TestCostAndFinancingArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"cost no ID", []string{"cost"}},
+		{"cost bad ID", []string{"cost", "abc"}},
+		{"financing set nothing", []string{"financing", "set"}},
+		{"financing set fractional term", []string{"financing", "set", "--term", "29.5"}},
+		{"financing set bad rate", []string{"financing", "set", "--rate", "low"}},
+		{"list negative max monthly", []string{"list", "--max-monthly", "-100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestDedupeMergeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no IDs", []string{"dedupe", "merge"}},
+		{"one ID", []string{"dedupe", "merge", "3"}},
+		{"bad keep ID", []string{"dedupe", "merge", "abc", "4"}},
+		{"bad duplicate ID", []string{"dedupe", "merge", "3", "abc"}},
+		{"same ID", []string{"dedupe", "merge", "3", "3"}},
+		{"dedupe extra args", []string{"dedupe", "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestImportArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no file", []string{"import"}},
+		{"two files", []string{"import", "a.csv", "b.csv"}},
+		{"missing file", []string{"import", "/nonexistent/addresses.csv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestUsageBudgetArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"usage extra args", []string{"usage", "500"}},
+		{"no budget", []string{"usage", "budget"}},
+		{"not a number", []string{"usage", "budget", "lots"}},
+		{"negative", []string{"usage", "budget", "--", "-5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestJobsAcceptsNoArgs(t *testing.T) {
+	_, err := executeCommand("jobs", "cleanup")
+	if err == nil {
+		t.Fatal("expected error for extra args")
+	}
+}
+
+func TestRestoreRequiresNumericID(t *testing.T) {
+	if _, err := executeCommand("restore"); err == nil {
+		t.Error("expected error when no ID provided")
+	}
+	if _, err := executeCommand("restore", "abc"); err == nil {
+		t.Error("expected error for non-numeric ID")
+	}
+}
+
+func TestArchiveRequiresID(t *testing.T) {
+	_, err := executeCommand("archive")
+	if err == nil {
+		t.Fatal("expected error when no ID provided")
+	}
+}
+
+func TestTrashAcceptsNoArgs(t *testing.T) {
+	_, err := executeCommand("trash", "1")
+	if err == nil {
+		t.Fatal("expected error for extra args")
+	}
+}
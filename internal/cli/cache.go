@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the server's MLS listing cache",
+		Long: `Inspect or clear cached RapidAPI responses on the server.
+
+Cached responses are reused by add and refresh until they expire
+(HF_MLS_CACHE_TTL on the server, default 24h).`,
+	}
+
+	cmd.AddCommand(newCacheLsCmd(), newCacheClearCmd())
+
+	return cmd
+}
+
+func newCacheLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List cached listing responses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheLs()
+		},
+	}
+}
+
+func runCacheLs() error {
+	c := newAPIClient()
+
+	entries, err := c.ListCache()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(entries)
+	}
+
+	return printCacheTable(entries)
+}
+
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear [mpr_id]",
+		Short: "Clear cached listing responses",
+		Long: `Remove cached listing responses so the next add or refresh fetches fresh data.
+
+Examples:
+  hf cache clear              # clear everything
+  hf cache clear M1234567890  # clear one listing`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var mprID string
+			if len(args) == 1 {
+				mprID = args[0]
+			}
+			return runCacheClear(mprID)
+		},
+	}
+}
+
+func runCacheClear(mprID string) error {
+	c := newAPIClient()
+
+	n, err := c.ClearCache(mprID)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]int64{"removed": n})
+	}
+
+	fmt.Printf("Removed %d cached listing(s).\n", n)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/compare"
+)
+
+func newCompareCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compare <id> <id>...",
+		Short: "Compare properties side by side",
+		Long: `Lay 2-6 properties side by side: every listing field, price per sqft,
+distances, score, each member's rating, visits and tags, one row per field.
+In each row the best value is marked ▲ and the worst ▼ where one way is
+clearly better. The latest comments on each property follow the table.
+
+Examples:
+  hf compare 1 4 7`,
+		Args: cobra.RangeArgs(compare.MinProperties, compare.MaxProperties),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := make([]int64, len(args))
+			for i, arg := range args {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid property ID: %s", arg)
+				}
+				ids[i] = id
+			}
+			return runCompare(ids)
+		},
+	}
+}
+
+func runCompare(ids []int64) error {
+	c := newAPIClient()
+
+	cmp, err := c.Compare(ids)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(cmp)
+	}
+
+	return printComparison(cmp)
+}
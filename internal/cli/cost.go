@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newCostCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cost <id>",
+		Short: "Show a property's estimated monthly cost",
+		Long: `Show principal and interest, taxes, insurance, PMI, HOA and the total
+monthly cost of a property under your financing profile (see "hf financing").
+
+Taxes come from the listing's tax record; when there isn't one they are
+estimated from your profile's tax rate.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runCost(id)
+		},
+	}
+}
+
+func runCost(id int64) error {
+	c := newAPIClient()
+
+	resp, err := c.PropertyCost(id)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(resp)
+	}
+
+	printCost(resp.Profile, resp.Cost)
+	return nil
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/score"
+)
+
+func newCriteriaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "criteria",
+		Short: "Manage the weighted criteria houses are scored on",
+		Long: `Manage the household's scoring criteria. Each house gets a 0-100 score:
+the weighted average of its criteria, shown by "hf score" and
+"hf list --sort score".
+
+A computed criterion reads a listing field and scores it on a line from
+its worst value (0) to its best (full marks), so for price, best is below
+worst. A criterion without --field is scored by hand for each house with
+"hf score <id> name=1..5".
+
+Fields: ` + strings.Join(score.Fields, ", ") + `.`,
+	}
+
+	cmd.AddCommand(newCriteriaAddCmd(), newCriteriaLsCmd(), newCriteriaSetCmd(), newCriteriaRmCmd())
+
+	return cmd
+}
+
+func newCriteriaAddCmd() *cobra.Command {
+	var (
+		c           score.Criterion
+		best, worst float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a scoring criterion",
+		Long: `Add a scoring criterion, computed from a listing field or scored by hand.
+
+Examples:
+  hf criteria add kitchen --weight 3
+  hf criteria add price --weight 5 --field price --best 250000 --worst 400000
+  hf criteria add commute --weight 4 --field distance --place work --best 5 --worst 30`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.Name = args[0]
+			if cmd.Flags().Changed("best") {
+				c.Best = &best
+			}
+			if cmd.Flags().Changed("worst") {
+				c.Worst = &worst
+			}
+			if err := c.Validate(); err != nil {
+				return err
+			}
+			return runCriteriaAdd(&c)
+		},
+	}
+
+	cmd.Flags().IntVar(&c.Weight, "weight", 1, "relative importance, 1-10")
+	cmd.Flags().StringVar(&c.Field, "field", "", "listing field to score; omit to score by hand")
+	cmd.Flags().StringVar(&c.Place, "place", "", "place to measure from, for --field distance")
+	cmd.Flags().Float64Var(&best, "best", 0, "field value that scores full marks")
+	cmd.Flags().Float64Var(&worst, "worst", 0, "field value that scores nothing")
+
+	return cmd
+}
+
+func runCriteriaAdd(criterion *score.Criterion) error {
+	c := newAPIClient()
+
+	saved, err := c.AddCriterion(criterion)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(saved)
+	}
+
+	fmt.Printf("Added criterion %q (weight %d).\n", saved.Name, saved.Weight)
+	return nil
+}
+
+func newCriteriaLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List scoring criteria",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCriteriaLs()
+		},
+	}
+}
+
+func runCriteriaLs() error {
+	c := newAPIClient()
+
+	criteria, err := c.ListCriteria()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(criteria)
+	}
+
+	return printCriteriaTable(criteria)
+}
+
+func newCriteriaSetCmd() *cobra.Command {
+	var (
+		weight      int
+		best, worst float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Change a criterion's weight or range",
+		Long: `Change a criterion's weight or, for a computed one, its best and worst
+values. Settings you don't pass are kept.
+
+Examples:
+  hf criteria set kitchen --weight 5
+  hf criteria set price --best 275000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var u score.Update
+			if cmd.Flags().Changed("weight") {
+				u.Weight = &weight
+			}
+			if cmd.Flags().Changed("best") {
+				u.Best = &best
+			}
+			if cmd.Flags().Changed("worst") {
+				u.Worst = &worst
+			}
+			if u == (score.Update{}) {
+				return fmt.Errorf("specify --weight, --best or --worst")
+			}
+			return runCriteriaSet(args[0], u)
+		},
+	}
+
+	cmd.Flags().IntVar(&weight, "weight", 0, "relative importance, 1-10")
+	cmd.Flags().Float64Var(&best, "best", 0, "field value that scores full marks")
+	cmd.Flags().Float64Var(&worst, "worst", 0, "field value that scores nothing")
+
+	return cmd
+}
+
+func runCriteriaSet(name string, u score.Update) error {
+	c := newAPIClient()
+
+	updated, err := c.UpdateCriterion(name, u)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(updated)
+	}
+
+	fmt.Printf("Updated criterion %q (weight %d).\n", updated.Name, updated.Weight)
+	return nil
+}
+
+func newCriteriaRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a criterion and every hand score given for it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCriteriaRm(args[0])
+		},
+	}
+}
+
+func runCriteriaRm(name string) error {
+	c := newAPIClient()
+
+	if err := c.DeleteCriterion(name); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]string{"removed": name})
+	}
+
+	fmt.Printf("Removed criterion %q.\n", name)
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newDedupeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find properties tracked more than once",
+		Long: `List groups of tracked properties that look like the same house.
+
+Addresses are compared after normalizing abbreviations, units and case
+("123 North Main Street Apt 4" matches "123 N Main St #4"), so the same
+house added under a different spelling or a relisted MLS ID shows up here.
+Fold one into another with "hf dedupe merge".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDedupe()
+		},
+	}
+
+	cmd.AddCommand(newDedupeMergeCmd())
+
+	return cmd
+}
+
+func runDedupe() error {
+	c := newAPIClient()
+
+	groups, err := c.ListDuplicates()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(groups)
+	}
+
+	return printDuplicateGroups(groups)
+}
+
+func newDedupeMergeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge <keep-id> <duplicate-id>",
+		Short: "Merge a duplicate into the property you keep",
+		Long: `Move the duplicate's comments, visits, listing history, alerts and
+edits onto the kept property, then remove the duplicate. Each member's
+rating of the duplicate moves over unless they already rated the kept
+property, which takes whichever visit status is further along. Its listing
+data is left as is, so keep the one linked to the current MLS listing.
+
+Example:
+  hf dedupe merge 3 7`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keepID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			dupID, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[1])
+			}
+			if keepID == dupID {
+				return fmt.Errorf("cannot merge a property into itself")
+			}
+			return runDedupeMerge(keepID, dupID)
+		},
+	}
+}
+
+func runDedupeMerge(keepID, dupID int64) error {
+	p, err := newAPIClient().MergeProperty(keepID, dupID)
+	if err != nil {
+		return fmt.Errorf("merging properties: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	fmt.Printf("Merged #%d into #%d.\n", dupID, keepID)
+	printPropertySummary(p)
+	return nil
+}
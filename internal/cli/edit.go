@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// editFlags maps hf edit flags to the listing fields they override.
+var editFlags = []struct {
+	flag, field, usage string
+}{
+	{"price", "price", "asking price in dollars"},
+	{"beds", "bedrooms", "bedrooms"},
+	{"baths", "bathrooms", "bathrooms"},
+	{"sqft", "sqft", "living area in square feet"},
+	{"lot", "lot_size", "lot size in acres"},
+	{"year", "year_built", "year built"},
+	{"type", "property_type", "property type (e.g. single_family)"},
+	{"status", "status", "listing status (e.g. active, pending)"},
+	{"hoa", "hoa_fee", "HOA fee in dollars per month"},
+	{"tax", "annual_tax", "annual property tax in dollars"},
+	{"list-date", "list_date", "list date (YYYY-MM-DD)"},
+	{"sold-price", "last_sold_price", "last sold price in dollars"},
+	{"sold-date", "last_sold_date", "last sold date (YYYY-MM-DD)"},
+	{"lat", "latitude", "latitude"},
+	{"lon", "longitude", "longitude"},
+	{"garage", "garage", "garage spaces"},
+	{"stories", "stories", "number of stories"},
+	{"heating", "heating", "heating description"},
+	{"cooling", "cooling", "cooling description"},
+	{"agent", "listing_agent", "listing agent name"},
+}
+
+func newEditCmd() *cobra.Command {
+	values := make(map[string]*string, len(editFlags))
+	var reset []string
+
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Correct a property's listing details",
+		Long: `Override listing fields parsed from the MLS data.
+
+Overrides are stored separately from the MLS values, so refreshes keep
+updating the listing underneath without clobbering the corrections. hf show
+marks edited fields along with the MLS value. Use --reset to drop an
+override and show the MLS value again.
+
+Examples:
+  hf edit 3 --beds 4 --sqft 1850
+  hf edit 3 --lot 0.25
+  hf edit 3 --hoa 45 --tax 2250
+  hf edit 3 --reset beds,sqft`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+
+			edit := make(property.Edit)
+			for _, ef := range editFlags {
+				if cmd.Flags().Changed(ef.flag) {
+					edit[ef.field] = values[ef.flag]
+				}
+			}
+			for _, name := range reset {
+				field, err := editField(strings.TrimSpace(name))
+				if err != nil {
+					return err
+				}
+				if _, ok := edit[field]; ok {
+					return fmt.Errorf("cannot set and reset %s at once", name)
+				}
+				edit[field] = nil
+			}
+			if len(edit) == 0 {
+				return fmt.Errorf("specify at least one field to edit or --reset")
+			}
+			if err := edit.Validate(); err != nil {
+				return err
+			}
+
+			return runEdit(id, edit)
+		},
+	}
+
+	for _, ef := range editFlags {
+		values[ef.flag] = new(string)
+		cmd.Flags().StringVar(values[ef.flag], ef.flag, "", ef.usage)
+	}
+	cmd.Flags().StringSliceVar(&reset, "reset", nil, "fields to revert to the MLS value (e.g. beds,sqft)")
+
+	return cmd
+}
+
+func runEdit(id int64, edit property.Edit) error {
+	p, err := newAPIClient().EditProperty(id, edit)
+	if err != nil {
+		return fmt.Errorf("editing property: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	printPropertySummary(p)
+	return nil
+}
+
+// editField resolves a --reset name, given as a flag name or a field name.
+func editField(name string) (string, error) {
+	for _, ef := range editFlags {
+		if name == ef.flag || name == ef.field {
+			return ef.field, nil
+		}
+	}
+	return "", fmt.Errorf("unknown field %q", name)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newEventsCmd() *cobra.Command {
+	var (
+		propertyID int64
+		limit      int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "List listing change alerts",
+		Long:  "List detected listing changes such as price drops, pending, back on market, and sold.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEvents(propertyID, limit)
+		},
+	}
+
+	cmd.Flags().Int64Var(&propertyID, "property", 0, "only show events for this property ID")
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum number of events to show")
+
+	return cmd
+}
+
+func runEvents(propertyID int64, limit int) error {
+	c := newAPIClient()
+
+	events, err := c.ListEvents(propertyID, limit)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(events)
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No events.")
+		return nil
+	}
+
+	for _, e := range events {
+		fmt.Printf("[%s] Property #%d: %s\n", e.CreatedAt.Format("2006-01-02"), e.PropertyID, e.Message)
+	}
+	return nil
+}
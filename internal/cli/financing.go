@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// financingFlags maps hf financing set flags to profile JSON fields.
+var financingFlags = []struct {
+	flag, field, usage string
+}{
+	{"down", "down_payment_percent", "down payment, percent of price"},
+	{"rate", "rate_percent", "annual interest rate, percent"},
+	{"term", "term_years", "loan term in years"},
+	{"insurance", "insurance_rate", "homeowner's insurance, percent of price per year"},
+	{"pmi", "pmi_rate", "PMI, percent of the loan per year"},
+	{"pmi-threshold", "pmi_threshold_percent", "down payment percent at which PMI stops"},
+	{"tax-rate", "tax_rate", "property tax, percent of price per year, used when a listing has no tax record"},
+}
+
+func newFinancingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "financing",
+		Short: "Show or change your mortgage assumptions",
+		Long: `Show or change your financing profile: the down payment, rate, term,
+insurance and PMI rules used by "hf cost" and "hf list --max-monthly".
+
+Each user has their own profile. Until you save one, defaults of 20% down
+on a 30-year loan at 6.5% are used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFinancingShow()
+		},
+	}
+
+	cmd.AddCommand(newFinancingSetCmd())
+
+	return cmd
+}
+
+func runFinancingShow() error {
+	c := newAPIClient()
+
+	p, err := c.GetFinancing()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	printFinancing(p)
+	return nil
+}
+
+func newFinancingSetCmd() *cobra.Command {
+	values := make(map[string]*float64, len(financingFlags))
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Change your mortgage assumptions",
+		Long: `Change fields of your financing profile. Fields you don't pass keep
+their current values.
+
+Examples:
+  hf financing set --down 10 --rate 6.25
+  hf financing set --term 15
+  hf financing set --pmi 0.7 --pmi-threshold 20`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			update := make(map[string]interface{})
+			for _, ff := range financingFlags {
+				if cmd.Flags().Changed(ff.flag) {
+					update[ff.field] = *values[ff.flag]
+				}
+			}
+			if len(update) == 0 {
+				return fmt.Errorf("specify at least one field to change")
+			}
+			if term, ok := update["term_years"].(float64); ok {
+				if term != float64(int(term)) {
+					return fmt.Errorf("--term must be a whole number of years")
+				}
+				update["term_years"] = int(term)
+			}
+			return runFinancingSet(update)
+		},
+	}
+
+	for _, ff := range financingFlags {
+		values[ff.flag] = new(float64)
+		cmd.Flags().Float64Var(values[ff.flag], ff.flag, 0, ff.usage)
+	}
+
+	return cmd
+}
+
+func runFinancingSet(update map[string]interface{}) error {
+	p, err := newAPIClient().SaveFinancing(update)
+	if err != nil {
+		return fmt.Errorf("saving financing profile: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	printFinancing(p)
+	return nil
+}
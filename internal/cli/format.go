@@ -8,7 +8,14 @@ import (
 	"text/tabwriter"
 
 	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/compare"
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/jobs"
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
 	"github.com/evcraddock/house-finder/internal/visit"
 )
 
@@ -23,37 +30,99 @@ func printJSON(v interface{}) error {
 func printPropertySummary(p *property.Property) {
 	fmt.Printf("Property #%d\n", p.ID)
 	fmt.Printf("  Address:  %s\n", p.Address)
-	fmt.Printf("  URL:      %s\n", p.RealtorURL)
+	if p.IsManual() {
+		fmt.Printf("  Source:   manual entry (not linked to an MLS listing)\n")
+	} else {
+		fmt.Printf("  URL:      %s\n", p.RealtorURL)
+	}
 	if p.Price != nil {
-		fmt.Printf("  Price:    $%s\n", formatPrice(*p.Price))
+		fmt.Printf("  Price:    $%s%s\n", formatPrice(*p.Price), editedNote(p, "price"))
 	}
 	if p.Bedrooms != nil {
-		fmt.Printf("  Beds:     %g\n", *p.Bedrooms)
+		fmt.Printf("  Beds:     %g%s\n", *p.Bedrooms, editedNote(p, "bedrooms"))
 	}
 	if p.Bathrooms != nil {
-		fmt.Printf("  Baths:    %g\n", *p.Bathrooms)
+		fmt.Printf("  Baths:    %g%s\n", *p.Bathrooms, editedNote(p, "bathrooms"))
 	}
 	if p.Sqft != nil {
-		fmt.Printf("  Sqft:     %d\n", *p.Sqft)
+		fmt.Printf("  Sqft:     %d%s\n", *p.Sqft, editedNote(p, "sqft"))
 	}
 	if p.LotSize != nil {
-		fmt.Printf("  Lot:      %.2f acres\n", *p.LotSize)
+		fmt.Printf("  Lot:      %.2f acres%s\n", *p.LotSize, editedNote(p, "lot_size"))
 	}
 	if p.YearBuilt != nil {
-		fmt.Printf("  Built:    %d\n", *p.YearBuilt)
+		fmt.Printf("  Built:    %d%s\n", *p.YearBuilt, editedNote(p, "year_built"))
 	}
 	if p.PropertyType != nil {
-		fmt.Printf("  Type:     %s\n", *p.PropertyType)
+		fmt.Printf("  Type:     %s%s\n", *p.PropertyType, editedNote(p, "property_type"))
 	}
 	if p.Status != nil {
-		fmt.Printf("  Status:   %s\n", *p.Status)
+		fmt.Printf("  Status:   %s%s\n", *p.Status, editedNote(p, "status"))
+	}
+	if p.ListDate != nil {
+		fmt.Printf("  Listed:   %s%s", *p.ListDate, editedNote(p, "list_date"))
+		if p.DaysOnMarket != nil {
+			fmt.Printf(" (%d days on market)", *p.DaysOnMarket)
+		}
+		fmt.Println()
+	}
+	if p.HOAFee != nil {
+		fmt.Printf("  HOA:      $%s/mo%s\n", formatPrice(*p.HOAFee), editedNote(p, "hoa_fee"))
+	}
+	if p.AnnualTax != nil {
+		fmt.Printf("  Tax:      $%s/yr%s\n", formatPrice(*p.AnnualTax), editedNote(p, "annual_tax"))
+	}
+	if p.LastSoldPrice != nil || p.LastSoldDate != nil {
+		fmt.Printf("  Sold:     %s", textOrDash(p.LastSoldDate))
+		if p.LastSoldPrice != nil {
+			fmt.Printf(" for $%s", formatPrice(*p.LastSoldPrice))
+		}
+		fmt.Printf("%s%s\n", editedNote(p, "last_sold_date"), editedNote(p, "last_sold_price"))
+	}
+	if p.Garage != nil {
+		fmt.Printf("  Garage:   %d%s\n", *p.Garage, editedNote(p, "garage"))
+	}
+	if p.Stories != nil {
+		fmt.Printf("  Stories:  %d%s\n", *p.Stories, editedNote(p, "stories"))
 	}
-	if p.Rating != nil {
-		fmt.Printf("  Rating:   %s\n", formatRating(*p.Rating))
+	if p.Heating != nil {
+		fmt.Printf("  Heating:  %s%s\n", *p.Heating, editedNote(p, "heating"))
+	}
+	if p.Cooling != nil {
+		fmt.Printf("  Cooling:  %s%s\n", *p.Cooling, editedNote(p, "cooling"))
+	}
+	if p.Latitude != nil && p.Longitude != nil {
+		fmt.Printf("  Location: %.6f, %.6f%s%s\n", *p.Latitude, *p.Longitude, editedNote(p, "latitude"), editedNote(p, "longitude"))
+	}
+	for _, d := range p.Distances {
+		fmt.Printf("  To %s: %.1f mi (%s drive)\n", d.Place, d.Miles, d.Drive)
+	}
+	if p.ListingAgent != nil {
+		fmt.Printf("  Agent:    %s%s\n", *p.ListingAgent, editedNote(p, "listing_agent"))
+	}
+	if p.Rating != nil && p.Ratings != nil {
+		fmt.Printf("  Rating:   %s%s\n", formatRating(*p.Rating), formatRatingSpread(p.Ratings))
+		for _, m := range p.Ratings.Members {
+			fmt.Printf("    %s %s\n", formatRating(m.Rating), formatRater(m.Email))
+		}
+	}
+	if p.Score != nil && p.Score.Total != nil {
+		fmt.Printf("  Score:    %s/100\n", formatScore(p.Score.Total))
 	}
 	if p.VisitStatus != "" {
 		fmt.Printf("  Visit:    %s\n", p.VisitStatus)
 	}
+	if len(p.Tags) > 0 {
+		fmt.Printf("  Tags:     %s\n", strings.Join(p.Tags, ", "))
+	}
+}
+
+// editedNote marks a hand-edited field and shows the MLS value it hides.
+func editedNote(p *property.Property, field string) string {
+	if !p.IsOverridden(field) {
+		return ""
+	}
+	return fmt.Sprintf("  (edited; MLS: %s)", textOrDash(p.Overrides[field]))
 }
 
 // printPropertyTable prints a list of properties as a formatted table.
@@ -63,11 +132,29 @@ func printPropertyTable(props []*property.Property) error {
 		return nil
 	}
 
+	// Only show the score, distance and tag columns once some house has them.
+	var showScore, showDistance, showTags bool
+	for _, p := range props {
+		showScore = showScore || p.Score != nil
+		showDistance = showDistance || len(p.Distances) > 0
+		showTags = showTags || len(p.Tags) > 0
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	if _, err := fmt.Fprintln(w, "ID\tADDRESS\tPRICE\tBED\tBATH\tSQFT\tRATING"); err != nil {
+	header, sep := "ID\tADDRESS\tPRICE\tBED\tBATH\tSQFT\tRATING", "--\t-------\t-----\t---\t----\t----\t------"
+	if showScore {
+		header, sep = header+"\tSCORE", sep+"\t-----"
+	}
+	if showDistance {
+		header, sep = header+"\tDISTANCE", sep+"\t--------"
+	}
+	if showTags {
+		header, sep = header+"\tTAGS", sep+"\t----"
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
 		return fmt.Errorf("writing table header: %w", err)
 	}
-	if _, err := fmt.Fprintln(w, "--\t-------\t-----\t---\t----\t----\t------"); err != nil {
+	if _, err := fmt.Fprintln(w, sep); err != nil {
 		return fmt.Errorf("writing table separator: %w", err)
 	}
 
@@ -92,9 +179,26 @@ func printPropertyTable(props []*property.Property) error {
 		if p.Rating != nil {
 			rating = formatRating(*p.Rating)
 		}
+		if p.Ratings != nil && p.Ratings.Split() {
+			rating += " split"
+		}
 
-		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			p.ID, truncate(p.Address, 40), price, beds, baths, sqft, rating); err != nil {
+		row := fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\t%s",
+			p.ID, truncate(p.Address, 40), price, beds, baths, sqft, rating)
+		if showScore {
+			var total *float64
+			if p.Score != nil {
+				total = p.Score.Total
+			}
+			row += "\t" + formatScore(total)
+		}
+		if showDistance {
+			row += "\t" + formatDistances(p.Distances)
+		}
+		if showTags {
+			row += "\t" + strings.Join(p.Tags, ", ")
+		}
+		if _, err := fmt.Fprintln(w, row); err != nil {
 			return fmt.Errorf("writing table row: %w", err)
 		}
 	}
@@ -107,6 +211,478 @@ func printPropertyTable(props []*property.Property) error {
 	return nil
 }
 
+// printCacheTable prints cached MLS responses as a formatted table.
+func printCacheTable(entries []*mls.CacheEntry) error {
+	if len(entries) == 0 {
+		fmt.Println("Cache is empty.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "MPR ID\tFETCHED\tEXPIRES\tSIZE\tURL"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, e := range entries {
+		expires := e.ExpiresAt.Local().Format("2006-01-02 15:04")
+		if e.Expired {
+			expires = "expired"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d KB\t%s\n",
+			e.MprID, e.FetchedAt.Local().Format("2006-01-02 15:04"), expires,
+			(e.Bytes+1023)/1024, truncate(e.RealtorURL, 60)); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// printPlaceTable prints named places as a formatted table.
+func printPlaceTable(places []*place.Place) error {
+	if len(places) == 0 {
+		fmt.Println("No places yet. Add one with: hf place add <name> <lat> <lon>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tLATITUDE\tLONGITUDE"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, p := range places {
+		if _, err := fmt.Fprintf(w, "%s\t%g\t%g\n", p.Name, p.Latitude, p.Longitude); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// printScore prints a property's score and its breakdown by criterion.
+func printScore(id int64, s *score.Score) error {
+	if len(s.Parts) == 0 {
+		fmt.Println("No scoring criteria yet. Add one with: hf criteria add <name>")
+		return nil
+	}
+
+	fmt.Printf("Property %d score: %s/100\n\n", id, formatScore(s.Total))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "CRITERION\tWEIGHT\tVALUE\tSCORE"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, p := range s.Parts {
+		value := "-"
+		if p.Value != nil {
+			value = fmt.Sprintf("%g", *p.Value)
+		}
+		if p.Manual {
+			value += " (by hand)"
+		}
+		share := "-"
+		if p.Score != nil {
+			share = fmt.Sprintf("%.0f%%", *p.Score*100)
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", p.Criterion, p.Weight, value, share); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+func printCriteriaTable(criteria []*score.Criterion) error {
+	if len(criteria) == 0 {
+		fmt.Println("No scoring criteria yet. Add one with: hf criteria add <name>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tWEIGHT\tSCORED FROM\tBEST\tWORST"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, c := range criteria {
+		from, best, worst := "hand (1-5)", "-", "-"
+		if !c.Manual() {
+			from = c.Field
+			if c.Field == score.FieldDistance {
+				from = "distance to " + c.Place
+			}
+			best, worst = fmt.Sprintf("%g", *c.Best), fmt.Sprintf("%g", *c.Worst)
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", c.Name, c.Weight, from, best, worst); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// formatScore formats a 0-100 total score, or "-" when unknown.
+func formatScore(total *float64) string {
+	if total == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", *total)
+}
+
+func printTagTable(tags []*tag.Tag) error {
+	if len(tags) == 0 {
+		fmt.Println("No tags yet. Add one with: hf tag add <id> <tag>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "TAG\tPROPERTIES"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, t := range tags {
+		if _, err := fmt.Fprintf(w, "%s\t%d\n", t.Name, t.Count); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// printDuplicates lists tracked properties that look like the same house
+// as one being added.
+func printDuplicates(dups []property.Duplicate) {
+	fmt.Println("This looks like a house you already track:")
+	for _, d := range dups {
+		fmt.Printf("  #%d  %s\n", d.ID, d.Address)
+	}
+}
+
+// printDuplicateGroups prints each group of likely duplicates.
+func printDuplicateGroups(groups []*property.DuplicateGroup) error {
+	if len(groups) == 0 {
+		fmt.Println("No duplicates found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, g := range groups {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return fmt.Errorf("writing table row: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", g.Address); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+		for _, p := range g.Properties {
+			rating := "-"
+			if p.Rating != nil {
+				rating = formatRating(*p.Rating)
+			}
+			if _, err := fmt.Fprintf(w, "  #%d\t%s\t%s\t%s\t%s\n",
+				p.ID, truncate(p.Address, 50), p.MprID, rating, p.CreatedAt.Local().Format("2006-01-02")); err != nil {
+				return fmt.Errorf("writing table row: %w", err)
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// printComparison prints properties side by side, one row per field,
+// then each property's latest comments.
+func printComparison(c *compare.Comparison) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	ids, addresses := "", ""
+	for _, p := range c.Properties {
+		ids += fmt.Sprintf("\t#%d", p.ID)
+		addresses += "\t" + truncate(p.Address, 30)
+	}
+	if _, err := fmt.Fprintln(w, ids); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, addresses); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	for _, r := range c.Rows {
+		row := r.Label
+		for i, v := range r.Values {
+			if v == "" {
+				v = "-"
+			}
+			switch r.Mark(i) {
+			case "best":
+				v += " ▲"
+			case "worst":
+				v += " ▼"
+			}
+			row += "\t" + v
+		}
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	fmt.Println("\n▲ best  ▼ worst")
+
+	for i, p := range c.Properties {
+		if i >= len(c.Comments) || len(c.Comments[i]) == 0 {
+			continue
+		}
+		fmt.Printf("\nLatest comments on #%d %s:\n", p.ID, p.Address)
+		for _, cm := range c.Comments[i] {
+			fmt.Printf("  [%s] %s\n", cm.CreatedAt.Format("2006-01-02"), truncate(cm.Text, 100))
+		}
+	}
+	return nil
+}
+
+// printSearchResults prints each matching property with its snippet,
+// matched words in brackets.
+func printSearchResults(results []*property.SearchResult) {
+	if len(results) == 0 {
+		fmt.Println("No matches.")
+		return
+	}
+
+	for _, r := range results {
+		p := r.Property
+		price := "-"
+		if p.Price != nil {
+			price = "$" + formatPrice(*p.Price)
+		}
+		fmt.Printf("#%d  %s  %s\n  %s\n\n", p.ID, p.Address, price, formatSnippet(r.Snippet))
+	}
+}
+
+// formatSnippet puts a search snippet on one line, matched words in
+// brackets.
+func formatSnippet(parts []property.SnippetPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Match {
+			b.WriteString("[" + part.Text + "]")
+		} else {
+			b.WriteString(part.Text)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// printImportResults prints one line per row of an import.
+func printImportResults(results []property.ImportResult) {
+	for _, res := range results {
+		id := ""
+		if res.Property != nil {
+			id = fmt.Sprintf("#%d", res.Property.ID)
+		}
+		line := fmt.Sprintf("  row %-4d %-8s %-6s %s", res.Row, res.Status, id, truncate(res.Address, 50))
+		if res.Error != "" {
+			line += " (" + res.Error + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+// printUsage prints this month's paid calls against the budget, then
+// listing API totals by month, day and user.
+func printUsage(r *mls.UsageReport) error {
+	if r.MonthlyBudget > 0 {
+		fmt.Printf("RapidAPI calls in %s: %d of %d budgeted (%d left)\n",
+			r.Month, r.PaidThisMonth, r.MonthlyBudget, max(r.MonthlyBudget-r.PaidThisMonth, 0))
+	} else {
+		fmt.Printf("RapidAPI calls in %s: %d (no budget set)\n", r.Month, r.PaidThisMonth)
+	}
+	if len(r.Monthly) == 0 {
+		fmt.Println("\nNo listing API calls recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	sections := []struct {
+		title  string
+		totals []mls.UsageTotal
+	}{
+		{"MONTH", r.Monthly},
+		{"DAY", r.Daily},
+	}
+	for _, sec := range sections {
+		if _, err := fmt.Fprintf(w, "\n%s\tCALLS\tRAPIDAPI\tFAILED\tAVG MS\n", sec.title); err != nil {
+			return fmt.Errorf("writing table header: %w", err)
+		}
+		for _, t := range sec.totals {
+			if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", t.Period, t.Calls, t.Paid, t.Failed, t.AvgLatencyMS); err != nil {
+				return fmt.Errorf("writing table row: %w", err)
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\nUSER (%s)\tCALLS\tRAPIDAPI\n", r.Month); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+	for _, u := range r.Users {
+		user := u.User
+		if user == "" {
+			user = "(no auth)"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\n", user, u.Calls, u.Paid); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	return nil
+}
+
+// printTrashTable prints properties in the trash with when each was removed.
+func printTrashTable(props []*property.Property) error {
+	if len(props) == 0 {
+		fmt.Println("The trash is empty.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "ID\tADDRESS\tPRICE\tRATING\tREMOVED"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, "--\t-------\t-----\t------\t-------"); err != nil {
+		return fmt.Errorf("writing table separator: %w", err)
+	}
+
+	for _, p := range props {
+		price, rating, removed := "-", "-", "-"
+		if p.Price != nil {
+			price = "$" + formatPrice(*p.Price)
+		}
+		if p.Rating != nil {
+			rating = formatRating(*p.Rating)
+		}
+		if p.DeletedAt != nil {
+			removed = p.DeletedAt.Local().Format("2006-01-02 15:04")
+		}
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", p.ID, truncate(p.Address, 40), price, rating, removed); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+
+	fmt.Printf("\nTotal: %d in the trash. Restore with: hf restore <id>\n", len(props))
+	return nil
+}
+
+// printJobTable prints background jobs as a formatted table, with the
+// last error of any failed job below it.
+func printJobTable(statuses []jobs.Status) error {
+	if len(statuses) == 0 {
+		fmt.Println("No background jobs.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "JOB\tEVERY\tLAST RUN\tRESULT\tTOOK\tRUNS\tFAILED\tNEXT RUN"); err != nil {
+		return fmt.Errorf("writing table header: %w", err)
+	}
+
+	var failed []jobs.Status
+	for _, st := range statuses {
+		every, next := "off", "—"
+		if st.Enabled {
+			every = st.Interval
+		}
+		if st.NextRun != nil {
+			next = st.NextRun.Local().Format("2006-01-02 15:04")
+		}
+		last, result, took := "never", "—", "—"
+		if st.LastStarted != nil {
+			last = st.LastStarted.Local().Format("2006-01-02 15:04")
+		}
+		if st.LastStatus != "" {
+			result = st.LastStatus
+			took = fmt.Sprintf("%dms", st.LastDurationMS)
+		}
+		if st.Running {
+			result = "running"
+		}
+		if st.LastStatus == jobs.StatusFailed {
+			failed = append(failed, st)
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			st.Name, every, last, result, took, st.Runs, st.Failures, next); err != nil {
+			return fmt.Errorf("writing table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flushing table: %w", err)
+	}
+	for _, st := range failed {
+		fmt.Printf("\n%s: %s\n", st.Name, st.LastError)
+	}
+	return nil
+}
+
+// printCost prints a monthly cost breakdown and the assumptions behind it.
+func printCost(p *finance.Profile, c finance.Cost) {
+	fmt.Printf("Monthly cost: $%s\n", formatPrice(c.Total))
+	fmt.Printf("  Principal & interest: $%s\n", formatPrice(c.PrincipalInterest))
+	taxNote := ""
+	if c.TaxesEstimated {
+		taxNote = fmt.Sprintf(" (estimated at %g%%, no tax record)", p.TaxRate)
+	}
+	fmt.Printf("  Taxes:                $%s%s\n", formatPrice(c.Taxes), taxNote)
+	fmt.Printf("  Insurance:            $%s\n", formatPrice(c.Insurance))
+	if c.PMI > 0 {
+		fmt.Printf("  PMI:                  $%s\n", formatPrice(c.PMI))
+	}
+	if c.HOA > 0 {
+		fmt.Printf("  HOA:                  $%s\n", formatPrice(c.HOA))
+	}
+	fmt.Printf("\n$%s down (%g%%) on $%s, $%s loan at %g%% for %d years.\n",
+		formatPrice(c.DownPayment), p.DownPaymentPercent, formatPrice(c.Price),
+		formatPrice(c.LoanAmount), p.RatePercent, p.TermYears)
+}
+
+// printFinancing prints a financing profile.
+func printFinancing(p *finance.Profile) {
+	fmt.Println("Financing profile")
+	fmt.Printf("  Down payment:  %g%%\n", p.DownPaymentPercent)
+	fmt.Printf("  Rate:          %g%%\n", p.RatePercent)
+	fmt.Printf("  Term:          %d years\n", p.TermYears)
+	fmt.Printf("  Insurance:     %g%% of price per year\n", p.InsuranceRate)
+	fmt.Printf("  PMI:           %g%% of loan per year, below %g%% down\n", p.PMIRate, p.PMIThresholdPercent)
+	fmt.Printf("  Tax rate:      %g%% of price per year (when a listing has no tax record)\n", p.TaxRate)
+	if p.UpdatedAt.IsZero() {
+		fmt.Println("\nThese are the defaults. Change them with: hf financing set")
+	}
+}
+
 // printCommentList prints comments in text format.
 func printCommentList(comments []*comment.Comment) {
 	if len(comments) == 0 {
@@ -131,21 +707,28 @@ func printCommentSingle(c *comment.Comment) {
 
 // formatPrice formats a dollar amount as a string with commas.
 func formatPrice(dollars int64) string {
-	s := fmt.Sprintf("%d", dollars)
+	return property.FormatDollars(dollars)
+}
 
-	// Add commas
-	if len(s) <= 3 {
-		return s
+// formatRatingSpread describes how the household's ratings compare, or
+// returns "" when only one member has rated.
+func formatRatingSpread(r *property.Ratings) string {
+	if len(r.Members) < 2 {
+		return ""
 	}
-
-	var parts []string
-	for len(s) > 3 {
-		parts = append([]string{s[len(s)-3:]}, parts...)
-		s = s[:len(s)-3]
+	spread := fmt.Sprintf(" (average %.1f, lowest %d", r.Average, r.Min)
+	if r.Split() {
+		spread += ", split"
 	}
-	parts = append([]string{s}, parts...)
+	return spread + ")"
+}
 
-	return strings.Join(parts, ",")
+// formatRater names the member who gave a rating.
+func formatRater(email string) string {
+	if email == "" {
+		return "(shared, from before per-member ratings)"
+	}
+	return email
 }
 
 // formatRating returns a star representation of a rating (1-4).
@@ -175,6 +758,48 @@ func printVisits(visits []*visit.Visit) {
 	}
 }
 
+// printRefreshResult prints the changes found by a refresh in text format.
+func printRefreshResult(res *property.RefreshResult) {
+	if len(res.Changes) == 0 {
+		fmt.Printf("Property #%d: no changes\n", res.Property.ID)
+		return
+	}
+
+	fmt.Printf("Property #%d: %d changed\n", res.Property.ID, len(res.Changes))
+	for _, c := range res.Changes {
+		fmt.Printf("  %s: %s → %s\n", c.Field, textOrDash(c.OldValue), textOrDash(c.NewValue))
+	}
+}
+
+// printHistory prints recorded listing changes in text format.
+func printHistory(history []*property.Snapshot) {
+	for _, s := range history {
+		fmt.Printf("[%s] %s: %s → %s\n",
+			s.CreatedAt.Format("2006-01-02"), s.Field, textOrDash(s.OldValue), textOrDash(s.NewValue))
+	}
+	fmt.Println()
+}
+
+// textOrDash dereferences s, returning "-" for nil.
+// formatDistances renders distances compactly, e.g. "work 12.3mi, school 4.1mi".
+func formatDistances(distances []place.Distance) string {
+	if len(distances) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(distances))
+	for i, d := range distances {
+		parts[i] = fmt.Sprintf("%s %.1fmi", d.Place, d.Miles)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func textOrDash(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
+
 // truncate shortens a string to maxLen, adding "..." if truncated.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
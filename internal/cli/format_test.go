@@ -1,6 +1,10 @@
 package cli
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
 
 func TestFormatPrice(t *testing.T) {
 	tests := []struct {
@@ -48,6 +52,43 @@ func TestFormatRating(t *testing.T) {
 	}
 }
 
+func TestFormatRatingSpread(t *testing.T) {
+	one := []property.MemberRating{{Email: "a@example.com", Rating: 3}}
+	two := append(one, property.MemberRating{Email: "b@example.com", Rating: 1})
+	tests := []struct {
+		name     string
+		ratings  property.Ratings
+		expected string
+	}{
+		{"one member", property.Ratings{Members: one, Average: 3, Min: 3}, ""},
+		{"agree", property.Ratings{Members: two, Average: 2.5, Min: 2, Spread: 1}, " (average 2.5, lowest 2)"},
+		{"split", property.Ratings{Members: two, Average: 2, Min: 1, Spread: 2}, " (average 2.0, lowest 1, split)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatRatingSpread(&tt.ratings)
+			if result != tt.expected {
+				t.Errorf("formatRatingSpread() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatSnippet(t *testing.T) {
+	parts := []property.SnippetPart{
+		{Text: "…huge "},
+		{Text: "finished", Match: true},
+		{Text: " "},
+		{Text: "basement", Match: true},
+		{Text: "\nNeeds a   roof"},
+	}
+	expected := "…huge [finished] [basement] Needs a roof"
+	if result := formatSnippet(parts); result != expected {
+		t.Errorf("formatSnippet() = %q, want %q", result, expected)
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/client"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// importChunkSize is how many rows hf import sends per request, so each
+// request finishes well inside the client timeout and progress shows as
+// it goes.
+const importChunkSize = 10
+
+// importColumns maps accepted CSV header names to ImportRow fields.
+var importColumns = map[string]string{
+	"address":      "address",
+	"rating":       "rating",
+	"status":       "visit_status",
+	"visit_status": "visit_status",
+	"comment":      "comment",
+	"comments":     "comment",
+	"notes":        "comment",
+}
+
+func newImportCmd() *cobra.Command {
+	var noCache bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Add many properties from a CSV or text file",
+		Long: `Add every address in a file, as if running hf add for each.
+
+The file is either plain text with one address (or realtor.com URL, or
+property ID) per line, or a CSV whose header row names an "address"
+column. A CSV may also have "rating" (1-4), "status" (not_visited,
+want_to_visit, visited) and "comment" columns, applied to each house as it
+is added. Blank lines and lines starting with # are ignored. Use - to read
+from stdin.
+
+The server looks listings up a few at a time to stay within RapidAPI rate
+limits. Houses already tracked, or listed twice in the file, are skipped
+without a lookup. Each row's outcome is reported under its line number in
+the file; one bad address doesn't stop the rest.
+
+Examples:
+  hf import addresses.txt
+  hf import showings.csv
+  pbpaste | hf import -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rows, err := readImportFile(args[0])
+			if err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return fmt.Errorf("no addresses found in %s", args[0])
+			}
+			return runImport(rows, noCache)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "ignore cached listing data and make fresh RapidAPI calls")
+
+	return cmd
+}
+
+func runImport(rows []property.ImportRow, noCache bool) error {
+	c := newAPIClient()
+
+	if !isJSON() {
+		fmt.Printf("Importing %d addresses...\n", len(rows))
+	}
+
+	total := client.ImportResponse{Results: make([]property.ImportResult, 0, len(rows))}
+	for start := 0; start < len(rows); start += importChunkSize {
+		end := min(start+importChunkSize, len(rows))
+		resp, err := c.ImportProperties(rows[start:end], noCache)
+		if err != nil {
+			return fmt.Errorf("importing rows %d-%d: %w", rows[start].Row, rows[end-1].Row, err)
+		}
+
+		total.Added += resp.Added
+		total.Skipped += resp.Skipped
+		total.Failed += resp.Failed
+		total.Results = append(total.Results, resp.Results...)
+		if !isJSON() {
+			printImportResults(resp.Results)
+		}
+	}
+
+	if isJSON() {
+		return printJSON(total)
+	}
+
+	fmt.Printf("\nAdded %d, skipped %d, failed %d.\n", total.Added, total.Skipped, total.Failed)
+	return nil
+}
+
+// readImportFile reads import rows from path, or stdin for "-".
+func readImportFile(path string) ([]property.ImportRow, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return parseImport(string(data))
+}
+
+// parseImport reads a CSV with an address header, or else one address
+// per line. Each row is numbered by its line in the input.
+func parseImport(data string) ([]property.ImportRow, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	for _, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if header, err := csv.NewReader(strings.NewReader(line)).Read(); err == nil && csvColumns(header) != nil {
+			return parseImportCSV(data)
+		}
+		break
+	}
+
+	var rows []property.ImportRow
+	for i, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rows = append(rows, property.ImportRow{Row: i + 1, Address: line})
+	}
+	return rows, nil
+}
+
+// csvColumns maps each header field to its ImportRow field, or returns
+// nil if the header has no address column.
+func csvColumns(header []string) []string {
+	cols := make([]string, len(header))
+	hasAddress := false
+	for i, name := range header {
+		cols[i] = importColumns[strings.ToLower(strings.TrimSpace(name))]
+		if cols[i] == "address" {
+			hasAddress = true
+		}
+	}
+	if !hasAddress {
+		return nil
+	}
+	return cols
+}
+
+func parseImportCSV(data string) ([]property.ImportRow, error) {
+	cr := csv.NewReader(strings.NewReader(data))
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	cols := csvColumns(header)
+
+	var rows []property.ImportRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		row := property.ImportRow{Row: line}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if i >= len(cols) || value == "" {
+				continue
+			}
+			switch cols[i] {
+			case "address":
+				row.Address = value
+			case "rating":
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid rating %q", line, value)
+				}
+				row.Rating = &n
+			case "visit_status":
+				row.VisitStatus = value
+			case "comment":
+				row.Comment = value
+			}
+		}
+		if row.Address == "" && row.Rating == nil && row.VisitStatus == "" && row.Comment == "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
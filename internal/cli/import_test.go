@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestParseImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string // address per row
+		rows    []int
+		wantErr bool
+	}{
+		{
+			name: "plain text",
+			data: "# from the realtor\n123 Main St, Yukon, OK 73099\n\n456 Oak Ave, Mustang, OK\r\nM1234567890\n",
+			want: []string{"123 Main St, Yukon, OK 73099", "456 Oak Ave, Mustang, OK", "M1234567890"},
+			rows: []int{2, 4, 5},
+		},
+		{
+			name: "csv with header",
+			data: "Address,Rating,Status,Notes\n\"123 Main St, Yukon, OK\",3,want_to_visit,big yard\n\n456 Oak Ave,,,\n",
+			want: []string{"123 Main St, Yukon, OK", "456 Oak Ave"},
+			rows: []int{2, 4},
+		},
+		{
+			name:    "csv with bad rating",
+			data:    "address,rating\n123 Main St,great\n",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			data:    "address\n\"123 Main St\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImport(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, row := range rows {
+				if row.Address != tt.want[i] || row.Row != tt.rows[i] {
+					t.Errorf("rows[%d] = line %d %q, want line %d %q", i, row.Row, row.Address, tt.rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	rows, err := parseImport("address,rating,status,comment\n1 Elm St,4,visited,loved it\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	r := rows[0]
+	if r.Rating == nil || *r.Rating != 4 || r.VisitStatus != "visited" || r.Comment != "loved it" {
+		t.Errorf("row = %+v, want rating 4, visited, comment", r)
+	}
+}
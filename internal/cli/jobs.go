@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newJobsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "jobs",
+		Short: "Show the server's background jobs (admin)",
+		Long: `Show each background job the server runs, how often, when it next
+runs, and how its last run went.
+
+Jobs: cleanup removes expired login links and sessions (every hour by
+default, HF_CLEANUP_INTERVAL); trash-purge deletes houses that have been
+in the trash longer than HF_TRASH_RETENTION (daily, HF_PURGE_INTERVAL);
+listing-refresh re-fetches active listings and emails alerts
+(HF_WATCH_INTERVAL); digest emails alerts not yet sent
+(HF_DIGEST_INTERVAL). The listing jobs run daily and hourly by default
+when SMTP is configured, and are off without it. Requires the admin
+account.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runJobs()
+		},
+	}
+}
+
+func runJobs() error {
+	statuses, err := newAPIClient().Jobs()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(statuses)
+	}
+
+	return printJobTable(statuses)
+}
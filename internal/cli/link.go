@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newLinkCmd() *cobra.Command {
+	var noCache bool
+
+	cmd := &cobra.Command{
+		Use:   "link <id> <address|url|mpr_id>",
+		Short: "Link a manually entered property to an MLS listing",
+		Long: `Attach a property added with --manual to its realtor.com listing.
+
+The listing data replaces the manual details, every changed field is
+recorded in the property's history, and the property is refreshed and
+watched like any other from then on. Comments, visits, and ratings are
+kept.
+
+Examples:
+  hf link 12 "789 Elm St, Yukon, OK 73099"
+  hf link 12 M75364-50927`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runLink(id, strings.Join(args[1:], " "), noCache)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "ignore cached listing data and make a fresh RapidAPI call")
+
+	return cmd
+}
+
+func runLink(id int64, ref string, noCache bool) error {
+	res, err := newAPIClient().LinkProperty(id, ref, noCache)
+	if err != nil {
+		return fmt.Errorf("linking property: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(res)
+	}
+
+	fmt.Printf("Linked to MLS listing %s.\n", res.Property.MprID)
+	printRefreshResult(res)
+	return nil
+}
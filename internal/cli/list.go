@@ -1,30 +1,141 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/evcraddock/house-finder/internal/client"
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/property"
 )
 
 func newListCmd() *cobra.Command {
 	var (
 		minRating   int
+		ratingBy    string
 		visitStatus string
+		maxDistance []string
+		maxMonthly  int64
+		tags        []string
+		sortBy      string
+		archived    bool
+		filters     client.ListOptions
+
+		minPrice, maxPrice int64
+		minBeds, maxBeds   float64
+		minBaths, maxBaths float64
+		minSqft, maxSqft   int64
+		minYear, maxYear   int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all properties",
-		Long:  "List all tracked properties, optionally filtered by rating or visit status.",
-		Args:  cobra.NoArgs,
+		Long: `List all tracked properties, optionally filtered by rating, visit status,
+tag (see "hf tag"), distance to a place (see "hf place"), estimated
+monthly cost under your financing profile (see "hf financing"), or
+listing details: price, beds, baths, square feet, year built, property
+type, listing status, city and ZIP. Archived houses are left out unless
+--archived is given; removed ones are listed by "hf trash".
+
+--rating filters on the household's average rating, rounded to whole
+stars; add --rating-by me to filter on your own rating instead.
+
+Listing filters use hand-corrected values where there are any. A house
+missing a value is left out by any range on it. --type, --listing-status,
+--city and --zip can be repeated to match any of several values.
+
+--sort takes one or more comma-separated keys, each optionally followed
+by :asc or :desc; later keys break ties. Without a direction a key sorts
+best first: cheapest, biggest, newest, highest rated. Houses missing a
+value come last. Keys: ` + strings.Join(property.SortKeys(), ", ") + `.
+
+Examples:
+  hf list --rating 3
+  hf list --rating 3 --rating-by me
+  hf list --max-distance work=15mi
+  hf list --max-distance work=15mi --max-distance school=5km
+  hf list --max-monthly 2500
+  hf list --tag needs-roof --tag big-yard
+  hf list --min-beds 3 --min-baths 2 --max-price 350000
+  hf list --type single_family --city Edmond --city Norman
+  hf list --listing-status for_sale --zip 73034
+  hf list --sort score
+  hf list --sort price_per_sqft,year_built:desc
+  hf list --archived`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts := client.ListOptions{MinRating: minRating, VisitStatus: visitStatus}
+			for _, md := range maxDistance {
+				if _, err := place.ParseLimit(md); err != nil {
+					return err
+				}
+			}
+			if maxMonthly < 0 {
+				return fmt.Errorf("--max-monthly must be a positive dollar amount")
+			}
+			if _, err := property.ParseSort(sortBy); err != nil {
+				return fmt.Errorf("--sort: %w", err)
+			}
+			// Only flags actually given set a bound, so --min-beds 0 or
+			// --max-price 0 mean what they say.
+			for _, r := range []struct {
+				name     string
+				min, max float64
+				rng      *property.Range
+			}{
+				{"price", float64(minPrice), float64(maxPrice), &filters.Price},
+				{"beds", minBeds, maxBeds, &filters.Beds},
+				{"baths", minBaths, maxBaths, &filters.Baths},
+				{"sqft", float64(minSqft), float64(maxSqft), &filters.Sqft},
+				{"year", float64(minYear), float64(maxYear), &filters.YearBuilt},
+			} {
+				if r.min < 0 || r.max < 0 {
+					return fmt.Errorf("--min-%s and --max-%s must be 0 or more", r.name, r.name)
+				}
+				if cmd.Flags().Changed("min-" + r.name) {
+					r.rng.Min = &r.min
+				}
+				if cmd.Flags().Changed("max-" + r.name) {
+					r.rng.Max = &r.max
+				}
+			}
+			if ratingBy != "me" && ratingBy != "household" {
+				return fmt.Errorf("--rating-by must be me or household")
+			}
+			opts := filters
+			opts.MinRating, opts.RatingBy, opts.VisitStatus = minRating, ratingBy, visitStatus
+			opts.MaxDistance, opts.MaxMonthly, opts.Tags, opts.Sort = maxDistance, maxMonthly, tags, sortBy
+			if archived {
+				opts.State = "archived"
+			}
 			return runList(opts)
 		},
 	}
 
 	cmd.Flags().IntVar(&minRating, "rating", 0, "minimum rating to filter by (1-4)")
+	cmd.Flags().StringVar(&ratingBy, "rating-by", "household", "whose rating --rating applies to: me or household (the average)")
 	cmd.Flags().StringVar(&visitStatus, "status", "", "filter by visit status (not_visited, want_to_visit, visited)")
+	cmd.Flags().Int64Var(&maxMonthly, "max-monthly", 0, "only houses whose estimated monthly cost is at most this many dollars")
+	cmd.Flags().StringVar(&sortBy, "sort", "", "order by comma-separated keys, each optionally :asc or :desc, e.g. price,sqft:desc; default is average rating, then newest")
+	cmd.Flags().BoolVar(&archived, "archived", false, "list archived houses instead of active ones")
+	cmd.Flags().StringArrayVar(&maxDistance, "max-distance", nil, "only houses within a distance of a place, e.g. work=15mi or school=5km (repeatable)")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only houses with this tag (repeatable; all must match)")
+	cmd.Flags().Int64Var(&minPrice, "min-price", 0, "only houses listed at this many dollars or more")
+	cmd.Flags().Int64Var(&maxPrice, "max-price", 0, "only houses listed at this many dollars or less")
+	cmd.Flags().Float64Var(&minBeds, "min-beds", 0, "only houses with at least this many bedrooms")
+	cmd.Flags().Float64Var(&maxBeds, "max-beds", 0, "only houses with at most this many bedrooms")
+	cmd.Flags().Float64Var(&minBaths, "min-baths", 0, "only houses with at least this many bathrooms")
+	cmd.Flags().Float64Var(&maxBaths, "max-baths", 0, "only houses with at most this many bathrooms")
+	cmd.Flags().Int64Var(&minSqft, "min-sqft", 0, "only houses with at least this many square feet")
+	cmd.Flags().Int64Var(&maxSqft, "max-sqft", 0, "only houses with at most this many square feet")
+	cmd.Flags().IntVar(&minYear, "min-year", 0, "only houses built in or after this year")
+	cmd.Flags().IntVar(&maxYear, "max-year", 0, "only houses built in or before this year")
+	cmd.Flags().StringArrayVar(&filters.Types, "type", nil, "only houses of this property type, e.g. single_family or condo (repeatable; any may match)")
+	cmd.Flags().StringArrayVar(&filters.ListingStatuses, "listing-status", nil, "only houses with this listing status, e.g. for_sale or pending (repeatable; any may match)")
+	cmd.Flags().StringArrayVar(&filters.Cities, "city", nil, "only houses in this city (repeatable; any may match)")
+	cmd.Flags().StringArrayVar(&filters.Zips, "zip", nil, "only houses in this ZIP code (repeatable; any may match)")
 
 	return cmd
 }
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListSendsZeroBounds(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Encode()
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte("[]")); err != nil {
+			t.Errorf("write: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HF_API_KEY", "hf_testapikey1234567890")
+	t.Setenv("HF_SERVER_URL", srv.URL)
+
+	if _, err := executeCommand("list", "--max-price", "0", "--min-beds", "0", "--min-baths", "2"); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	// Flags left unset send nothing; ones given as 0 are real bounds.
+	if want := "max_price=0&min_baths=2&min_beds=0&rating_by=household"; query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+}
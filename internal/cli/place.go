@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newPlaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "place",
+		Short: "Manage the places you want to live near",
+		Long: `Manage named places (work, school, family) shared by the household.
+
+Every property shows its straight-line distance and an estimated drive
+time band to each place, and "hf list --max-distance" filters on them.`,
+	}
+
+	cmd.AddCommand(newPlaceAddCmd(), newPlaceLsCmd(), newPlaceRmCmd())
+
+	return cmd
+}
+
+func newPlaceAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <name> <latitude> <longitude>",
+		Short: "Add a named place",
+		Long: `Add a named place by its coordinates.
+
+Examples:
+  hf place add work 35.4676 -97.5164
+  hf place add "mom's house" 35.6528 -97.4781`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lat, err := strconv.ParseFloat(args[1], 64)
+			if err != nil {
+				return fmt.Errorf("invalid latitude: %s", args[1])
+			}
+			lon, err := strconv.ParseFloat(args[2], 64)
+			if err != nil {
+				return fmt.Errorf("invalid longitude: %s", args[2])
+			}
+			return runPlaceAdd(args[0], lat, lon)
+		},
+	}
+}
+
+func runPlaceAdd(name string, lat, lon float64) error {
+	c := newAPIClient()
+
+	p, err := c.AddPlace(name, lat, lon)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	fmt.Printf("Added place %q (%g, %g).\n", p.Name, p.Latitude, p.Longitude)
+	return nil
+}
+
+func newPlaceLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List places",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlaceLs()
+		},
+	}
+}
+
+func runPlaceLs() error {
+	c := newAPIClient()
+
+	places, err := c.ListPlaces()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(places)
+	}
+
+	return printPlaceTable(places)
+}
+
+func newPlaceRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a place",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlaceRm(args[0])
+		},
+	}
+}
+
+func runPlaceRm(name string) error {
+	c := newAPIClient()
+
+	if err := c.DeletePlace(name); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]string{"removed": name})
+	}
+
+	fmt.Printf("Removed place %q.\n", name)
+	return nil
+}
@@ -11,9 +11,10 @@ func newRateCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "rate <id> <1-4>",
 		Short: "Rate a property",
-		Long:  "Set a rating (1-4) for a property. 4 is best.",
-		Args:  cobra.ExactArgs(2),
-		RunE:  runRate,
+		Long: `Set your rating (1-4) for a property. 4 is best. Each household member
+keeps their own rating; lists show the average.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runRate,
 	}
 }
 
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/client"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func newRefreshCmd() *cobra.Command {
+	var (
+		all     bool
+		noCache bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "refresh [ids...]",
+		Short: "Re-fetch properties from the MLS",
+		Long: `Re-fetch listing data for tracked properties and record what changed.
+
+Each refreshed property costs one RapidAPI call, unless the server has a
+fresh cached response for it. Use --no-cache to always fetch.
+
+Examples:
+  hf refresh 3
+  hf refresh 3 5 8
+  hf refresh --all
+  hf refresh 3 --no-cache`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return fmt.Errorf("specify property IDs or --all, not both")
+			}
+			if !all && len(args) == 0 {
+				return fmt.Errorf("specify at least one property ID or --all")
+			}
+
+			var ids []int64
+			for _, arg := range args {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid property ID: %s", arg)
+				}
+				ids = append(ids, id)
+			}
+
+			return runRefresh(ids, all, noCache)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "refresh every tracked property")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "ignore cached listing data and make a fresh RapidAPI call")
+
+	return cmd
+}
+
+func runRefresh(ids []int64, all, noCache bool) error {
+	c := newAPIClient()
+
+	if all {
+		props, err := c.ListProperties(client.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, p := range props {
+			if p.IsManual() {
+				continue
+			}
+			ids = append(ids, p.ID)
+		}
+	}
+
+	var results []*property.RefreshResult
+	var failed int
+	for _, id := range ids {
+		res, err := c.RefreshProperty(id, noCache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Property #%d: %v\n", id, err)
+			failed++
+			continue
+		}
+		results = append(results, res)
+		if !isJSON() {
+			printRefreshResult(res)
+		}
+	}
+
+	if isJSON() {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d refreshes failed", failed, len(ids))
+	}
+	return nil
+}
@@ -10,10 +10,13 @@ import (
 func newRemoveCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "remove <id>",
-		Short: "Remove a property",
-		Long:  "Remove a property and all its comments.",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runRemove,
+		Short: "Move a property to the trash",
+		Long: `Move a property to the trash. It drops out of "hf list" but keeps its
+comments and visits until the server purges it, 30 days later by default
+(HF_TRASH_RETENTION). Undo with "hf restore <id>"; see what is in the
+trash with "hf trash".`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRemove,
 	}
 }
 
@@ -36,6 +39,6 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	fmt.Printf("Property #%d removed.\n", id)
+	fmt.Printf("Property #%d moved to the trash. Undo with: hf restore %d\n", id, id)
 	return nil
 }
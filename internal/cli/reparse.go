@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newReparseCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "reparse",
+		Short: "Re-derive listing fields from stored MLS data (admin)",
+		Long: `Re-run the listing parser over every property's stored raw_json and
+update price, beds, baths, sqft and the other parsed fields.
+
+Use this after the parser learns new field paths. No RapidAPI calls are
+made and no listing history is recorded. Hand-edited overrides are kept.
+Requires the admin account.
+
+Examples:
+  hf reparse --dry-run
+  hf reparse`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReparse(dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would change without saving")
+
+	return cmd
+}
+
+func runReparse(dryRun bool) error {
+	report, err := newAPIClient().Reparse(dryRun)
+	if err != nil {
+		return fmt.Errorf("reparsing: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	for _, res := range report.Changed {
+		printRefreshResult(res)
+	}
+
+	verb := "updated"
+	if report.DryRun {
+		verb = "would change (dry run, nothing saved)"
+	}
+	fmt.Printf("Checked %d properties: %d %s\n", report.Checked, len(report.Changed), verb)
+	return nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Restore an archived or removed property",
+		Long:  `Bring an archived property, or one in the trash, back to "hf list".`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runRestore(id)
+		},
+	}
+}
+
+func runRestore(id int64) error {
+	p, err := newAPIClient().RestoreProperty(id)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(p)
+	}
+
+	fmt.Printf("Property #%d restored: %s\n", p.ID, p.Address)
+	return nil
+}
@@ -35,11 +35,32 @@ func NewRootCmd() *cobra.Command {
 		newListCmd(),
 		newShowCmd(),
 		newRateCmd(),
+		newEditCmd(),
 		newCommentCmd(),
 		newCommentsCmd(),
 		newVisitCmd(),
 		newVisitsCmd(),
+		newRefreshCmd(),
+		newLinkCmd(),
+		newEventsCmd(),
+		newPlaceCmd(),
+		newTagCmd(),
+		newScoreCmd(),
+		newCompareCmd(),
+		newSearchCmd(),
+		newCriteriaCmd(),
+		newCostCmd(),
+		newFinancingCmd(),
+		newDedupeCmd(),
+		newImportCmd(),
+		newUsageCmd(),
+		newJobsCmd(),
+		newCacheCmd(),
+		newReparseCmd(),
+		newArchiveCmd(),
 		newRemoveCmd(),
+		newRestoreCmd(),
+		newTrashCmd(),
 		newEmailCmd(),
 		newServeCmd(),
 		newLoginCmd(),
@@ -54,17 +75,21 @@ func NewRootCmd() *cobra.Command {
 // openDB opens the SQLite database using the --db flag or default path.
 // Used by the serve command to pass the DB to the web server.
 func openDB() (*sql.DB, error) {
-	path := flagDB
-	if path == "" {
-		var err error
-		path, err = db.DefaultPath()
-		if err != nil {
-			return nil, err
-		}
+	path, err := dbPath()
+	if err != nil {
+		return nil, err
 	}
 	return db.Open(path)
 }
 
+// dbPath returns the --db flag or the default database path.
+func dbPath() (string, error) {
+	if flagDB != "" {
+		return flagDB, nil
+	}
+	return db.DefaultPath()
+}
+
 // newAPIClient creates an HTTP client for the house-finder API.
 func newAPIClient() *client.Client {
 	return client.New(getServerURL(), getAPIKey())
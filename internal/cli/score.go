@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/score"
+)
+
+func newScoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "score <id> [criterion=score...]",
+		Short: "Show or set a property's score",
+		Long: `Show a property's weighted score against the household's criteria (see
+"hf criteria"), with the breakdown by criterion. Pass criterion=score
+pairs to score hand-rated criteria from 1 (poor) to 5 (great); 0 clears
+a score.
+
+Examples:
+  hf score 1
+  hf score 1 kitchen=4 yard=2
+  hf score 1 "natural light=5"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			scores := make(map[string]int, len(args)-1)
+			for _, arg := range args[1:] {
+				name, value, ok := strings.Cut(arg, "=")
+				v, err := strconv.Atoi(strings.TrimSpace(value))
+				if !ok || strings.TrimSpace(name) == "" || err != nil {
+					return fmt.Errorf("invalid score %q: want criterion=score, e.g. kitchen=4", arg)
+				}
+				scores[strings.TrimSpace(name)] = v
+			}
+			return runScore(id, scores)
+		},
+	}
+}
+
+func runScore(id int64, scores map[string]int) error {
+	c := newAPIClient()
+
+	var s *score.Score
+	var err error
+	if len(scores) > 0 {
+		s, err = c.SetScores(id, scores)
+	} else {
+		s, err = c.GetScore(id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(s)
+	}
+
+	return printScore(id, s)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search <words...>",
+		Short: "Search addresses, comments and visit notes",
+		Long: `Find active properties whose address, property type, comments or visit
+notes contain every word given. Words also match as prefixes, so "basem"
+finds "basement". Each match shows a snippet with the matching words in
+[brackets].
+
+Examples:
+  hf search basement
+  hf search "finished basement"
+  hf search oak ave`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSearch(strings.Join(args, " "))
+		},
+	}
+}
+
+func runSearch(query string) error {
+	c := newAPIClient()
+
+	results, err := c.Search(query)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(results)
+	}
+
+	printSearchResults(results)
+	return nil
+}
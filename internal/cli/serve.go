@@ -1,13 +1,19 @@
 package cli
 
 import (
+	"database/sql"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/evcraddock/house-finder/internal/auth"
 	"github.com/evcraddock/house-finder/internal/logging"
+	"github.com/evcraddock/house-finder/internal/media"
 	"github.com/evcraddock/house-finder/internal/mls"
 	"github.com/evcraddock/house-finder/internal/web"
 )
@@ -18,8 +24,16 @@ func newServeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Start the web UI",
-		Long:  "Start an HTTP server for the web UI.",
-		Args:  cobra.NoArgs,
+		Long: `Start an HTTP server for the web UI and the REST API.
+
+The server also runs background jobs; see "hf jobs". When a listing
+provider and SMTP are both configured, it re-fetches every active listing
+daily and emails price and status alerts (HF_WATCH_INTERVAL, default 24h;
+each pass costs one RapidAPI call per active listing), and emails alerts
+not yet sent hourly (HF_DIGEST_INTERVAL, default 1h). Without SMTP, no
+alerts are sent and both are off unless set. Set either to 0 to turn it
+off.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runServe(port)
 		},
@@ -30,6 +44,18 @@ func newServeCmd() *cobra.Command {
 	return cmd
 }
 
+// defaultCacheTTL is how long cached RapidAPI responses are reused.
+const defaultCacheTTL = 24 * time.Hour
+
+// jobIntervalEnv maps environment variables to the background jobs whose
+// interval they set.
+var jobIntervalEnv = []struct{ env, job string }{
+	{"HF_CLEANUP_INTERVAL", web.JobCleanup},
+	{"HF_WATCH_INTERVAL", web.JobListingRefresh},
+	{"HF_DIGEST_INTERVAL", web.JobDigest},
+	{"HF_PURGE_INTERVAL", web.JobTrashPurge},
+}
+
 func runServe(port int) error {
 	database, err := openDB()
 	if err != nil {
@@ -40,21 +66,116 @@ func runServe(port int) error {
 	authCfg := auth.ConfigFromEnv()
 	logging.Setup(authCfg.DevMode)
 
-	// Create MLS client if RAPIDAPI_KEY is set (optional — enables POST /api/properties)
-	var mlsClient *mls.Client
-	if key := os.Getenv("RAPIDAPI_KEY"); key != "" {
-		c, cErr := mls.NewClient(key)
-		if cErr != nil {
-			slog.Warn("mls client init failed", "err", cErr)
-		} else {
-			mlsClient = c
+	provider, err := listingProvider(database)
+	if err != nil {
+		return err
+	}
+
+	srv, err := web.NewServer(database, authCfg, provider)
+	if err != nil {
+		return err
+	}
+
+	// Background job intervals, overriding the defaults. Listing refresh
+	// and digest default on only when SMTP is configured to email alerts.
+	for _, j := range jobIntervalEnv {
+		v := os.Getenv(j.env)
+		if v == "" {
+			continue
+		}
+		interval, pErr := time.ParseDuration(v)
+		if pErr != nil || interval < 0 {
+			return fmt.Errorf("invalid %s %q: want a duration such as 24h, or 0 to disable", j.env, v)
+		}
+		if err := srv.SetJobInterval(j.job, interval); err != nil {
+			return err
 		}
 	}
 
-	srv, err := web.NewServer(database, authCfg, mlsClient)
+	if v := os.Getenv("HF_TRASH_RETENTION"); v != "" {
+		retention, pErr := time.ParseDuration(v)
+		if pErr != nil {
+			return fmt.Errorf("invalid HF_TRASH_RETENTION %q: want a duration such as 720h", v)
+		}
+		if err := srv.SetTrashRetention(retention); err != nil {
+			return err
+		}
+	}
+
+	store, err := photoStore()
 	if err != nil {
 		return err
 	}
+	if store != nil {
+		srv.CachePhotos(store)
+	}
 
 	return srv.ListenAndServe(port)
 }
+
+// photoStore opens the listing photo cache in HF_MEDIA_DIR, which defaults
+// to a media directory next to the database. "off" disables it, and the
+// web UI hotlinks photos from realtor.com instead.
+func photoStore() (*media.Store, error) {
+	dir := os.Getenv("HF_MEDIA_DIR")
+	if dir == "off" {
+		return nil, nil
+	}
+	if dir == "" {
+		path, err := dbPath()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(filepath.Dir(path), "media")
+	}
+	return media.NewStore(dir)
+}
+
+// listingProvider selects the MLS provider from HF_MLS_PROVIDER.
+// "rapidapi" (the default) uses RAPIDAPI_KEY and is optional — without a key
+// POST /api/properties is disabled. Its responses are cached in the database
+// for HF_MLS_CACHE_TTL (default 24h, 0 disables), and its calls are
+// limited to HF_RAPIDAPI_RATE per second (default 2, 0 disables). Every
+// call is metered in the database against the admin's monthly budget. "file"
+// serves saved responses from HF_MLS_FIXTURES for offline use.
+func listingProvider(database *sql.DB) (mls.Provider, error) {
+	switch name := os.Getenv("HF_MLS_PROVIDER"); name {
+	case "", "rapidapi":
+		key := os.Getenv("RAPIDAPI_KEY")
+		if key == "" {
+			return nil, nil
+		}
+		c, err := mls.NewClient(key)
+		if err != nil {
+			slog.Warn("mls client init failed", "err", err)
+			return nil, nil
+		}
+		if v := os.Getenv("HF_RAPIDAPI_RATE"); v != "" {
+			rate, err := strconv.ParseFloat(v, 64)
+			if err != nil || rate < 0 {
+				return nil, fmt.Errorf("invalid HF_RAPIDAPI_RATE %q: want calls per second", v)
+			}
+			c.SetRateLimit(rate)
+		}
+		c.SetUsage(mls.NewUsage(database))
+		ttl := defaultCacheTTL
+		if v := os.Getenv("HF_MLS_CACHE_TTL"); v != "" {
+			ttl, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid HF_MLS_CACHE_TTL %q: %w", v, err)
+			}
+		}
+		if ttl <= 0 {
+			return c, nil
+		}
+		return mls.NewCachedProvider(c, mls.NewCache(database, ttl)), nil
+	case "file":
+		dir := os.Getenv("HF_MLS_FIXTURES")
+		if dir == "" {
+			return nil, fmt.Errorf("HF_MLS_FIXTURES is required when HF_MLS_PROVIDER=file")
+		}
+		return mls.NewFileProvider(dir)
+	default:
+		return nil, fmt.Errorf("unknown HF_MLS_PROVIDER %q (want rapidapi or file)", name)
+	}
+}
@@ -40,6 +40,10 @@ func runShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Visits (%d):\n", len(resp.Visits))
 		printVisits(resp.Visits)
 	}
+	if len(resp.History) > 0 {
+		fmt.Printf("Listing history (%d):\n", len(resp.History))
+		printHistory(resp.History)
+	}
 	if len(resp.Comments) > 0 {
 		fmt.Printf("Comments (%d):\n", len(resp.Comments))
 		printCommentList(resp.Comments)
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Label properties with tags",
+		Long: `Label properties with free-form tags such as "needs-roof" or
+"great-schools". Tags are shared by the household and are created the
+first time they are used. Filter on them with "hf list --tag".`,
+	}
+
+	cmd.AddCommand(newTagAddCmd(), newTagRmCmd(), newTagLsCmd(), newTagRenameCmd(), newTagDeleteCmd())
+
+	return cmd
+}
+
+func newTagAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <id> <tag>...",
+		Short: "Add tags to a property",
+		Long: `Add one or more tags to a property.
+
+Examples:
+  hf tag add 1 needs-roof
+  hf tag add 1 "great schools" big-yard`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runTagAdd(id, args[1:])
+		},
+	}
+}
+
+func runTagAdd(id int64, tags []string) error {
+	c := newAPIClient()
+
+	names, err := c.TagProperty(id, tags...)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(names)
+	}
+
+	fmt.Printf("Property %d tags: %s\n", id, strings.Join(names, ", "))
+	return nil
+}
+
+func newTagRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <id> <tag>...",
+		Short: "Remove tags from a property",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid property ID: %s", args[0])
+			}
+			return runTagRm(id, args[1:])
+		},
+	}
+}
+
+func runTagRm(id int64, tags []string) error {
+	c := newAPIClient()
+
+	var names []string
+	for _, t := range tags {
+		var err error
+		if names, err = c.UntagProperty(id, t); err != nil {
+			return err
+		}
+	}
+
+	if isJSON() {
+		return printJSON(names)
+	}
+
+	if len(names) == 0 {
+		fmt.Printf("Property %d has no tags.\n", id)
+		return nil
+	}
+	fmt.Printf("Property %d tags: %s\n", id, strings.Join(names, ", "))
+	return nil
+}
+
+func newTagLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List tags and how many properties carry each",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagLs()
+		},
+	}
+}
+
+func runTagLs() error {
+	c := newAPIClient()
+
+	tags, err := c.ListTags()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(tags)
+	}
+
+	return printTagTable(tags)
+}
+
+func newTagRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <tag> <new-name>",
+		Short: "Rename a tag on every property",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagRename(args[0], args[1])
+		},
+	}
+}
+
+func runTagRename(name, newName string) error {
+	c := newAPIClient()
+
+	t, err := c.RenameTag(name, newName)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(t)
+	}
+
+	fmt.Printf("Renamed tag %q to %q.\n", name, t.Name)
+	return nil
+}
+
+func newTagDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <tag>",
+		Short: "Delete a tag from every property",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagDelete(args[0])
+		},
+	}
+}
+
+func runTagDelete(name string) error {
+	c := newAPIClient()
+
+	if err := c.DeleteTag(name); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]string{"deleted": name})
+	}
+
+	fmt.Printf("Deleted tag %q.\n", name)
+	return nil
+}
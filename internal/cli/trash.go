@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/evcraddock/house-finder/internal/client"
+)
+
+func newTrashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trash",
+		Short: "List removed properties",
+		Long: `List properties moved to the trash with "hf remove". The server deletes
+them for good, with their comments and visits, once they have been in the
+trash for the retention period (HF_TRASH_RETENTION, 30 days by default).
+Bring one back with "hf restore <id>".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrash()
+		},
+	}
+}
+
+func runTrash() error {
+	props, err := newAPIClient().ListProperties(client.ListOptions{State: "deleted"})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(props)
+	}
+
+	return printTrashTable(props)
+}
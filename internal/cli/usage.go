@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show listing API usage against the monthly budget (admin)",
+		Long: `Show how many listing API calls the server has made, by day, by month
+and by user, and how much of this month's RapidAPI budget is used.
+
+Only RapidAPI calls count against the budget; the realtor.com address
+lookups are free. Retries are counted as separate calls. Months and days
+are in UTC. Requires the admin account.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUsage()
+		},
+	}
+
+	cmd.AddCommand(newUsageBudgetCmd())
+
+	return cmd
+}
+
+func runUsage() error {
+	report, err := newAPIClient().Usage()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	return printUsage(report)
+}
+
+func newUsageBudgetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "budget <calls>",
+		Short: "Set the monthly budget of RapidAPI calls (admin)",
+		Long: `Set how many RapidAPI calls the server may make each calendar month
+(UTC). Once they are used up, adding or refreshing a property fails with
+an error instead of making the call; cached listings still work. Use 0 to
+remove the budget.
+
+Examples:
+  hf usage budget 500
+  hf usage budget 0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			calls, err := strconv.Atoi(args[0])
+			if err != nil || calls < 0 {
+				return fmt.Errorf("invalid budget %q: want a number of calls, 0 for none", args[0])
+			}
+			return runUsageBudget(calls)
+		},
+	}
+}
+
+func runUsageBudget(calls int) error {
+	report, err := newAPIClient().SetUsageBudget(calls)
+	if err != nil {
+		return fmt.Errorf("setting budget: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	if calls == 0 {
+		fmt.Println("Monthly budget removed.")
+	} else {
+		fmt.Printf("Monthly budget set to %d RapidAPI calls.\n", calls)
+	}
+	fmt.Printf("%d used in %s.\n", report.PaidThisMonth, report.Month)
+	return nil
+}
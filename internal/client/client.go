@@ -7,11 +7,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/evcraddock/house-finder/internal/alert"
 	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/compare"
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/jobs"
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
 	"github.com/evcraddock/house-finder/internal/visit"
 )
 
@@ -33,27 +43,91 @@ func New(baseURL, apiKey string) *Client {
 
 // ShowResponse is the response from GET /api/properties/{id}.
 type ShowResponse struct {
-	Property *property.Property `json:"property"`
-	Comments []*comment.Comment `json:"comments"`
-	Visits   []*visit.Visit     `json:"visits"`
+	Property *property.Property   `json:"property"`
+	Comments []*comment.Comment   `json:"comments"`
+	Visits   []*visit.Visit       `json:"visits"`
+	History  []*property.Snapshot `json:"history"`
 }
 
 // ListOptions controls filtering for ListProperties.
 type ListOptions struct {
+	State       string // active, archived, deleted or all (empty = active)
 	MinRating   int
-	VisitStatus string // not_visited, want_to_visit, visited (empty = all)
+	RatingBy    string   // whose rating MinRating applies to: me or household (empty = household)
+	VisitStatus string   // not_visited, want_to_visit, visited (empty = all)
+	MaxDistance []string // place=15mi limits, all of which must hold
+	MaxMonthly  int64    // estimated monthly cost cap in dollars (0 = no cap)
+	Tags        []string // tags every listed house must carry
+
+	// Listing-field ranges; a nil end is open, so 0 is a bound like any other.
+	Price, Beds, Baths, Sqft, YearBuilt property.Range
+
+	Types           []string // property types, any of which may match
+	ListingStatuses []string // listing statuses (for_sale, pending, ...), any of which may match
+	Cities          []string // cities, any of which may match
+	Zips            []string // ZIP codes, any of which may match
+
+	Sort string // sort keys with optional :asc or :desc, e.g. "price,sqft:desc" (see property.ParseSort); empty = average rating, then newest
 }
 
-// ListProperties returns all properties, optionally filtered.
+// ListProperties returns active properties, or those in opts.State,
+// optionally filtered.
 func (c *Client) ListProperties(opts ListOptions) ([]*property.Property, error) {
 	path := "/api/properties"
 	var params []string
+	if opts.State != "" {
+		params = append(params, "state="+url.QueryEscape(opts.State))
+	}
 	if opts.MinRating > 0 {
 		params = append(params, fmt.Sprintf("min_rating=%d", opts.MinRating))
 	}
+	if opts.RatingBy != "" {
+		params = append(params, "rating_by="+url.QueryEscape(opts.RatingBy))
+	}
 	if opts.VisitStatus != "" {
 		params = append(params, fmt.Sprintf("visit_status=%s", opts.VisitStatus))
 	}
+	for _, md := range opts.MaxDistance {
+		params = append(params, "max_distance="+url.QueryEscape(md))
+	}
+	if opts.MaxMonthly > 0 {
+		params = append(params, fmt.Sprintf("max_monthly=%d", opts.MaxMonthly))
+	}
+	for _, t := range opts.Tags {
+		params = append(params, "tag="+url.QueryEscape(t))
+	}
+	for _, r := range []struct {
+		name string
+		rng  property.Range
+	}{
+		{"price", opts.Price},
+		{"beds", opts.Beds},
+		{"baths", opts.Baths},
+		{"sqft", opts.Sqft},
+		{"year", opts.YearBuilt},
+	} {
+		if r.rng.Min != nil {
+			params = append(params, "min_"+r.name+"="+strconv.FormatFloat(*r.rng.Min, 'f', -1, 64))
+		}
+		if r.rng.Max != nil {
+			params = append(params, "max_"+r.name+"="+strconv.FormatFloat(*r.rng.Max, 'f', -1, 64))
+		}
+	}
+	for _, t := range opts.Types {
+		params = append(params, "property_type="+url.QueryEscape(t))
+	}
+	for _, st := range opts.ListingStatuses {
+		params = append(params, "listing_status="+url.QueryEscape(st))
+	}
+	for _, city := range opts.Cities {
+		params = append(params, "city="+url.QueryEscape(city))
+	}
+	for _, z := range opts.Zips {
+		params = append(params, "zip="+url.QueryEscape(z))
+	}
+	if opts.Sort != "" {
+		params = append(params, "sort="+url.QueryEscape(opts.Sort))
+	}
 	if len(params) > 0 {
 		path += "?" + strings.Join(params, "&")
 	}
@@ -75,8 +149,26 @@ func (c *Client) GetProperty(id int64) (*ShowResponse, error) {
 }
 
 // AddProperty adds a property by address (server does MLS lookup).
-func (c *Client) AddProperty(address string) (*property.Property, error) {
-	body := map[string]string{"address": address}
+// noCache forces a fresh RapidAPI call instead of using cached listing data.
+// A house that looks like one already tracked is refused with a
+// *property.DuplicateError unless force is set.
+func (c *Client) AddProperty(address string, noCache, force bool) (*property.Property, error) {
+	body := map[string]interface{}{"address": address, "no_cache": noCache, "force": force}
+	var p property.Property
+	if err := c.post("/api/properties", body, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AddManualProperty adds a property entered by hand (no MLS lookup). A
+// house that looks like one already tracked is refused with a
+// *property.DuplicateError unless in.Force is set.
+func (c *Client) AddManualProperty(in property.ManualInput) (*property.Property, error) {
+	body := struct {
+		property.ManualInput
+		Manual bool `json:"manual"`
+	}{in, true}
 	var p property.Property
 	if err := c.post("/api/properties", body, &p); err != nil {
 		return nil, err
@@ -84,12 +176,135 @@ func (c *Client) AddProperty(address string) (*property.Property, error) {
 	return &p, nil
 }
 
-// DeleteProperty removes a property.
+// ImportResponse is the response from POST /api/properties/batch.
+type ImportResponse struct {
+	Added   int                     `json:"added"`
+	Skipped int                     `json:"skipped"`
+	Failed  int                     `json:"failed"`
+	Results []property.ImportResult `json:"results"`
+}
+
+// ImportProperties adds many properties in one request. The server looks
+// them up a few at a time and reports each row; callers with long lists
+// should send them in chunks.
+func (c *Client) ImportProperties(rows []property.ImportRow, noCache bool) (*ImportResponse, error) {
+	body := map[string]interface{}{"rows": rows, "no_cache": noCache}
+	var resp ImportResponse
+	if err := c.post("/api/properties/batch", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LinkProperty attaches a manual entry to the MLS listing found for ref
+// (an address, realtor.com URL, or property ID).
+func (c *Client) LinkProperty(id int64, ref string, noCache bool) (*property.RefreshResult, error) {
+	body := map[string]interface{}{"address": ref, "no_cache": noCache}
+	var res property.RefreshResult
+	if err := c.post(fmt.Sprintf("/api/properties/%d/link", id), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Suggest returns up to n candidate listings for an address (free geocoder only).
+func (c *Client) Suggest(address string, n int) ([]mls.Candidate, error) {
+	params := url.Values{"q": {address}, "limit": {fmt.Sprint(n)}}
+	var candidates []mls.Candidate
+	if err := c.get("/api/suggest?"+params.Encode(), &candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// RefreshProperty re-fetches a property from the MLS (server makes one
+// RapidAPI call unless the listing is cached and noCache is false).
+func (c *Client) RefreshProperty(id int64, noCache bool) (*property.RefreshResult, error) {
+	body := map[string]bool{"no_cache": noCache}
+	var res property.RefreshResult
+	if err := c.post(fmt.Sprintf("/api/properties/%d/refresh", id), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// EditProperty stores hand corrections for a property's listing fields.
+// A nil value in edit removes that field's override.
+func (c *Client) EditProperty(id int64, edit property.Edit) (*property.Property, error) {
+	var p property.Property
+	if err := c.send("PATCH", fmt.Sprintf("/api/properties/%d", id), edit, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Reparse re-derives every property's listing columns from its stored
+// raw_json (admin only, no MLS calls). dryRun reports without saving.
+func (c *Client) Reparse(dryRun bool) (*property.ReparseReport, error) {
+	body := map[string]bool{"dry_run": dryRun}
+	var report property.ReparseReport
+	if err := c.post("/api/admin/reparse", body, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Usage returns the server's listing API usage and monthly budget
+// (admin only).
+func (c *Client) Usage() (*mls.UsageReport, error) {
+	var report mls.UsageReport
+	if err := c.get("/api/admin/usage", &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// SetUsageBudget sets the monthly budget of paid RapidAPI calls, after
+// which the server refuses them (admin only). Zero removes the budget.
+func (c *Client) SetUsageBudget(calls int) (*mls.UsageReport, error) {
+	body := map[string]int{"monthly_budget": calls}
+	var report mls.UsageReport
+	if err := c.send("PUT", "/api/admin/usage", body, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Jobs returns the server's background jobs with their schedule and
+// last run (admin only).
+func (c *Client) Jobs() ([]jobs.Status, error) {
+	var statuses []jobs.Status
+	if err := c.get("/api/admin/jobs", &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// DeleteProperty moves a property to the trash.
 func (c *Client) DeleteProperty(id int64) error {
 	return c.doDelete(fmt.Sprintf("/api/properties/%d", id))
 }
 
-// RateProperty sets a rating on a property.
+// ArchiveProperty hides a property from lists, keeping its history.
+func (c *Client) ArchiveProperty(id int64) (*property.Property, error) {
+	var p property.Property
+	if err := c.post(fmt.Sprintf("/api/properties/%d/archive", id), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// RestoreProperty returns an archived or deleted property to the list.
+func (c *Client) RestoreProperty(id int64) (*property.Property, error) {
+	var p property.Property
+	if err := c.post(fmt.Sprintf("/api/properties/%d/restore", id), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// RateProperty sets your rating on a property; other members' ratings
+// are left alone.
 func (c *Client) RateProperty(id int64, rating int) error {
 	body := map[string]int{"rating": rating}
 	return c.post(fmt.Sprintf("/api/properties/%d/rate", id), body, nil)
@@ -148,12 +363,17 @@ func (c *Client) get(path string, result interface{}) error {
 
 // post performs a POST request with a JSON body and decodes the response.
 func (c *Client) post(path string, body interface{}, result interface{}) error {
+	return c.send("POST", path, body, result)
+}
+
+// send performs a request with a JSON body and decodes the response.
+func (c *Client) send(method, path string, body interface{}, result interface{}) error {
 	data, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("marshaling request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+path, bytes.NewReader(data))
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
@@ -194,9 +414,13 @@ func (c *Client) do(req *http.Request, result interface{}) error {
 
 	if resp.StatusCode >= 400 {
 		var errResp struct {
-			Error string `json:"error"`
+			Error      string               `json:"error"`
+			Duplicates []property.Duplicate `json:"duplicates"`
 		}
-		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+		if json.Unmarshal(respBody, &errResp) == nil && len(errResp.Duplicates) > 0 {
+			return &property.DuplicateError{Duplicates: errResp.Duplicates}
+		}
+		if errResp.Error != "" {
 			return fmt.Errorf("%s", errResp.Error)
 		}
 		return fmt.Errorf("server error: %s", http.StatusText(resp.StatusCode))
@@ -211,6 +435,261 @@ func (c *Client) do(req *http.Request, result interface{}) error {
 	return nil
 }
 
+// ListEvents returns recent listing change events.
+// If propertyID is non-zero only that property's events are returned.
+func (c *Client) ListEvents(propertyID int64, limit int) ([]*alert.Event, error) {
+	path := "/api/events"
+	var params []string
+	if propertyID > 0 {
+		params = append(params, fmt.Sprintf("property_id=%d", propertyID))
+	}
+	if limit > 0 {
+		params = append(params, fmt.Sprintf("limit=%d", limit))
+	}
+	if len(params) > 0 {
+		path += "?" + strings.Join(params, "&")
+	}
+
+	var events []*alert.Event
+	if err := c.get(path, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListCache returns the server's cached MLS listing responses.
+func (c *Client) ListCache() ([]*mls.CacheEntry, error) {
+	var entries []*mls.CacheEntry
+	if err := c.get("/api/cache", &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ClearCache removes cached listing responses for mprID, or all of them
+// if mprID is empty. It returns the number of entries removed.
+func (c *Client) ClearCache(mprID string) (int64, error) {
+	path := "/api/cache"
+	if mprID != "" {
+		path += "?mpr_id=" + url.QueryEscape(mprID)
+	}
+
+	req, err := http.NewRequest("DELETE", c.baseURL+path, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	var resp struct {
+		Removed int64 `json:"removed"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Removed, nil
+}
+
+// ListCriteria returns the household's scoring criteria, heaviest first.
+func (c *Client) ListCriteria() ([]*score.Criterion, error) {
+	var criteria []*score.Criterion
+	if err := c.get("/api/criteria", &criteria); err != nil {
+		return nil, err
+	}
+	return criteria, nil
+}
+
+// AddCriterion saves a new scoring criterion.
+func (c *Client) AddCriterion(criterion *score.Criterion) (*score.Criterion, error) {
+	var saved score.Criterion
+	if err := c.post("/api/criteria", criterion, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// UpdateCriterion changes a criterion's weight, best or worst value.
+func (c *Client) UpdateCriterion(name string, u score.Update) (*score.Criterion, error) {
+	var updated score.Criterion
+	if err := c.send("PATCH", "/api/criteria/"+url.PathEscape(name), u, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCriterion removes a criterion and every hand score given for it.
+func (c *Client) DeleteCriterion(name string) error {
+	return c.doDelete("/api/criteria/" + url.PathEscape(name))
+}
+
+// GetScore returns a property's score with its breakdown by criterion.
+func (c *Client) GetScore(id int64) (*score.Score, error) {
+	var s score.Score
+	if err := c.get(fmt.Sprintf("/api/properties/%d/score", id), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SetScores records hand scores for a property, keyed by criterion name
+// (0 clears one), and returns its updated score.
+func (c *Client) SetScores(id int64, scores map[string]int) (*score.Score, error) {
+	var s score.Score
+	body := map[string]map[string]int{"scores": scores}
+	if err := c.send("PUT", fmt.Sprintf("/api/properties/%d/score", id), body, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListTags returns every tag with how many properties carry it.
+func (c *Client) ListTags() ([]*tag.Tag, error) {
+	var tags []*tag.Tag
+	if err := c.get("/api/tags", &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag on every property that carries it.
+func (c *Client) RenameTag(name, newName string) (*tag.Tag, error) {
+	var t tag.Tag
+	body := map[string]string{"name": newName}
+	if err := c.send("PATCH", "/api/tags/"+url.PathEscape(name), body, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTag removes a tag from every property.
+func (c *Client) DeleteTag(name string) error {
+	return c.doDelete("/api/tags/" + url.PathEscape(name))
+}
+
+// TagProperty adds tags to a property and returns all of its tags.
+func (c *Client) TagProperty(id int64, tags ...string) ([]string, error) {
+	var names []string
+	body := map[string][]string{"tags": tags}
+	if err := c.post(fmt.Sprintf("/api/properties/%d/tags", id), body, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// UntagProperty removes a tag from a property and returns the tags left.
+func (c *Client) UntagProperty(id int64, name string) ([]string, error) {
+	var names []string
+	path := fmt.Sprintf("/api/properties/%d/tags/%s", id, url.PathEscape(name))
+	if err := c.send("DELETE", path, nil, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListPlaces returns the household's named places.
+func (c *Client) ListPlaces() ([]*place.Place, error) {
+	var places []*place.Place
+	if err := c.get("/api/places", &places); err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+// AddPlace saves a named place at the given coordinates.
+func (c *Client) AddPlace(name string, lat, lon float64) (*place.Place, error) {
+	body := map[string]interface{}{"name": name, "latitude": lat, "longitude": lon}
+	var p place.Place
+	if err := c.post("/api/places", body, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeletePlace removes a named place.
+func (c *Client) DeletePlace(name string) error {
+	return c.doDelete("/api/places/" + url.PathEscape(name))
+}
+
+// ListDuplicates returns every group of tracked properties that look like
+// the same house.
+func (c *Client) ListDuplicates() ([]*property.DuplicateGroup, error) {
+	var groups []*property.DuplicateGroup
+	if err := c.get("/api/duplicates", &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// MergeProperty folds property fromID into keepID: its comments, visits,
+// history and ratings move to keepID and fromID is removed.
+func (c *Client) MergeProperty(keepID, fromID int64) (*property.Property, error) {
+	body := map[string]int64{"from": fromID}
+	var p property.Property
+	if err := c.post(fmt.Sprintf("/api/properties/%d/merge", keepID), body, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Search returns the active properties whose address, type, comments or
+// visit notes contain every word of query, each with a snippet of the
+// matching text.
+func (c *Client) Search(query string) ([]*property.SearchResult, error) {
+	var results []*property.SearchResult
+	if err := c.get("/api/search?q="+url.QueryEscape(query), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Compare lays properties side by side, in the order given, with the
+// best and worst value in each row marked.
+func (c *Client) Compare(ids []int64) (*compare.Comparison, error) {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	var cmp compare.Comparison
+	if err := c.get("/api/compare?ids="+strings.Join(parts, ","), &cmp); err != nil {
+		return nil, err
+	}
+	return &cmp, nil
+}
+
+// CostResponse is the response from GET /api/properties/{id}/cost.
+type CostResponse struct {
+	PropertyID int64            `json:"property_id"`
+	Profile    *finance.Profile `json:"profile"`
+	Cost       finance.Cost     `json:"cost"`
+}
+
+// PropertyCost returns a property's estimated monthly cost under the
+// caller's financing profile.
+func (c *Client) PropertyCost(id int64) (*CostResponse, error) {
+	var resp CostResponse
+	if err := c.get(fmt.Sprintf("/api/properties/%d/cost", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetFinancing returns the caller's financing profile.
+func (c *Client) GetFinancing() (*finance.Profile, error) {
+	var p finance.Profile
+	if err := c.get("/api/financing", &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveFinancing updates the caller's financing profile. Only the fields
+// in update (keyed by their JSON names) change.
+func (c *Client) SaveFinancing(update map[string]interface{}) (*finance.Profile, error) {
+	var p finance.Profile
+	if err := c.send("PUT", "/api/financing", update, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 // EmailRequest specifies which properties to email.
 type EmailRequest struct {
 	PropertyIDs []int64 `json:"property_ids,omitempty"`
@@ -2,12 +2,19 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/evcraddock/house-finder/internal/alert"
 	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/compare"
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/score"
 )
 
 func TestListProperties(t *testing.T) {
@@ -43,6 +50,9 @@ func TestListPropertiesWithMinRating(t *testing.T) {
 		if r.URL.Query().Get("min_rating") != "3" {
 			t.Errorf("min_rating = %q, want 3", r.URL.Query().Get("min_rating"))
 		}
+		if r.URL.Query().Get("rating_by") != "me" {
+			t.Errorf("rating_by = %q, want me", r.URL.Query().Get("rating_by"))
+		}
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode([]*property.Property{}); err != nil {
 			t.Fatalf("encode: %v", err)
@@ -51,9 +61,423 @@ func TestListPropertiesWithMinRating(t *testing.T) {
 	defer srv.Close()
 
 	c := New(srv.URL, "testkey")
-	if _, err := c.ListProperties(ListOptions{MinRating: 3}); err != nil {
+	if _, err := c.ListProperties(ListOptions{MinRating: 3, RatingBy: "me"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+}
+
+func TestRestoreProperty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/properties/7/restore" {
+			t.Errorf("request = %s %s, want POST /api/properties/7/restore", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(property.Property{ID: 7, State: property.StateActive}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := New(srv.URL, "testkey").RestoreProperty(7)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if p.State != property.StateActive {
+		t.Errorf("state = %q, want active", p.State)
+	}
+}
+
+func TestListPropertiesWithFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query()["max_distance"]
+		if len(got) != 2 || got[0] != "work=15mi" || got[1] != "school=5km" {
+			t.Errorf("max_distance = %v, want [work=15mi school=5km]", got)
+		}
+		if got := r.URL.Query().Get("max_monthly"); got != "2500" {
+			t.Errorf("max_monthly = %q, want 2500", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode([]*property.Property{}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	if _, err := c.ListProperties(ListOptions{MaxDistance: []string{"work=15mi", "school=5km"}, MaxMonthly: 2500}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+}
+
+func TestListPropertiesWithListingFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "city=Edmond&city=Norman&listing_status=for_sale&max_price=350000&min_baths=1.5&min_beds=3&min_sqft=0&min_year=1990&property_type=single_family&sort=price%2Csqft%3Adesc&zip=73034"
+		if got := r.URL.Query().Encode(); got != want {
+			t.Errorf("query = %s, want %s", got, want)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode([]*property.Property{}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	f64 := func(v float64) *float64 { return &v }
+	opts := ListOptions{
+		Price:           property.Range{Max: f64(350000)},
+		Beds:            property.Range{Min: f64(3)},
+		Baths:           property.Range{Min: f64(1.5)},
+		Sqft:            property.Range{Min: f64(0)}, // a zero bound is still sent
+		YearBuilt:       property.Range{Min: f64(1990)},
+		Types:           []string{"single_family"},
+		ListingStatuses: []string{"for_sale"},
+		Cities:          []string{"Edmond", "Norman"},
+		Zips:            []string{"73034"},
+		Sort:            "price,sqft:desc",
+	}
+	if _, err := c.ListProperties(opts); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var resp interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/properties":
+			if got := r.URL.Query()["tag"]; len(got) != 2 || got[0] != "needs roof" || got[1] != "pool" {
+				t.Errorf("tag = %v, want [needs roof pool]", got)
+			}
+			resp = []*property.Property{}
+		case r.Method == "POST" && r.URL.Path == "/api/properties/3/tags":
+			var req struct {
+				Tags []string `json:"tags"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			resp = req.Tags
+		case r.Method == "DELETE" && r.URL.Path == "/api/properties/3/tags/needs roof":
+			resp = []string{"pool"}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	if _, err := c.ListProperties(ListOptions{Tags: []string{"needs roof", "pool"}}); err != nil {
 		t.Fatalf("list: %v", err)
 	}
+	tags, err := c.TagProperty(3, "needs roof", "pool")
+	if err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Errorf("tags = %v, want 2", tags)
+	}
+	tags, err = c.UntagProperty(3, "needs roof")
+	if err != nil {
+		t.Fatalf("untag: %v", err)
+	}
+	if len(tags) != 1 || tags[0] != "pool" {
+		t.Errorf("tags after untag = %v, want [pool]", tags)
+	}
+}
+
+func TestScores(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var resp interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/properties":
+			if got := r.URL.Query().Get("sort"); got != "score" {
+				t.Errorf("sort = %q, want score", got)
+			}
+			resp = []*property.Property{}
+		case r.Method == "PUT" && r.URL.Path == "/api/properties/3/score":
+			var req struct {
+				Scores map[string]int `json:"scores"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if req.Scores["kitchen"] != 4 {
+				t.Errorf("scores = %v, want kitchen 4", req.Scores)
+			}
+			total := 75.0
+			resp = score.Score{Total: &total, Parts: []score.Part{{Criterion: "kitchen", Weight: 1, Manual: true}}}
+		case r.Method == "PATCH" && r.URL.Path == "/api/criteria/natural light":
+			resp = score.Criterion{Name: "natural light", Weight: 3}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	if _, err := c.ListProperties(ListOptions{Sort: "score"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	s, err := c.SetScores(3, map[string]int{"kitchen": 4})
+	if err != nil {
+		t.Fatalf("set scores: %v", err)
+	}
+	if s.Total == nil || *s.Total != 75 {
+		t.Errorf("total = %v, want 75", s.Total)
+	}
+	weight := 3
+	if _, err := c.UpdateCriterion("natural light", score.Update{Weight: &weight}); err != nil {
+		t.Fatalf("update criterion: %v", err)
+	}
+}
+
+func TestPlaces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/places":
+			if err := json.NewEncoder(w).Encode([]*place.Place{{ID: 1, Name: "work"}}); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+		case r.Method == "POST" && r.URL.Path == "/api/places":
+			var body struct {
+				Name      string  `json:"name"`
+				Latitude  float64 `json:"latitude"`
+				Longitude float64 `json:"longitude"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Name != "school" || body.Latitude != 35.5 || body.Longitude != -97.5 {
+				t.Errorf("body = %+v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			if err := json.NewEncoder(w).Encode(place.Place{ID: 2, Name: body.Name}); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+		case r.Method == "DELETE" && r.URL.EscapedPath() == "/api/places/mom%27s%20house":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.EscapedPath())
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	places, err := c.ListPlaces()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(places) != 1 || places[0].Name != "work" {
+		t.Errorf("places = %+v", places)
+	}
+	p, err := c.AddPlace("school", 35.5, -97.5)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if p.ID != 2 {
+		t.Errorf("id = %d, want 2", p.ID)
+	}
+	if err := c.DeletePlace("mom's house"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+}
+
+func TestImportProperties(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/properties/batch" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Rows    []property.ImportRow `json:"rows"`
+			NoCache bool                 `json:"no_cache"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(body.Rows) != 2 || body.Rows[1].Row != 7 || !body.NoCache {
+			t.Errorf("body = %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		resp := ImportResponse{Added: 1, Skipped: 1, Results: []property.ImportResult{
+			{Row: 1, Address: body.Rows[0].Address, Status: property.ImportAdded, Property: &property.Property{ID: 5}},
+			{Row: 7, Address: body.Rows[1].Address, Status: property.ImportSkipped, Error: "already tracked as #2"},
+		}}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	resp, err := c.ImportProperties([]property.ImportRow{
+		{Row: 1, Address: "1 Main St"},
+		{Row: 7, Address: "2 Main St"},
+	}, true)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if resp.Added != 1 || resp.Skipped != 1 || resp.Results[0].Property.ID != 5 {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var resp interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/duplicates":
+			resp = []*property.DuplicateGroup{{Address: "1 MAIN ST", Properties: []*property.Property{{ID: 1}, {ID: 2}}}}
+		case r.Method == "POST" && r.URL.Path == "/api/properties/1/merge":
+			var body map[string]int64
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body["from"] != 2 {
+				t.Errorf("from = %d, want 2", body["from"])
+			}
+			resp = property.Property{ID: 1}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	groups, err := c.ListDuplicates()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Properties) != 2 {
+		t.Errorf("groups = %+v", groups)
+	}
+	p, err := c.MergeProperty(1, 2)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if p.ID != 1 {
+		t.Errorf("merged id = %d, want 1", p.ID)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" || r.URL.Query().Get("q") != "finished basement" {
+			t.Errorf("request = %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		resp := []*property.SearchResult{{
+			Property: &property.Property{ID: 3},
+			Snippet:  []property.SnippetPart{{Text: "Huge "}, {Text: "finished", Match: true}},
+		}}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	results, err := c.Search("finished basement")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Property.ID != 3 || !results[0].Snippet[1].Match {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/compare" || r.URL.Query().Get("ids") != "4,1,7" {
+			t.Errorf("request = %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		resp := compare.Comparison{
+			Properties: []*property.Property{{ID: 4}, {ID: 1}, {ID: 7}},
+			Rows:       []compare.Row{{Label: "Price", Values: []string{"$1", "$2", "$3"}, Best: []int{0}, Worst: []int{2}}},
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	cmp, err := c.Compare([]int64{4, 1, 7})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if len(cmp.Properties) != 3 || cmp.Rows[0].Mark(0) != "best" || cmp.Rows[0].Mark(2) != "worst" {
+		t.Errorf("comparison = %+v", cmp)
+	}
+}
+
+func TestFinancing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var resp interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/financing":
+			resp = finance.DefaultProfile("a@example.com")
+		case r.Method == "PUT" && r.URL.Path == "/api/financing":
+			var body map[string]float64
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(body) != 1 || body["rate_percent"] != 6.25 {
+				t.Errorf("body = %v, want only rate_percent", body)
+			}
+			p := finance.DefaultProfile("a@example.com")
+			p.RatePercent = body["rate_percent"]
+			resp = p
+		case r.Method == "GET" && r.URL.Path == "/api/properties/7/cost":
+			resp = CostResponse{PropertyID: 7, Profile: finance.DefaultProfile(""), Cost: finance.Cost{Total: 1987}}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	p, err := c.GetFinancing()
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if p.TermYears != 30 {
+		t.Errorf("term = %d, want 30", p.TermYears)
+	}
+	p, err = c.SaveFinancing(map[string]interface{}{"rate_percent": 6.25})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if p.RatePercent != 6.25 {
+		t.Errorf("rate = %g, want 6.25", p.RatePercent)
+	}
+	cost, err := c.PropertyCost(7)
+	if err != nil {
+		t.Fatalf("cost: %v", err)
+	}
+	if cost.PropertyID != 7 || cost.Cost.Total != 1987 {
+		t.Errorf("cost = %+v", cost)
+	}
 }
 
 func TestGetProperty(t *testing.T) {
@@ -90,13 +514,19 @@ func TestAddProperty(t *testing.T) {
 		if r.Method != "POST" {
 			t.Errorf("method = %s", r.Method)
 		}
-		var req struct{ Address string }
+		var req struct {
+			Address string
+			NoCache bool `json:"no_cache"`
+		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			t.Fatalf("decode: %v", err)
 		}
 		if req.Address != "123 Main St" {
 			t.Errorf("address = %q", req.Address)
 		}
+		if !req.NoCache {
+			t.Error("expected no_cache = true")
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(&property.Property{ID: 1, Address: "123 Main St"}); err != nil {
@@ -106,7 +536,7 @@ func TestAddProperty(t *testing.T) {
 	defer srv.Close()
 
 	c := New(srv.URL, "testkey")
-	p, err := c.AddProperty("123 Main St")
+	p, err := c.AddProperty("123 Main St", true, false)
 	if err != nil {
 		t.Fatalf("add: %v", err)
 	}
@@ -115,6 +545,312 @@ func TestAddProperty(t *testing.T) {
 	}
 }
 
+func TestAddPropertyDuplicate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Force bool `json:"force"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !req.Force {
+			w.WriteHeader(http.StatusConflict)
+			if _, err := w.Write([]byte(`{"error": "looks like a house already tracked", "duplicates": [{"id": 7, "address": "123 Main Street"}]}`)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(&property.Property{ID: 8, Address: "123 Main St"}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	_, err := c.AddProperty("123 Main St", false, false)
+	var dupErr *property.DuplicateError
+	if !errors.As(err, &dupErr) || len(dupErr.Duplicates) != 1 || dupErr.Duplicates[0].ID != 7 {
+		t.Fatalf("add error = %v, want a duplicate of #7", err)
+	}
+
+	p, err := c.AddProperty("123 Main St", false, true)
+	if err != nil {
+		t.Fatalf("forced add: %v", err)
+	}
+	if p.ID != 8 {
+		t.Errorf("id = %d", p.ID)
+	}
+}
+
+func TestAddManualProperty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Address string `json:"address"`
+			Price   *int64 `json:"price"`
+			Manual  bool   `json:"manual"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !req.Manual {
+			t.Error("expected manual = true")
+		}
+		if req.Price == nil || *req.Price != 240000 {
+			t.Errorf("price = %v", req.Price)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(&property.Property{ID: 1, Address: req.Address, Source: property.SourceManual}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	price := int64(240000)
+	p, err := c.AddManualProperty(property.ManualInput{Address: "789 Elm St", Price: &price})
+	if err != nil {
+		t.Fatalf("add manual: %v", err)
+	}
+	if !p.IsManual() {
+		t.Errorf("source = %q", p.Source)
+	}
+}
+
+func TestLinkProperty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/properties/4/link" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&property.RefreshResult{Property: &property.Property{ID: 4, MprID: "M1"}}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	res, err := c.LinkProperty(4, "M1", false)
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if res.Property.MprID != "M1" {
+		t.Errorf("mpr_id = %q", res.Property.MprID)
+	}
+}
+
+func TestEditProperty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/properties/3" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		var edit map[string]*string
+		if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if v := edit["sqft"]; v == nil || *v != "1850" {
+			t.Errorf("sqft = %v", v)
+		}
+		if v, ok := edit["bedrooms"]; !ok || v != nil {
+			t.Errorf("bedrooms = %v (present %v), want null", v, ok)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&property.Property{ID: 3}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	sqft := "1850"
+	if _, err := c.EditProperty(3, property.Edit{"sqft": &sqft, "bedrooms": nil}); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+}
+
+func TestReparse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/admin/reparse" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			DryRun bool `json:"dry_run"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&property.ReparseReport{DryRun: req.DryRun, Checked: 2}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	report, err := c.Reparse(true)
+	if err != nil {
+		t.Fatalf("reparse: %v", err)
+	}
+	if !report.DryRun || report.Checked != 2 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestSetUsageBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/admin/usage" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			MonthlyBudget int `json:"monthly_budget"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&mls.UsageReport{Month: "2026-03", MonthlyBudget: req.MonthlyBudget, PaidThisMonth: 7}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	report, err := c.SetUsageBudget(500)
+	if err != nil {
+		t.Fatalf("set budget: %v", err)
+	}
+	if report.MonthlyBudget != 500 || report.PaidThisMonth != 7 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/suggest" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("q"); got != "100 Main St" {
+			t.Errorf("q = %q", got)
+		}
+		if got := r.URL.Query().Get("limit"); got != "5" {
+			t.Errorf("limit = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`[{"mpr_id":"1111111111","address":"100 Main St Apt 1"}]`)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	candidates, err := c.Suggest("100 Main St", 5)
+	if err != nil {
+		t.Fatalf("suggest: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].MprID != "1111111111" {
+		t.Errorf("candidates = %+v", candidates)
+	}
+}
+
+func TestRefreshProperty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s", r.Method)
+		}
+		if r.URL.Path != "/api/properties/7/refresh" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		newPrice := "240000"
+		resp := property.RefreshResult{
+			Property: &property.Property{ID: 7},
+			Changes:  []property.FieldChange{{Field: "price", NewValue: &newPrice}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	res, err := c.RefreshProperty(7, false)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if len(res.Changes) != 1 || res.Changes[0].Field != "price" {
+		t.Errorf("changes = %+v", res.Changes)
+	}
+}
+
+func TestCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/cache" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			if _, err := w.Write([]byte(`[{"mpr_id":"M1","realtor_url":"https://example.com/1","bytes":42}]`)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+		case "DELETE":
+			if got := r.URL.Query().Get("mpr_id"); got != "M1" {
+				t.Errorf("mpr_id = %q", got)
+			}
+			if _, err := w.Write([]byte(`{"removed":1}`)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+		default:
+			t.Errorf("method = %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	entries, err := c.ListCache()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 1 || entries[0].MprID != "M1" || entries[0].Bytes != 42 {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	n, err := c.ClearCache("M1")
+	if err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("removed = %d, want 1", n)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/events" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("property_id") != "3" {
+			t.Errorf("property_id = %q, want 3", r.URL.Query().Get("property_id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode([]*alert.Event{{ID: 1, PropertyID: 3, Kind: alert.KindSold}}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "testkey")
+	events, err := c.ListEvents(3, 10)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != alert.KindSold {
+		t.Errorf("events = %+v", events)
+	}
+}
+
 func TestDeleteProperty(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "DELETE" {
@@ -0,0 +1,267 @@
+// Package compare lays a few properties side by side, one row per field,
+// and marks the best and worst value in each row.
+package compare
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/visit"
+)
+
+// Properties compared at once; past MaxProperties the columns get too
+// narrow to read.
+const (
+	MinProperties = 2
+	MaxProperties = 6
+)
+
+// MaxComments is how many of each property's latest comments are shown.
+const MaxComments = 3
+
+// better says which way a row's values improve. Rows where neither way is
+// clearly preferable, such as stories or days on market, mark no best or
+// worst.
+type better int
+
+const (
+	neither better = iota
+	lower
+	higher
+)
+
+// Row is one field across the compared properties.
+type Row struct {
+	Label  string   `json:"label"`
+	Values []string `json:"values"`          // formatted, in the order of Comparison.Properties; "" when unknown
+	Best   []int    `json:"best,omitempty"`  // indexes of the best values
+	Worst  []int    `json:"worst,omitempty"` // indexes of the worst values
+}
+
+// Mark returns "best" or "worst" for the value at index i, or "".
+func (r Row) Mark(i int) string {
+	for _, b := range r.Best {
+		if b == i {
+			return "best"
+		}
+	}
+	for _, w := range r.Worst {
+		if w == i {
+			return "worst"
+		}
+	}
+	return ""
+}
+
+// Comparison is properties side by side.
+type Comparison struct {
+	Properties []*property.Property `json:"properties"`
+	Rows       []Row                `json:"rows"`
+	Comments   [][]*comment.Comment `json:"comments"` // each property's latest, newest first
+}
+
+// Build compares props in the order given. visits and comments hold each
+// property's visits and comments, newest first, in the same order.
+func Build(props []*property.Property, visits [][]*visit.Visit, comments [][]*comment.Comment) *Comparison {
+	c := &Comparison{Properties: props, Comments: make([][]*comment.Comment, len(props))}
+	for i := range props {
+		if i < len(comments) {
+			c.Comments[i] = comments[i][:min(len(comments[i]), MaxComments)]
+		}
+	}
+
+	c.number("Price", lower, dollars, func(p *property.Property) *float64 { return property.AsFloat(p.Price) })
+	c.number("Price per sqft", lower, dollars, pricePerSqft)
+	c.number("Bedrooms", higher, plain, func(p *property.Property) *float64 { return p.Bedrooms })
+	c.number("Bathrooms", higher, plain, func(p *property.Property) *float64 { return p.Bathrooms })
+	c.number("Sqft", higher, commas, func(p *property.Property) *float64 { return property.AsFloat(p.Sqft) })
+	c.number("Lot size", higher, acres, func(p *property.Property) *float64 { return p.LotSize })
+	c.number("Year built", higher, year, func(p *property.Property) *float64 { return property.AsFloat(p.YearBuilt) })
+	c.text("Type", func(p *property.Property) string { return property.StringValue(p.PropertyType) })
+	c.text("Listing status", func(p *property.Property) string { return property.StringValue(p.Status) })
+	c.number("HOA fee", lower, monthly, func(p *property.Property) *float64 { return property.AsFloat(p.HOAFee) })
+	c.number("Annual tax", lower, dollars, func(p *property.Property) *float64 { return property.AsFloat(p.AnnualTax) })
+	c.text("Listed", func(p *property.Property) string { return property.StringValue(p.ListDate) })
+	c.number("Days on market", neither, commas, func(p *property.Property) *float64 { return property.AsFloat(p.DaysOnMarket) })
+	c.text("Last sold", lastSold)
+	c.number("Garage", higher, plain, func(p *property.Property) *float64 { return property.AsFloat(p.Garage) })
+	c.number("Stories", neither, plain, func(p *property.Property) *float64 { return property.AsFloat(p.Stories) })
+	c.text("Heating", func(p *property.Property) string { return property.StringValue(p.Heating) })
+	c.text("Cooling", func(p *property.Property) string { return property.StringValue(p.Cooling) })
+	c.text("Listing agent", func(p *property.Property) string { return property.StringValue(p.ListingAgent) })
+	for _, name := range placeNames(props) {
+		c.number("To "+name, lower, miles, func(p *property.Property) *float64 { return distanceTo(p, name) })
+	}
+	c.number("Score", higher, plain, func(p *property.Property) *float64 {
+		if p.Score == nil {
+			return nil
+		}
+		return p.Score.Total
+	})
+	c.number("Rating", higher, average, func(p *property.Property) *float64 {
+		if p.Ratings == nil {
+			return nil
+		}
+		return &p.Ratings.Average
+	})
+	for _, email := range raters(props) {
+		label := "Rated by " + email
+		if email == "" {
+			label = "Shared rating"
+		}
+		c.number(label, higher, stars, func(p *property.Property) *float64 { return property.AsFloat(p.RatingBy(email)) })
+	}
+	c.text("Visit status", func(p *property.Property) string {
+		return strings.ReplaceAll(string(p.VisitStatus), "_", " ")
+	})
+	c.Rows = append(c.Rows, Row{Label: "Visits", Values: visitSummaries(props, visits)})
+	c.text("Tags", func(p *property.Property) string { return strings.Join(p.Tags, ", ") })
+	return c
+}
+
+// text adds a row of values that are only shown, never ranked.
+func (c *Comparison) text(label string, value func(*property.Property) string) {
+	r := Row{Label: label, Values: make([]string, len(c.Properties))}
+	for i, p := range c.Properties {
+		r.Values[i] = value(p)
+	}
+	c.Rows = append(c.Rows, r)
+}
+
+// number adds a row of numeric values, marking the best and worst when
+// b says which way is better and at least two known values differ.
+func (c *Comparison) number(label string, b better, format func(float64) string, value func(*property.Property) *float64) {
+	r := Row{Label: label, Values: make([]string, len(c.Properties))}
+	var known []float64
+	vals := make([]*float64, len(c.Properties))
+	for i, p := range c.Properties {
+		if v := value(p); v != nil {
+			vals[i] = v
+			r.Values[i] = format(*v)
+			known = append(known, *v)
+		}
+	}
+
+	if b != neither && len(known) >= 2 {
+		lo, hi := known[0], known[0]
+		for _, v := range known {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+		if lo != hi {
+			best, worst := hi, lo
+			if b == lower {
+				best, worst = lo, hi
+			}
+			for i, v := range vals {
+				switch {
+				case v == nil:
+				case *v == best:
+					r.Best = append(r.Best, i)
+				case *v == worst:
+					r.Worst = append(r.Worst, i)
+				}
+			}
+		}
+	}
+	c.Rows = append(c.Rows, r)
+}
+
+func pricePerSqft(p *property.Property) *float64 {
+	if p.Price == nil || p.Sqft == nil || *p.Sqft <= 0 {
+		return nil
+	}
+	v := math.Round(float64(*p.Price) / float64(*p.Sqft))
+	return &v
+}
+
+func lastSold(p *property.Property) string {
+	switch {
+	case p.LastSoldPrice != nil && p.LastSoldDate != nil:
+		return fmt.Sprintf("%s (%s)", dollars(float64(*p.LastSoldPrice)), *p.LastSoldDate)
+	case p.LastSoldPrice != nil:
+		return dollars(float64(*p.LastSoldPrice))
+	default:
+		return property.StringValue(p.LastSoldDate)
+	}
+}
+
+// placeNames returns the places any of props has a distance to, in the
+// order first seen.
+func placeNames(props []*property.Property) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range props {
+		for _, d := range p.Distances {
+			if !seen[d.Place] {
+				seen[d.Place] = true
+				names = append(names, d.Place)
+			}
+		}
+	}
+	return names
+}
+
+func distanceTo(p *property.Property, name string) *float64 {
+	for _, d := range p.Distances {
+		if d.Place == name {
+			miles := d.Miles
+			return &miles
+		}
+	}
+	return nil
+}
+
+// raters returns the emails of everyone who rated any of props, in the
+// order first seen.
+func raters(props []*property.Property) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, p := range props {
+		if p.Ratings == nil {
+			continue
+		}
+		for _, m := range p.Ratings.Members {
+			if !seen[m.Email] {
+				seen[m.Email] = true
+				emails = append(emails, m.Email)
+			}
+		}
+	}
+	return emails
+}
+
+// visitSummaries counts each property's visits and gives the latest date.
+func visitSummaries(props []*property.Property, visits [][]*visit.Visit) []string {
+	out := make([]string, len(props))
+	for i := range props {
+		if i >= len(visits) || len(visits[i]) == 0 {
+			out[i] = "none"
+			continue
+		}
+		out[i] = fmt.Sprintf("%d, last %s", len(visits[i]), visits[i][0].VisitDate)
+	}
+	return out
+}
+
+func plain(v float64) string   { return fmt.Sprintf("%g", v) }
+func year(v float64) string    { return fmt.Sprintf("%.0f", v) }
+func acres(v float64) string   { return fmt.Sprintf("%.2f acres", v) }
+func miles(v float64) string   { return fmt.Sprintf("%.1f mi", v) }
+func average(v float64) string { return fmt.Sprintf("%.1f", v) }
+func monthly(v float64) string { return dollars(v) + "/mo" }
+
+func stars(v float64) string {
+	n := int(v)
+	return strings.Repeat("★", n) + strings.Repeat("☆", 4-n)
+}
+
+func dollars(v float64) string {
+	return "$" + commas(v)
+}
+
+func commas(v float64) string {
+	return property.FormatDollars(int64(math.Round(v)))
+}
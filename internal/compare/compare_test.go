@@ -0,0 +1,116 @@
+package compare
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/visit"
+)
+
+func int64Ptr(v int64) *int64       { return &v }
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestBuild(t *testing.T) {
+	props := []*property.Property{
+		{ID: 1, Price: int64Ptr(300000), Sqft: int64Ptr(2000), Bedrooms: float64Ptr(3), VisitStatus: property.VisitStatusVisited},
+		{ID: 2, Price: int64Ptr(250000), Sqft: int64Ptr(1250), Bedrooms: float64Ptr(4)},
+		{ID: 3, Price: int64Ptr(250000), Bedrooms: float64Ptr(3), Tags: []string{"big yard", "needs roof"}},
+	}
+	visits := [][]*visit.Visit{
+		{{VisitDate: "2024-03-09"}, {VisitDate: "2024-03-02"}},
+		nil,
+		nil,
+	}
+	comments := [][]*comment.Comment{
+		{{Text: "d"}, {Text: "c"}, {Text: "b"}, {Text: "a"}},
+		nil,
+		{{Text: "only"}},
+	}
+
+	c := Build(props, visits, comments)
+
+	rows := make(map[string]Row)
+	for _, r := range c.Rows {
+		rows[r.Label] = r
+	}
+	tests := []struct {
+		label               string
+		values, best, worst string
+	}{
+		// Both cheapest are best; unknown values are never marked.
+		{"Price", "[$300,000 $250,000 $250,000]", "[1 2]", "[0]"},
+		{"Price per sqft", "[$150 $200 ]", "[0]", "[1]"},
+		{"Bedrooms", "[3 4 3]", "[1]", "[0 2]"},
+		{"Year built", "[  ]", "[]", "[]"},
+		{"Visits", "[2, last 2024-03-09 none none]", "[]", "[]"},
+		{"Visit status", "[visited  ]", "[]", "[]"},
+		{"Tags", "[  big yard, needs roof]", "[]", "[]"},
+	}
+	for _, tt := range tests {
+		r, ok := rows[tt.label]
+		if !ok {
+			t.Errorf("no %q row", tt.label)
+			continue
+		}
+		if got := fmt.Sprint(r.Values); got != tt.values {
+			t.Errorf("%s values = %s, want %s", tt.label, got, tt.values)
+		}
+		if got := fmt.Sprint(r.Best); got != tt.best {
+			t.Errorf("%s best = %s, want %s", tt.label, got, tt.best)
+		}
+		if got := fmt.Sprint(r.Worst); got != tt.worst {
+			t.Errorf("%s worst = %s, want %s", tt.label, got, tt.worst)
+		}
+	}
+	if mark := rows["Price"].Mark(2); mark != "best" {
+		t.Errorf("Mark(2) = %q, want best", mark)
+	}
+
+	if len(c.Comments[0]) != MaxComments || c.Comments[0][0].Text != "d" {
+		t.Errorf("comments[0] = %d, want the latest %d", len(c.Comments[0]), MaxComments)
+	}
+	if len(c.Comments[1]) != 0 || len(c.Comments[2]) != 1 {
+		t.Errorf("comments = %d and %d, want 0 and 1", len(c.Comments[1]), len(c.Comments[2]))
+	}
+}
+
+func TestBuildRatingsAndDistances(t *testing.T) {
+	rated := func(average float64, members ...property.MemberRating) *property.Property {
+		return &property.Property{Ratings: &property.Ratings{Members: members, Average: average}}
+	}
+	a := property.MemberRating{Email: "a@example.com", Rating: 4}
+	b := property.MemberRating{Email: "b@example.com", Rating: 2}
+	props := []*property.Property{
+		rated(3, a, b),
+		rated(2, property.MemberRating{Email: "b@example.com", Rating: 2}),
+		{},
+	}
+	props[0].Distances = []place.Distance{{Place: "work", Miles: 5}}
+	props[1].Distances = []place.Distance{{Place: "work", Miles: 12.25}}
+
+	c := Build(props, nil, nil)
+
+	var labels []string
+	for _, r := range c.Rows {
+		switch r.Label {
+		case "Rating", "Rated by a@example.com", "Rated by b@example.com":
+			labels = append(labels, r.Label)
+		case "To work":
+			if fmt.Sprint(r.Values) != "[5.0 mi 12.2 mi ]" || fmt.Sprint(r.Best) != "[0]" || fmt.Sprint(r.Worst) != "[1]" {
+				t.Errorf("distance row = %+v", r)
+			}
+		}
+		if r.Label == "Rating" && (fmt.Sprint(r.Best) != "[0]" || fmt.Sprint(r.Worst) != "[1]") {
+			t.Errorf("rating best %v worst %v, want [0] and [1]", r.Best, r.Worst)
+		}
+		if r.Label == "Rated by b@example.com" && (len(r.Best) != 0 || r.Values[0] != "★★☆☆") {
+			t.Errorf("b's row = %+v, want equal ratings left unmarked", r)
+		}
+	}
+	if fmt.Sprint(labels) != "[Rating Rated by a@example.com Rated by b@example.com]" {
+		t.Errorf("rating rows = %v", labels)
+	}
+}
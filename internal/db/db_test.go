@@ -101,7 +101,7 @@ func TestMigrations(t *testing.T) {
 		{
 			name:  "properties table exists",
 			table: "properties",
-			cols:  []string{"id", "address", "mpr_id", "realtor_url", "price", "bedrooms", "bathrooms", "sqft", "lot_size", "year_built", "property_type", "status", "rating", "raw_json", "created_at", "updated_at", "visit_status"},
+			cols:  []string{"id", "address", "mpr_id", "realtor_url", "price", "bedrooms", "bathrooms", "sqft", "lot_size", "year_built", "property_type", "status", "raw_json", "created_at", "updated_at", "visit_status", "source", "hoa_fee", "annual_tax", "list_date", "last_sold_price", "last_sold_date", "latitude", "longitude", "garage", "stories", "heating", "cooling", "listing_agent", "address_canonical", "archived_at", "deleted_at"},
 		},
 		{
 			name:  "comments table exists",
@@ -128,6 +128,76 @@ func TestMigrations(t *testing.T) {
 			table: "api_keys",
 			cols:  []string{"id", "name", "key_prefix", "key_hash", "created_at", "last_used_at", "email"},
 		},
+		{
+			name:  "property_snapshots table exists",
+			table: "property_snapshots",
+			cols:  []string{"id", "property_id", "field", "old_value", "new_value", "created_at"},
+		},
+		{
+			name:  "listing_events table exists",
+			table: "listing_events",
+			cols:  []string{"id", "property_id", "kind", "message", "old_value", "new_value", "created_at", "notified_at"},
+		},
+		{
+			name:  "mls_cache table exists",
+			table: "mls_cache",
+			cols:  []string{"realtor_url", "mpr_id", "raw_json", "fetched_at"},
+		},
+		{
+			name:  "property_overrides table exists",
+			table: "property_overrides",
+			cols:  []string{"property_id", "field", "value", "updated_at"},
+		},
+		{
+			name:  "places table exists",
+			table: "places",
+			cols:  []string{"id", "name", "latitude", "longitude", "created_at"},
+		},
+		{
+			name:  "financing_profiles table exists",
+			table: "financing_profiles",
+			cols:  []string{"email", "down_payment_percent", "rate_percent", "term_years", "insurance_rate", "pmi_rate", "pmi_threshold_percent", "tax_rate", "updated_at"},
+		},
+		{
+			name:  "mls_calls table exists",
+			table: "mls_calls",
+			cols:  []string{"id", "endpoint", "status", "latency_ms", "user", "created_at"},
+		},
+		{
+			name:  "mls_budget table exists",
+			table: "mls_budget",
+			cols:  []string{"id", "monthly_calls", "updated_by", "updated_at"},
+		},
+		{
+			name:  "jobs table exists",
+			table: "jobs",
+			cols:  []string{"name", "locked_by", "locked_until", "last_started_at", "last_finished_at", "last_status", "last_error", "last_duration_ms", "runs", "failures"},
+		},
+		{
+			name:  "tags table exists",
+			table: "tags",
+			cols:  []string{"id", "name", "created_at"},
+		},
+		{
+			name:  "property_tags table exists",
+			table: "property_tags",
+			cols:  []string{"property_id", "tag_id", "created_at"},
+		},
+		{
+			name:  "score_criteria table exists",
+			table: "score_criteria",
+			cols:  []string{"id", "name", "weight", "field", "place", "best", "worst", "created_at"},
+		},
+		{
+			name:  "property_scores table exists",
+			table: "property_scores",
+			cols:  []string{"property_id", "criterion_id", "score", "updated_at"},
+		},
+		{
+			name:  "property_ratings table exists",
+			table: "property_ratings",
+			cols:  []string{"property_id", "email", "rating", "updated_at"},
+		},
 	}
 
 	d := openTestDB(t)
@@ -150,15 +220,18 @@ func TestMigrations(t *testing.T) {
 func TestRatingConstraint(t *testing.T) {
 	d := openTestDB(t)
 
-	insert := `INSERT INTO properties (address, mpr_id, realtor_url, raw_json) VALUES (?, ?, ?, ?)`
-	insertWithRating := `INSERT INTO properties (address, mpr_id, realtor_url, raw_json, rating) VALUES (?, ?, ?, ?, ?)`
+	if _, err := d.Exec(
+		`INSERT INTO properties (address, mpr_id, realtor_url, raw_json) VALUES (?, ?, ?, ?)`,
+		"123 Test St", "mpr-rated", "https://example.com", "{}",
+	); err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
 
 	tests := []struct {
 		name    string
-		rating  interface{}
+		rating  int
 		wantErr bool
 	}{
-		{"null rating is valid", nil, false},
 		{"rating 1 is valid", 1, false},
 		{"rating 4 is valid", 4, false},
 		{"rating 0 is invalid", 0, true},
@@ -168,13 +241,10 @@ func TestRatingConstraint(t *testing.T) {
 
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mprID := fmt.Sprintf("mpr-%d", i)
-			var err error
-			if tt.rating == nil {
-				_, err = d.Exec(insert, "123 Test St", mprID, "https://example.com", "{}")
-			} else {
-				_, err = d.Exec(insertWithRating, "123 Test St", mprID, "https://example.com", "{}", tt.rating)
-			}
+			_, err := d.Exec(
+				`INSERT INTO property_ratings (property_id, email, rating) VALUES (1, ?, ?)`,
+				fmt.Sprintf("rater%d@example.com", i), tt.rating,
+			)
 			if tt.wantErr && err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -185,6 +255,128 @@ func TestRatingConstraint(t *testing.T) {
 	}
 }
 
+func TestLegacyRatingMigration(t *testing.T) {
+	d := openTestDB(t)
+
+	if has, err := columnExists(d, "properties", "rating"); err != nil || has {
+		t.Fatalf("new database has properties.rating = %v (%v), want no column", has, err)
+	}
+
+	// A database from before per-member ratings.
+	if _, err := d.Exec(`ALTER TABLE properties ADD COLUMN rating INTEGER CHECK (rating IS NULL OR (rating >= 1 AND rating <= 4))`); err != nil {
+		t.Fatalf("add legacy column: %v", err)
+	}
+	insert := `INSERT INTO properties (address, mpr_id, realtor_url, raw_json, rating) VALUES (?, ?, ?, ?, ?)`
+	if _, err := d.Exec(insert, "123 Test St", "mpr-legacy", "https://example.com", "{}", 3); err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	if _, err := d.Exec(insert, "456 Oak Ave", "mpr-unrated", "https://example.com", "{}", nil); err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	// Twice, as on every start.
+	for i := 0; i < 2; i++ {
+		if err := migrate(d); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+
+	var id int64
+	var email string
+	var rating int
+	if err := d.QueryRow(`SELECT property_id, email, rating FROM property_ratings`).Scan(&id, &email, &rating); err != nil {
+		t.Fatalf("reading migrated rating: %v", err)
+	}
+	if id != 1 || email != "" || rating != 3 {
+		t.Errorf("migrated rating = (%d, %q, %d), want (1, \"\", 3)", id, email, rating)
+	}
+
+	if has, err := columnExists(d, "properties", "rating"); err != nil || has {
+		t.Errorf("properties.rating still exists after migrating (%v)", err)
+	}
+	if _, err := d.Exec(`UPDATE properties SET rating = 4 WHERE id = 1`); err == nil {
+		t.Error("expected writing properties.rating to fail once it is dropped")
+	}
+}
+
+func TestSearchIndexSync(t *testing.T) {
+	d := openTestDB(t)
+
+	matches := func(q string) []int64 {
+		t.Helper()
+		rows, err := d.Query(`SELECT rowid FROM property_search WHERE property_search MATCH ? ORDER BY rowid`, q)
+		if err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		defer func() { _ = rows.Close() }()
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	exec := func(query string, args ...interface{}) {
+		t.Helper()
+		if _, err := d.Exec(query, args...); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+	}
+
+	exec(`INSERT INTO properties (id, address, mpr_id, realtor_url, property_type, raw_json) VALUES (1, '12 Elm St', 'm1', 'u', 'single_family', '{}')`)
+	exec(`INSERT INTO properties (id, address, mpr_id, realtor_url, raw_json) VALUES (2, '9 Oak Ave', 'm2', 'u', '{}')`)
+	exec(`INSERT INTO comments (property_id, text) VALUES (1, 'Huge finished basement')`)
+	exec(`INSERT INTO visits (property_id, visit_date, visit_type, notes) VALUES (2, '2024-03-01', 'showing', 'Basement smells damp')`)
+	exec(`INSERT INTO property_overrides (property_id, field, value) VALUES (2, 'property_type', 'condo')`)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"elm", "[1]"},
+		{"single_family", "[1]"},
+		{"condo", "[2]"},
+		{"basement", "[1 2]"},
+		{"damp", "[2]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(matches(tt.query)); got != tt.want {
+			t.Errorf("%q matches %s, want %s", tt.query, got, tt.want)
+		}
+	}
+
+	exec(`UPDATE properties SET address = '14 Birch Ln' WHERE id = 1`)
+	exec(`DELETE FROM comments`)
+	exec(`UPDATE visits SET property_id = 1`)
+	exec(`DELETE FROM property_overrides`)
+	for q, want := range map[string]string{"elm": "[]", "birch": "[1]", "basement": "[1]", "condo": "[]"} {
+		if got := fmt.Sprint(matches(q)); got != want {
+			t.Errorf("after changes %q matches %s, want %s", q, got, want)
+		}
+	}
+
+	// Deleting a property drops it, even though its visits cascade.
+	exec(`DELETE FROM properties WHERE id = 1`)
+	var n int
+	if err := d.QueryRow(`SELECT COUNT(*) FROM property_search`).Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("index has %d rows, want 1", n)
+	}
+
+	// Properties missing from the index are added on the next start.
+	exec(`DELETE FROM property_search`)
+	if err := migrate(d); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if got := fmt.Sprint(matches("oak")); got != "[2]" {
+		t.Errorf("after migrate oak matches %s, want [2]", got)
+	}
+}
+
 func TestCascadeDelete(t *testing.T) {
 	d := openTestDB(t)
 
@@ -0,0 +1,10 @@
+//go:build !sqlite_fts5
+
+package db
+
+// Search needs SQLite's FTS5, which go-sqlite3 only compiles in with the
+// sqlite_fts5 build tag. Without it the property_search migration would
+// fail on every database, so refuse to build instead:
+//
+//	go build -tags sqlite_fts5 ./cmd/hf  (or make build)
+var _ = hf_must_be_built_with_tags_sqlite_fts5
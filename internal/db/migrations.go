@@ -22,7 +22,6 @@ var migrations = []string{
 		year_built    INTEGER,
 		property_type TEXT,
 		status        TEXT,
-		rating        INTEGER CHECK (rating IS NULL OR (rating >= 1 AND rating <= 4)),
 		raw_json      TEXT    NOT NULL,
 		created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP
@@ -89,13 +88,130 @@ func migrate(db *sql.DB) error {
 			notes       TEXT    NOT NULL DEFAULT '',
 			created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS property_snapshots (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			field       TEXT    NOT NULL,
+			old_value   TEXT,
+			new_value   TEXT,
+			created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS listing_events (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			kind        TEXT    NOT NULL,
+			message     TEXT    NOT NULL,
+			old_value   TEXT,
+			new_value   TEXT,
+			created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+			notified_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS mls_cache (
+			realtor_url TEXT     PRIMARY KEY,
+			mpr_id      TEXT     NOT NULL,
+			raw_json    TEXT     NOT NULL,
+			fetched_at  DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS property_overrides (
+			property_id INTEGER  NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			field       TEXT     NOT NULL,
+			value       TEXT     NOT NULL,
+			updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (property_id, field)
+		)`,
+		`CREATE TABLE IF NOT EXISTS places (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+			latitude   REAL    NOT NULL,
+			longitude  REAL    NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS financing_profiles (
+			email                 TEXT     PRIMARY KEY,
+			down_payment_percent  REAL     NOT NULL,
+			rate_percent          REAL     NOT NULL,
+			term_years            INTEGER  NOT NULL,
+			insurance_rate        REAL     NOT NULL,
+			pmi_rate              REAL     NOT NULL,
+			pmi_threshold_percent REAL     NOT NULL,
+			tax_rate              REAL     NOT NULL,
+			updated_at            DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS mls_calls (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			endpoint   TEXT    NOT NULL,
+			status     INTEGER NOT NULL,
+			latency_ms INTEGER NOT NULL,
+			user       TEXT    NOT NULL DEFAULT '',
+			created_at TEXT    NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_mls_calls_created_at ON mls_calls(created_at)`,
+		`CREATE TABLE IF NOT EXISTS mls_budget (
+			id            INTEGER PRIMARY KEY CHECK (id = 1),
+			monthly_calls INTEGER NOT NULL,
+			updated_by    TEXT    NOT NULL DEFAULT '',
+			updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS jobs (
+			name             TEXT     PRIMARY KEY,
+			locked_by        TEXT     NOT NULL DEFAULT '',
+			locked_until     DATETIME,
+			last_started_at  DATETIME,
+			last_finished_at DATETIME,
+			last_status      TEXT     NOT NULL DEFAULT '',
+			last_error       TEXT     NOT NULL DEFAULT '',
+			last_duration_ms INTEGER  NOT NULL DEFAULT 0,
+			runs             INTEGER  NOT NULL DEFAULT 0,
+			failures         INTEGER  NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS property_tags (
+			property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			tag_id      INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (property_id, tag_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_property_tags_tag ON property_tags(tag_id)`,
+		`CREATE TABLE IF NOT EXISTS score_criteria (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+			weight     INTEGER NOT NULL,
+			field      TEXT    NOT NULL DEFAULT '',
+			place      TEXT    NOT NULL DEFAULT '',
+			best       REAL,
+			worst      REAL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS property_scores (
+			property_id  INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			criterion_id INTEGER NOT NULL REFERENCES score_criteria(id) ON DELETE CASCADE,
+			score        INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+			updated_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (property_id, criterion_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS property_ratings (
+			property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+			email       TEXT    NOT NULL,
+			rating      INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 4),
+			updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (property_id, email)
+		)`,
 	}
+	tableMigrations = append(tableMigrations, searchMigrations()...)
 	for _, m := range tableMigrations {
 		if _, err := db.Exec(m); err != nil {
 			return fmt.Errorf("table migration: %w", err)
 		}
 	}
 
+	if err := dropLegacyRatings(db); err != nil {
+		return fmt.Errorf("moving legacy ratings: %w", err)
+	}
+
 	columnMigrations := []struct {
 		table, column, definition string
 	}{
@@ -104,6 +220,22 @@ func migrate(db *sql.DB) error {
 		{"authorized_users", "phone", "TEXT NOT NULL DEFAULT ''"},
 		{"authorized_users", "is_realtor", "INTEGER NOT NULL DEFAULT 0"},
 		{"properties", "visit_status", "TEXT NOT NULL DEFAULT 'not_visited'"},
+		{"properties", "source", "TEXT NOT NULL DEFAULT 'mls'"},
+		{"properties", "hoa_fee", "INTEGER"},
+		{"properties", "annual_tax", "INTEGER"},
+		{"properties", "list_date", "TEXT"},
+		{"properties", "last_sold_price", "INTEGER"},
+		{"properties", "last_sold_date", "TEXT"},
+		{"properties", "latitude", "REAL"},
+		{"properties", "longitude", "REAL"},
+		{"properties", "garage", "INTEGER"},
+		{"properties", "stories", "INTEGER"},
+		{"properties", "heating", "TEXT"},
+		{"properties", "cooling", "TEXT"},
+		{"properties", "listing_agent", "TEXT"},
+		{"properties", "address_canonical", "TEXT NOT NULL DEFAULT ''"},
+		{"properties", "archived_at", "DATETIME"},
+		{"properties", "deleted_at", "DATETIME"},
 	}
 
 	for _, cm := range columnMigrations {
@@ -115,11 +247,110 @@ func migrate(db *sql.DB) error {
 	return nil
 }
 
+// searchDocumentSQL selects each property's searchable text: its address,
+// its type (a hand-edited one if set), and its comments and visit notes.
+const searchDocumentSQL = `SELECT id, address,
+	COALESCE((SELECT value FROM property_overrides WHERE property_id = properties.id AND field = 'property_type'), property_type, ''),
+	COALESCE((SELECT group_concat(text, char(10)) FROM comments WHERE property_id = properties.id), ''),
+	COALESCE((SELECT group_concat(notes, char(10)) FROM visits WHERE property_id = properties.id AND notes != ''), '')
+	FROM properties`
+
+// searchMigrations create the FTS5 full-text index over properties, one
+// row per property keyed by rowid = properties.id, and the triggers that
+// rebuild a property's row whenever anything it indexes changes.
+// mattn/go-sqlite3 only includes FTS5 when built with -tags sqlite_fts5.
+func searchMigrations() []string {
+	reindex := func(id string) string {
+		return fmt.Sprintf(`DELETE FROM property_search WHERE rowid = %[1]s;
+			INSERT INTO property_search (rowid, address, property_type, comments, visit_notes)
+			%[2]s WHERE id = %[1]s;`, id, searchDocumentSQL)
+	}
+	stmts := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS property_search USING fts5(
+			address, property_type, comments, visit_notes, tokenize = 'unicode61'
+		)`,
+		// Index properties added before the index existed.
+		`INSERT INTO property_search (rowid, address, property_type, comments, visit_notes)
+			` + searchDocumentSQL + ` WHERE id NOT IN (SELECT rowid FROM property_search)`,
+	}
+	triggers := []struct {
+		name, event string
+		ids         []string
+	}{
+		{"property_search_insert", "AFTER INSERT ON properties", []string{"NEW.id"}},
+		{"property_search_update", "AFTER UPDATE OF address, property_type ON properties", []string{"NEW.id"}},
+		{"property_search_delete", "AFTER DELETE ON properties", []string{"OLD.id"}},
+		{"comment_search_insert", "AFTER INSERT ON comments", []string{"NEW.property_id"}},
+		{"comment_search_update", "AFTER UPDATE ON comments", []string{"OLD.property_id", "NEW.property_id"}},
+		{"comment_search_delete", "AFTER DELETE ON comments", []string{"OLD.property_id"}},
+		{"visit_search_insert", "AFTER INSERT ON visits", []string{"NEW.property_id"}},
+		{"visit_search_update", "AFTER UPDATE ON visits", []string{"OLD.property_id", "NEW.property_id"}},
+		{"visit_search_delete", "AFTER DELETE ON visits", []string{"OLD.property_id"}},
+		{"override_search_insert", "AFTER INSERT ON property_overrides WHEN NEW.field = 'property_type'", []string{"NEW.property_id"}},
+		{"override_search_update", "AFTER UPDATE ON property_overrides WHEN 'property_type' IN (OLD.field, NEW.field)", []string{"OLD.property_id", "NEW.property_id"}},
+		{"override_search_delete", "AFTER DELETE ON property_overrides WHEN OLD.field = 'property_type'", []string{"OLD.property_id"}},
+	}
+	for _, t := range triggers {
+		var body string
+		for _, id := range t.ids {
+			body += reindex(id)
+		}
+		stmts = append(stmts, fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s %s BEGIN %s END", t.name, t.event, body))
+	}
+	return stmts
+}
+
+// dropLegacyRatings moves ratings from properties.rating, which predates
+// per-member ratings, into property_ratings, unattributed, then drops the
+// column so nothing can write a rating there again. It does nothing once
+// the column is gone, and new databases never have it.
+func dropLegacyRatings(db *sql.DB) (err error) {
+	exists, err := columnExists(db, "properties", "rating")
+	if err != nil || !exists {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO property_ratings (property_id, email, rating)
+		SELECT id, '', rating FROM properties WHERE rating IS NOT NULL`); err != nil {
+		return fmt.Errorf("copying ratings: %w", err)
+	}
+	if _, err := tx.Exec(`ALTER TABLE properties DROP COLUMN rating`); err != nil {
+		return fmt.Errorf("dropping properties.rating: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
 // addColumnIfNotExists adds a column to a table if it doesn't already exist.
 func addColumnIfNotExists(db *sql.DB, table, column, definition string) error {
+	exists, err := columnExists(db, table, column)
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// columnExists reports whether table has a column named column.
+func columnExists(db *sql.DB, table, column string) (bool, error) {
 	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return fmt.Errorf("checking table info: %w", err)
+		return false, fmt.Errorf("checking table info: %w", err)
 	}
 	defer func() {
 		if cerr := rows.Close(); cerr != nil {
@@ -133,16 +364,14 @@ func addColumnIfNotExists(db *sql.DB, table, column, definition string) error {
 		var notNull, pk int
 		var dfltValue interface{}
 		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
-			return fmt.Errorf("scanning column info: %w", err)
+			return false, fmt.Errorf("scanning column info: %w", err)
 		}
 		if name == column {
-			return nil // column already exists
+			return true, nil
 		}
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterating columns: %w", err)
+		return false, fmt.Errorf("iterating columns: %w", err)
 	}
-
-	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
-	return err
+	return false, nil
 }
@@ -60,6 +60,14 @@ func FormatEmail(props []PropertyWithComments, baseURL string) string {
 			fmt.Fprintf(&buf, "   %s\n", strings.Join(details, " | "))
 		}
 
+		if len(p.Distances) > 0 {
+			var distances []string
+			for _, d := range p.Distances {
+				distances = append(distances, fmt.Sprintf("%s %.1f mi (%s)", d.Place, d.Miles, d.Drive))
+			}
+			fmt.Fprintf(&buf, "   %s\n", strings.Join(distances, " | "))
+		}
+
 		if p.RealtorURL != "" {
 			url := p.RealtorURL
 			if !strings.HasPrefix(url, "http") {
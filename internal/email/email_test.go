@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
 )
 
@@ -20,6 +21,7 @@ func TestFormatEmail(t *testing.T) {
 				Bathrooms:  ptr(float64(2)),
 				Sqft:       ptr(int64(1500)),
 				RealtorURL: "/realestateandhomes-detail/123-Main-St",
+				Distances:  []place.Distance{{Place: "work", Miles: 12.3, Drive: "20-30 min"}},
 			},
 			Comments: []*comment.Comment{
 				{Text: "Love the backyard"},
@@ -61,6 +63,9 @@ func TestFormatEmail(t *testing.T) {
 	if !strings.Contains(body, "1,500 sqft") {
 		t.Error("expected sqft")
 	}
+	if !strings.Contains(body, "work 12.3 mi (20-30 min)") {
+		t.Error("expected distance to work")
+	}
 
 	// Check realtor URL
 	if !strings.Contains(body, "https://www.realtor.com/realestateandhomes-detail/123-Main-St") {
@@ -0,0 +1,119 @@
+// Package finance provides each user's mortgage assumptions and the
+// estimated monthly cost of owning a property under them.
+package finance
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Profile holds one user's financing assumptions. Percentages are given
+// as whole numbers, so 6.5 means 6.5%.
+type Profile struct {
+	Email               string    `json:"email"`
+	DownPaymentPercent  float64   `json:"down_payment_percent"`
+	RatePercent         float64   `json:"rate_percent"`          // annual interest rate
+	TermYears           int       `json:"term_years"`            // loan length
+	InsuranceRate       float64   `json:"insurance_rate"`        // % of price per year
+	PMIRate             float64   `json:"pmi_rate"`              // % of the loan per year
+	PMIThresholdPercent float64   `json:"pmi_threshold_percent"` // PMI applies below this down payment
+	TaxRate             float64   `json:"tax_rate"`              // % of price per year, when the listing has no tax record
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// DefaultProfile returns the assumptions used until a user saves their own:
+// 20% down on a 30-year loan at 6.5%.
+func DefaultProfile(email string) *Profile {
+	return &Profile{
+		Email:               email,
+		DownPaymentPercent:  20,
+		RatePercent:         6.5,
+		TermYears:           30,
+		InsuranceRate:       0.5,
+		PMIRate:             0.5,
+		PMIThresholdPercent: 20,
+		TaxRate:             1.0,
+	}
+}
+
+// Validate checks that the assumptions are in a sensible range.
+func (p *Profile) Validate() error {
+	switch {
+	case p.DownPaymentPercent < 0 || p.DownPaymentPercent > 100:
+		return fmt.Errorf("down payment must be 0-100%%, got %g", p.DownPaymentPercent)
+	case p.RatePercent < 0 || p.RatePercent > 30:
+		return fmt.Errorf("interest rate must be 0-30%%, got %g", p.RatePercent)
+	case p.TermYears < 1 || p.TermYears > 50:
+		return fmt.Errorf("term must be 1-50 years, got %d", p.TermYears)
+	case p.InsuranceRate < 0 || p.InsuranceRate > 10:
+		return fmt.Errorf("insurance rate must be 0-10%%, got %g", p.InsuranceRate)
+	case p.PMIRate < 0 || p.PMIRate > 10:
+		return fmt.Errorf("PMI rate must be 0-10%%, got %g", p.PMIRate)
+	case p.PMIThresholdPercent < 0 || p.PMIThresholdPercent > 100:
+		return fmt.Errorf("PMI threshold must be 0-100%%, got %g", p.PMIThresholdPercent)
+	case p.TaxRate < 0 || p.TaxRate > 10:
+		return fmt.Errorf("tax rate must be 0-10%%, got %g", p.TaxRate)
+	}
+	return nil
+}
+
+// Cost is the estimated monthly cost of owning a property. Monthly
+// amounts are whole dollars.
+type Cost struct {
+	Price             int64 `json:"price"`
+	DownPayment       int64 `json:"down_payment"`
+	LoanAmount        int64 `json:"loan_amount"`
+	PrincipalInterest int64 `json:"principal_interest"`
+	Taxes             int64 `json:"taxes"`
+	TaxesEstimated    bool  `json:"taxes_estimated"` // no tax record, so taxes use the profile's tax rate
+	Insurance         int64 `json:"insurance"`
+	PMI               int64 `json:"pmi"`
+	HOA               int64 `json:"hoa"`
+	Total             int64 `json:"total"`
+}
+
+// Monthly estimates the monthly cost of a house at price. annualTax and
+// hoaFee (per month) come from the listing and may be nil.
+func (p *Profile) Monthly(price int64, annualTax, hoaFee *int64) Cost {
+	c := Cost{Price: price}
+
+	down := float64(price) * p.DownPaymentPercent / 100
+	loan := float64(price) - down
+	c.DownPayment = int64(math.Round(down))
+	c.LoanAmount = int64(math.Round(loan))
+	c.PrincipalInterest = dollars(payment(loan, p.RatePercent, p.TermYears))
+
+	if annualTax != nil {
+		c.Taxes = dollars(float64(*annualTax) / 12)
+	} else {
+		c.Taxes = dollars(float64(price) * p.TaxRate / 100 / 12)
+		c.TaxesEstimated = true
+	}
+
+	c.Insurance = dollars(float64(price) * p.InsuranceRate / 100 / 12)
+	if p.DownPaymentPercent < p.PMIThresholdPercent {
+		c.PMI = dollars(loan * p.PMIRate / 100 / 12)
+	}
+	if hoaFee != nil {
+		c.HOA = *hoaFee
+	}
+
+	c.Total = c.PrincipalInterest + c.Taxes + c.Insurance + c.PMI + c.HOA
+	return c
+}
+
+// payment is the fixed monthly principal and interest on a fully
+// amortizing loan.
+func payment(loan, ratePercent float64, years int) float64 {
+	n := float64(years * 12)
+	r := ratePercent / 100 / 12
+	if r == 0 {
+		return loan / n
+	}
+	return loan * r / (1 - math.Pow(1+r, -n))
+}
+
+func dollars(v float64) int64 {
+	return int64(math.Round(v))
+}
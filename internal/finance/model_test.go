@@ -0,0 +1,79 @@
+package finance
+
+import "testing"
+
+func TestMonthly(t *testing.T) {
+	tax, hoa := int64(3600), int64(45)
+
+	tests := []struct {
+		name    string
+		profile func(p *Profile)
+		tax     *int64
+		hoa     *int64
+		want    Cost
+	}{
+		{
+			name: "20% down with tax and HOA",
+			tax:  &tax,
+			hoa:  &hoa,
+			want: Cost{Price: 300000, DownPayment: 60000, LoanAmount: 240000, PrincipalInterest: 1517, Taxes: 300, Insurance: 125, HOA: 45, Total: 1987},
+		},
+		{
+			name:    "under the PMI threshold, taxes estimated",
+			profile: func(p *Profile) { p.DownPaymentPercent = 10 },
+			want:    Cost{Price: 300000, DownPayment: 30000, LoanAmount: 270000, PrincipalInterest: 1707, Taxes: 250, TaxesEstimated: true, Insurance: 125, PMI: 113, Total: 2195},
+		},
+		{
+			name: "zero interest",
+			profile: func(p *Profile) {
+				p.RatePercent = 0
+				p.TermYears = 20
+				p.InsuranceRate = 0
+			},
+			tax:  &tax,
+			want: Cost{Price: 300000, DownPayment: 60000, LoanAmount: 240000, PrincipalInterest: 1000, Taxes: 300, Total: 1300},
+		},
+		{
+			name:    "cash purchase",
+			profile: func(p *Profile) { p.DownPaymentPercent = 100 },
+			tax:     &tax,
+			want:    Cost{Price: 300000, DownPayment: 300000, Taxes: 300, Insurance: 125, Total: 425},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultProfile("a@example.com")
+			if tt.profile != nil {
+				tt.profile(p)
+			}
+			if got := p.Monthly(300000, tt.tax, tt.hoa); got != tt.want {
+				t.Errorf("Monthly =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile func(p *Profile)
+		wantErr bool
+	}{
+		{"defaults", func(p *Profile) {}, false},
+		{"negative down payment", func(p *Profile) { p.DownPaymentPercent = -5 }, true},
+		{"rate over 30", func(p *Profile) { p.RatePercent = 65 }, true},
+		{"zero term", func(p *Profile) { p.TermYears = 0 }, true},
+		{"negative PMI", func(p *Profile) { p.PMIRate = -1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultProfile("")
+			tt.profile(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
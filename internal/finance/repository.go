@@ -0,0 +1,64 @@
+package finance
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Repository stores each user's financing profile.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a financing profile repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Get returns the user's saved profile, or DefaultProfile if they
+// haven't saved one.
+func (r *Repository) Get(email string) (*Profile, error) {
+	var p Profile
+	err := r.db.QueryRow(
+		`SELECT email, down_payment_percent, rate_percent, term_years, insurance_rate,
+			pmi_rate, pmi_threshold_percent, tax_rate, updated_at
+		FROM financing_profiles WHERE email = ?`, email,
+	).Scan(&p.Email, &p.DownPaymentPercent, &p.RatePercent, &p.TermYears, &p.InsuranceRate,
+		&p.PMIRate, &p.PMIThresholdPercent, &p.TaxRate, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return DefaultProfile(email), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting financing profile: %w", err)
+	}
+	return &p, nil
+}
+
+// Save validates and stores a user's profile, replacing any earlier one.
+func (r *Repository) Save(p *Profile) (*Profile, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err := r.db.Exec(
+		`INSERT INTO financing_profiles (email, down_payment_percent, rate_percent, term_years,
+			insurance_rate, pmi_rate, pmi_threshold_percent, tax_rate, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(email) DO UPDATE SET
+			down_payment_percent = excluded.down_payment_percent,
+			rate_percent = excluded.rate_percent,
+			term_years = excluded.term_years,
+			insurance_rate = excluded.insurance_rate,
+			pmi_rate = excluded.pmi_rate,
+			pmi_threshold_percent = excluded.pmi_threshold_percent,
+			tax_rate = excluded.tax_rate,
+			updated_at = excluded.updated_at`,
+		p.Email, p.DownPaymentPercent, p.RatePercent, p.TermYears,
+		p.InsuranceRate, p.PMIRate, p.PMIThresholdPercent, p.TaxRate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("saving financing profile: %w", err)
+	}
+
+	return r.Get(p.Email)
+}
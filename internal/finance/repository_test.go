@@ -0,0 +1,70 @@
+package finance
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/db"
+)
+
+func testRepo(t *testing.T) *Repository {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return NewRepository(d)
+}
+
+func TestGetSave(t *testing.T) {
+	repo := testRepo(t)
+
+	p, err := repo.Get("a@example.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if *p != *DefaultProfile("a@example.com") {
+		t.Errorf("unsaved profile = %+v, want defaults", p)
+	}
+
+	p.DownPaymentPercent = 5
+	p.RatePercent = 7.25
+	saved, err := repo.Save(p)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if saved.DownPaymentPercent != 5 || saved.RatePercent != 7.25 || saved.UpdatedAt.IsZero() {
+		t.Errorf("saved profile = %+v", saved)
+	}
+
+	p.TermYears = 15
+	if _, err := repo.Save(p); err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+	got, err := repo.Get("a@example.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.TermYears != 15 || got.DownPaymentPercent != 5 {
+		t.Errorf("updated profile = %+v", got)
+	}
+
+	// Profiles are per user.
+	other, err := repo.Get("b@example.com")
+	if err != nil {
+		t.Fatalf("get other: %v", err)
+	}
+	if other.DownPaymentPercent != 20 {
+		t.Errorf("other user's down payment = %g, want default 20", other.DownPaymentPercent)
+	}
+
+	p.TermYears = 0
+	if _, err := repo.Save(p); err == nil {
+		t.Error("expected validation error for zero term")
+	}
+}
@@ -0,0 +1,288 @@
+// Package jobs runs periodic background work, such as auth cleanup and
+// listing refreshes, inside the serve process.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// minLease is the shortest lock a run takes. A run is cancelled when its
+// lease ends, so a lock left by a crashed process expires on its own.
+const minLease = time.Minute
+
+// Job is a unit of periodic work.
+type Job struct {
+	Name string
+
+	// Interval is the time between the starts of successive runs. Zero
+	// disables the job.
+	Interval time.Duration
+
+	// Run does the work. It should return promptly once ctx is done.
+	Run func(ctx context.Context) error
+}
+
+// lease is how long a run may hold the job's lock: one interval, but at
+// least minLease.
+func (j *Job) lease() time.Duration {
+	return max(j.Interval, minLease)
+}
+
+// Status is a job's schedule and last run, for admins.
+type Status struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval,omitempty"` // empty when disabled
+	Enabled        bool       `json:"enabled"`
+	Running        bool       `json:"running"`
+	LastStarted    *time.Time `json:"last_started,omitempty"`
+	LastFinished   *time.Time `json:"last_finished,omitempty"`
+	LastStatus     string     `json:"last_status,omitempty"` // ok, failed or cancelled
+	LastError      string     `json:"last_error,omitempty"`
+	LastDurationMS int64      `json:"last_duration_ms"`
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	NextRun        *time.Time `json:"next_run,omitempty"`
+}
+
+// Scheduler runs registered jobs on their intervals. Each run takes a
+// lock in SQLite first, so a job never overlaps itself, even across
+// processes sharing the database. When a job is due is worked out from
+// its last recorded start, so restarts don't reset the clock.
+type Scheduler struct {
+	store  *store
+	holder string
+	now    func() time.Time
+
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+// NewScheduler creates a scheduler with no jobs.
+func NewScheduler(db *sql.DB) *Scheduler {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return &Scheduler{
+		store:  &store{db: db},
+		holder: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		now:    time.Now,
+	}
+}
+
+// Register adds a job. Names must be unique.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job needs a name and a run function")
+	}
+	if job.Interval < 0 {
+		return fmt.Errorf("job %s: interval must not be negative", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(job.Name) != nil {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.jobs = append(s.jobs, &job)
+	return nil
+}
+
+// SetInterval changes a registered job's interval; zero disables it. It
+// takes effect the next time Run starts.
+func (s *Scheduler) SetInterval(name string, interval time.Duration) error {
+	if interval < 0 {
+		return fmt.Errorf("job %s: interval must not be negative", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.find(name)
+	if job == nil {
+		return fmt.Errorf("unknown job %q", name)
+	}
+	job.Interval = interval
+	return nil
+}
+
+// find returns the job called name, or nil. The caller holds s.mu.
+func (s *Scheduler) find(name string) *Job {
+	for _, j := range s.jobs {
+		if j.Name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// snapshot returns copies of the registered jobs.
+func (s *Scheduler) snapshot() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, len(s.jobs))
+	for i, j := range s.jobs {
+		jobs[i] = *j
+	}
+	return jobs
+}
+
+// Run runs every enabled job whenever it is due until ctx is cancelled,
+// then waits for runs in progress, which see ctx cancelled, to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.snapshot() {
+		if job.Interval <= 0 {
+			continue
+		}
+		slog.Info("job scheduled", "job", job.Name, "interval", job.Interval.String())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop runs job each time it comes due until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		due, err := s.nextRun(job)
+		if err != nil {
+			slog.Error("job schedule failed", "job", job.Name, "err", err)
+			due = s.now().Add(job.Interval)
+		}
+
+		timer := time.NewTimer(due.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runOnce(ctx, job)
+	}
+}
+
+// nextRun returns when job is next due: one interval after its last
+// start, or when another holder's lock runs out if that is later. A job
+// that has never run is due now.
+func (s *Scheduler) nextRun(job Job) (time.Time, error) {
+	now := s.now()
+	rec, err := s.store.get(job.Name)
+	if err != nil || rec == nil {
+		return now, err
+	}
+
+	due := now
+	if rec.lastStarted.Valid {
+		due = rec.lastStarted.Time.Add(job.Interval)
+	}
+	if rec.lockedUntil.Valid && rec.lockedUntil.Time.After(due) {
+		due = rec.lockedUntil.Time
+	}
+	return due, nil
+}
+
+// runOnce runs job if no one else holds its lock, and records the outcome.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	start := s.now()
+	lease := job.lease()
+	acquired, err := s.store.acquire(job.Name, s.holder, start, start.Add(lease))
+	if err != nil {
+		slog.Error("job lock failed", "job", job.Name, "err", err)
+		return
+	}
+	if !acquired {
+		slog.Debug("job already running elsewhere", "job", job.Name)
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, lease)
+	err = runJob(runCtx, job)
+	cancel()
+	took := s.now().Sub(start)
+
+	status, msg := StatusOK, ""
+	switch {
+	case err == nil:
+		slog.Info("job finished", "job", job.Name, "duration", took.String())
+	case ctx.Err() != nil:
+		status, msg = StatusCancelled, err.Error()
+		slog.Info("job cancelled", "job", job.Name, "duration", took.String())
+	default:
+		status, msg = StatusFailed, err.Error()
+		slog.Error("job failed", "job", job.Name, "duration", took.String(), "err", err)
+	}
+
+	if err := s.store.finish(job.Name, s.holder, s.now(), took, status, msg); err != nil {
+		slog.Error("job record failed", "job", job.Name, "err", err)
+	}
+}
+
+// runJob calls job.Run, turning a panic into an error so one bad job
+// doesn't take the server down.
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Status returns every registered job's schedule and last run, in
+// registration order.
+func (s *Scheduler) Status() ([]Status, error) {
+	now := s.now()
+	jobs := s.snapshot()
+	statuses := make([]Status, 0, len(jobs))
+	for _, job := range jobs {
+		st := Status{Name: job.Name, Enabled: job.Interval > 0}
+		if st.Enabled {
+			st.Interval = job.Interval.String()
+		}
+
+		rec, err := s.store.get(job.Name)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			st.Running = rec.lockedUntil.Valid && rec.lockedUntil.Time.After(now)
+			st.LastStarted = timePtr(rec.lastStarted)
+			st.LastFinished = timePtr(rec.lastFinished)
+			st.LastStatus = rec.lastStatus
+			st.LastError = rec.lastError
+			st.LastDurationMS = rec.lastDurationMS
+			st.Runs = rec.runs
+			st.Failures = rec.failures
+		}
+
+		if st.Enabled {
+			next, err := s.nextRun(job)
+			if err != nil {
+				return nil, err
+			}
+			if next.Before(now) {
+				next = now
+			}
+			st.NextRun = &next
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
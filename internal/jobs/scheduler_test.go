@@ -0,0 +1,223 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/db"
+)
+
+func TestRunOnceRecordsOutcome(t *testing.T) {
+	s := NewScheduler(testDB(t))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	jobs := []Job{
+		{Name: "ok", Interval: time.Hour, Run: func(ctx context.Context) error { return nil }},
+		{Name: "fails", Interval: time.Hour, Run: func(ctx context.Context) error { return errors.New("smtp down") }},
+		{Name: "panics", Interval: time.Hour, Run: func(ctx context.Context) error { panic("boom") }},
+		{Name: "off", Run: func(ctx context.Context) error { return nil }},
+	}
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			t.Fatalf("register %s: %v", job.Name, err)
+		}
+	}
+	if err := s.Register(jobs[0]); err == nil {
+		t.Error("expected error registering a duplicate name")
+	}
+
+	for _, job := range jobs[:3] {
+		s.runOnce(context.Background(), job)
+	}
+
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if len(statuses) != 4 {
+		t.Fatalf("got %d statuses, want 4", len(statuses))
+	}
+
+	tests := []struct {
+		status, err string
+		failures    int
+	}{
+		{StatusOK, "", 0},
+		{StatusFailed, "smtp down", 1},
+		{StatusFailed, "panic: boom", 1},
+	}
+	for i, tt := range tests {
+		st := statuses[i]
+		if st.LastStatus != tt.status || st.LastError != tt.err || st.Runs != 1 || st.Failures != tt.failures {
+			t.Errorf("%s status = %+v, want %s (%q) after 1 run", st.Name, st, tt.status, tt.err)
+		}
+		if st.Running {
+			t.Errorf("%s still marked running", st.Name)
+		}
+		if st.LastStarted == nil || !st.LastStarted.Equal(now) {
+			t.Errorf("%s last started = %v, want %v", st.Name, st.LastStarted, now)
+		}
+		if st.NextRun == nil || !st.NextRun.Equal(now.Add(time.Hour)) {
+			t.Errorf("%s next run = %v, want %v", st.Name, st.NextRun, now.Add(time.Hour))
+		}
+	}
+
+	off := statuses[3]
+	if off.Enabled || off.Interval != "" || off.NextRun != nil || off.Runs != 0 {
+		t.Errorf("disabled job status = %+v", off)
+	}
+}
+
+func TestRunOnceSingleFlight(t *testing.T) {
+	d := testDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var runs int32
+	job := Job{Name: "refresh", Interval: 10 * time.Minute, Run: func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}}
+
+	// Another process holds the lock, as if mid-run or crashed.
+	other := NewScheduler(d)
+	other.holder = "other:1"
+	other.now = clock
+	if ok, err := other.store.acquire(job.Name, other.holder, now, now.Add(job.lease())); err != nil || !ok {
+		t.Fatalf("acquire = %v, %v", ok, err)
+	}
+
+	s := NewScheduler(d)
+	s.now = clock
+	if err := s.Register(job); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	s.runOnce(context.Background(), job)
+	if got := atomic.LoadInt32(&runs); got != 0 {
+		t.Fatalf("ran %d times while locked elsewhere, want 0", got)
+	}
+
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !statuses[0].Running {
+		t.Error("status not marked running while locked")
+	}
+
+	// Once the other holder's lease runs out, the lock can be taken over.
+	now = now.Add(job.lease() + time.Second)
+	if next, err := s.nextRun(job); err != nil || next.After(now) {
+		t.Fatalf("next run = %v, %v; want due by %v", next, err, now)
+	}
+	s.runOnce(context.Background(), job)
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		t.Errorf("ran %d times after lease expired, want 1", got)
+	}
+}
+
+func TestNextRunSurvivesRestart(t *testing.T) {
+	d := testDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := Job{Name: "cleanup", Interval: time.Hour, Run: func(ctx context.Context) error { return nil }}
+
+	s := NewScheduler(d)
+	s.now = func() time.Time { return now }
+	if next, err := s.nextRun(job); err != nil || !next.Equal(now) {
+		t.Fatalf("first next run = %v, %v; want now", next, err)
+	}
+	s.runOnce(context.Background(), job)
+
+	restarted := NewScheduler(d)
+	restarted.now = func() time.Time { return now.Add(20 * time.Minute) }
+	next, err := restarted.nextRun(job)
+	if err != nil {
+		t.Fatalf("next run: %v", err)
+	}
+	if !next.Equal(now.Add(time.Hour)) {
+		t.Errorf("next run after restart = %v, want %v", next, now.Add(time.Hour))
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	s := NewScheduler(testDB(t))
+
+	started := make(chan struct{})
+	if err := s.Register(Job{Name: "slow", Interval: time.Hour, Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if statuses[0].LastStatus != StatusCancelled || statuses[0].Running {
+		t.Errorf("status = %+v, want cancelled and not running", statuses[0])
+	}
+}
+
+func TestSetInterval(t *testing.T) {
+	s := NewScheduler(testDB(t))
+	if err := s.Register(Job{Name: "digest", Run: func(ctx context.Context) error { return nil }}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if err := s.SetInterval("digest", 6*time.Hour); err != nil {
+		t.Fatalf("set interval: %v", err)
+	}
+	if err := s.SetInterval("nope", time.Hour); err == nil {
+		t.Error("expected error for unknown job")
+	}
+	if err := s.SetInterval("digest", -time.Hour); err == nil {
+		t.Error("expected error for negative interval")
+	}
+
+	statuses, err := s.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !statuses[0].Enabled || statuses[0].Interval != "6h0m0s" {
+		t.Errorf("status = %+v, want enabled every 6h", statuses[0])
+	}
+}
+
+// testDB opens a migrated SQLite database in a temp directory.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return d
+}
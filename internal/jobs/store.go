@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// timeFormat is how lock and run times are written: UTC with fixed-width
+// milliseconds, so they compare correctly as strings in SQL.
+const timeFormat = "2006-01-02 15:04:05.000"
+
+// Run outcomes recorded in last_status.
+const (
+	StatusOK        = "ok"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// store keeps each job's lock and last-run record in the jobs table, so
+// that only one process runs a job at a time and status survives restarts.
+type store struct {
+	db *sql.DB
+}
+
+// record is a job's row in the jobs table.
+type record struct {
+	lockedBy       string
+	lockedUntil    sql.NullTime
+	lastStarted    sql.NullTime
+	lastFinished   sql.NullTime
+	lastStatus     string
+	lastError      string
+	lastDurationMS int64
+	runs           int
+	failures       int
+}
+
+// get returns the record for name, or nil if the job has never run.
+func (s *store) get(name string) (*record, error) {
+	var r record
+	err := s.db.QueryRow(
+		`SELECT locked_by, locked_until, last_started_at, last_finished_at, last_status, last_error, last_duration_ms, runs, failures
+		 FROM jobs WHERE name = ?`, name,
+	).Scan(&r.lockedBy, &r.lockedUntil, &r.lastStarted, &r.lastFinished, &r.lastStatus, &r.lastError, &r.lastDurationMS, &r.runs, &r.failures)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading job %s: %w", name, err)
+	}
+	return &r, nil
+}
+
+// acquire takes the lock on name for holder until the lease ends and
+// marks the run started. It reports false if another holder's lease is
+// still live.
+func (s *store) acquire(name, holder string, now, until time.Time) (bool, error) {
+	if _, err := s.db.Exec("INSERT OR IGNORE INTO jobs (name) VALUES (?)", name); err != nil {
+		return false, fmt.Errorf("creating job %s: %w", name, err)
+	}
+
+	result, err := s.db.Exec(
+		`UPDATE jobs SET locked_by = ?, locked_until = ?, last_started_at = ?
+		 WHERE name = ? AND (locked_until IS NULL OR locked_until < ?)`,
+		holder, formatTime(until), formatTime(now), name, formatTime(now),
+	)
+	if err != nil {
+		return false, fmt.Errorf("locking job %s: %w", name, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("checking rows affected: %w", err)
+	}
+	return n == 1, nil
+}
+
+// finish records the outcome of a run and releases holder's lock.
+func (s *store) finish(name, holder string, finished time.Time, took time.Duration, status, errMsg string) error {
+	failed := 0
+	if status == StatusFailed {
+		failed = 1
+	}
+	_, err := s.db.Exec(
+		`UPDATE jobs SET locked_by = '', locked_until = NULL, last_finished_at = ?, last_status = ?,
+		 last_error = ?, last_duration_ms = ?, runs = runs + 1, failures = failures + ?
+		 WHERE name = ? AND locked_by = ?`,
+		formatTime(finished), status, errMsg, took.Milliseconds(), failed, name, holder,
+	)
+	if err != nil {
+		return fmt.Errorf("recording job %s: %w", name, err)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
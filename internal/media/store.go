@@ -0,0 +1,292 @@
+// Package media caches listing photos on local disk so they keep loading
+// after a listing is pulled from realtor.com, and serves resized thumbnails.
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders for thumbnailing
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ThumbWidth is the width in pixels of generated thumbnails.
+	ThumbWidth = 320
+
+	// maxPhotoBytes caps a single download so a bad URL can't fill the disk.
+	maxPhotoBytes = 20 << 20
+
+	userAgent = "Mozilla/5.0"
+)
+
+// Store downloads photos once into a directory and serves them from there.
+// Files are named by a hash of the source URL, so the same photo shared by
+// two listings is stored once.
+type Store struct {
+	dir        string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewStore creates a photo store rooted at dir, creating it if needed.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating media directory %s: %w", dir, err)
+	}
+	return &Store{
+		dir:        dir,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		locks:      make(map[string]*sync.Mutex),
+	}, nil
+}
+
+// Original returns the local path of the full-size photo for url,
+// downloading it on first use.
+func (s *Store) Original(url string) (string, error) {
+	k := key(url)
+	unlock := s.lock(k)
+	defer unlock()
+
+	return s.original(url, k)
+}
+
+// Thumbnail returns the local path of a ThumbWidth-wide JPEG of the photo
+// at url, downloading and resizing on first use. Photos already narrower
+// than ThumbWidth, or in a format that can't be decoded, are served as-is.
+func (s *Store) Thumbnail(url string) (string, error) {
+	k := key(url)
+	unlock := s.lock(k)
+	defer unlock()
+
+	thumb := filepath.Join(s.dir, k+"_thumb.jpg")
+	if exists(thumb) {
+		return thumb, nil
+	}
+
+	orig, err := s.original(url, k)
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := writeThumbnail(orig, thumb)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return orig, nil
+	}
+	return thumb, nil
+}
+
+// Prefetch downloads any of urls not already stored. Failures are logged
+// and skipped; it returns how many photos were newly downloaded.
+func (s *Store) Prefetch(urls []string) int {
+	var fetched int
+	for _, url := range urls {
+		k := key(url)
+		unlock := s.lock(k)
+		path := filepath.Join(s.dir, k)
+		if !exists(path) {
+			if _, err := s.original(url, k); err != nil {
+				slog.Warn("photo prefetch failed", "url", url, "err", err)
+			} else {
+				fetched++
+			}
+		}
+		unlock()
+	}
+	return fetched
+}
+
+// original returns the stored photo for url, downloading it if missing.
+// The caller must hold the lock for k.
+func (s *Store) original(url, k string) (string, error) {
+	path := filepath.Join(s.dir, k)
+	if exists(path) {
+		return path, nil
+	}
+	if err := s.download(url, path); err != nil {
+		return "", fmt.Errorf("downloading photo: %w", err)
+	}
+	return path, nil
+}
+
+// download fetches url into path via a temp file, so a failed or partial
+// download never leaves a truncated photo behind.
+func (s *Store) download(url, path string) (err error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("unsupported photo URL %q", url)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			err = fmt.Errorf("%w (also failed to close body: %v)", err, closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "image/") {
+		return fmt.Errorf("unexpected content type %q", ct)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, maxPhotoBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing photo: %w", err)
+	}
+	if n > maxPhotoBytes {
+		return fmt.Errorf("photo larger than %d bytes", maxPhotoBytes)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("saving photo: %w", err)
+	}
+	return nil
+}
+
+// lock serializes work on one photo so concurrent requests download it once.
+func (s *Store) lock(k string) func() {
+	s.mu.Lock()
+	m, ok := s.locks[k]
+	if !ok {
+		m = &sync.Mutex{}
+		s.locks[k] = m
+	}
+	s.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// writeThumbnail resizes the image at src into a JPEG at dst. It reports
+// false, without error, when src can't be decoded or is already small.
+func writeThumbnail(src, dst string) (ok bool, err error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return false, fmt.Errorf("opening photo: %w", err)
+	}
+	img, _, decodeErr := image.Decode(f)
+	if closeErr := f.Close(); closeErr != nil {
+		return false, fmt.Errorf("closing photo: %w", closeErr)
+	}
+	if decodeErr != nil || img.Bounds().Dx() <= ThumbWidth {
+		return false, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".thumb-*")
+	if err != nil {
+		return false, fmt.Errorf("creating temp file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	err = jpeg.Encode(tmp, resize(img, ThumbWidth), &jpeg.Options{Quality: 80})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, fmt.Errorf("writing thumbnail: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), dst); err != nil {
+		return false, fmt.Errorf("saving thumbnail: %w", err)
+	}
+	return true, nil
+}
+
+// resize scales img to width, keeping its aspect ratio, by averaging the
+// source pixels that fall in each destination pixel.
+func resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := b.Min.Y + (y+1)*b.Dy()/height
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := b.Min.X + (x+1)*b.Dx()/width
+			if x1 == x0 {
+				x1++
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// key names a photo's files after its source URL.
+func key(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:16])
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
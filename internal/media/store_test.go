@@ -0,0 +1,169 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// photoServer serves a PNG of the given size at /photo.png and counts hits.
+func photoServer(t *testing.T, width, height int) (*httptest.Server, *int32) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		switch r.URL.Path {
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(buf.Bytes()) //nolint:errcheck // test server
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>")) //nolint:errcheck // test server
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func testStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return s
+}
+
+func TestOriginalDownloadsOnce(t *testing.T) {
+	srv, hits := photoServer(t, 40, 20)
+	s := testStore(t)
+
+	for i := 0; i < 3; i++ {
+		path, err := s.Original(srv.URL + "/photo.png")
+		if err != nil {
+			t.Fatalf("original: %v", err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("stat %s: %v", path, err)
+		}
+	}
+	if got := atomic.LoadInt32(hits); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+}
+
+func TestOriginalErrors(t *testing.T) {
+	srv, _ := photoServer(t, 40, 20)
+	s := testStore(t)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"not found", srv.URL + "/missing.jpg"},
+		{"not an image", srv.URL + "/page.html"},
+		{"not http", "file:///etc/passwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Original(tt.url); err == nil {
+				t.Error("expected error, got nil")
+			}
+			if _, err := os.Stat(filepath.Join(s.dir, key(tt.url))); err == nil {
+				t.Error("failed download left a file behind")
+			}
+		})
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     int
+		height    int
+		wantWidth int
+		wantThumb bool
+	}{
+		{"large photo is resized", 800, 600, ThumbWidth, true},
+		{"small photo is served as-is", 100, 50, 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := photoServer(t, tt.width, tt.height)
+			s := testStore(t)
+			url := srv.URL + "/photo.png"
+
+			path, err := s.Thumbnail(url)
+			if err != nil {
+				t.Fatalf("thumbnail: %v", err)
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			defer f.Close() //nolint:errcheck // read-only test file
+
+			var cfg image.Config
+			if tt.wantThumb {
+				cfg, err = jpeg.DecodeConfig(f)
+			} else {
+				cfg, err = png.DecodeConfig(f)
+			}
+			if err != nil {
+				t.Fatalf("decode config: %v", err)
+			}
+			if cfg.Width != tt.wantWidth {
+				t.Errorf("width = %d, want %d", cfg.Width, tt.wantWidth)
+			}
+			if tt.wantThumb && cfg.Height != tt.height*ThumbWidth/tt.width {
+				t.Errorf("height = %d, want %d", cfg.Height, tt.height*ThumbWidth/tt.width)
+			}
+
+			if _, err := s.Thumbnail(url); err != nil {
+				t.Fatalf("second thumbnail: %v", err)
+			}
+			if got := atomic.LoadInt32(hits); got != 1 {
+				t.Errorf("server hits = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	srv, hits := photoServer(t, 40, 20)
+	s := testStore(t)
+
+	urls := []string{srv.URL + "/photo.png", srv.URL + "/missing.jpg"}
+	if n := s.Prefetch(urls); n != 1 {
+		t.Errorf("first prefetch = %d, want 1", n)
+	}
+	if n := s.Prefetch(urls); n != 0 {
+		t.Errorf("second prefetch = %d, want 0", n)
+	}
+	// The missing photo is retried; the stored one is not.
+	if got := atomic.LoadInt32(hits); got != 3 {
+		t.Errorf("server hits = %d, want 3", got)
+	}
+}
@@ -0,0 +1,214 @@
+package mls
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cache stores RapidAPI listing responses in SQLite, keyed by realtor URL,
+// so repeated fetches of the same listing within the TTL are free.
+type Cache struct {
+	db  *sql.DB
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewCache creates a listing cache. Entries older than ttl are ignored
+// on read; they stay on disk until overwritten or cleared.
+func NewCache(db *sql.DB, ttl time.Duration) *Cache {
+	return &Cache{db: db, ttl: ttl, now: time.Now}
+}
+
+// CacheEntry describes a cached listing response.
+type CacheEntry struct {
+	MprID      string    `json:"mpr_id"`
+	RealtorURL string    `json:"realtor_url"`
+	Bytes      int       `json:"bytes"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Expired    bool      `json:"expired"`
+}
+
+// TTL returns how long entries stay fresh.
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get returns the cached result for realtorURL, or nil if there is no
+// fresh entry.
+func (c *Cache) Get(realtorURL string) (*Result, error) {
+	var res Result
+	var raw string
+	var fetchedAt time.Time
+	err := c.db.QueryRow(
+		"SELECT mpr_id, realtor_url, raw_json, fetched_at FROM mls_cache WHERE realtor_url = ?",
+		realtorURL,
+	).Scan(&res.MprID, &res.RealtorURL, &raw, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache: %w", err)
+	}
+
+	if c.now().After(fetchedAt.Add(c.ttl)) {
+		return nil, nil
+	}
+
+	res.RawJSON = json.RawMessage(raw)
+	return &res, nil
+}
+
+// Put stores a result, replacing any existing entry for its realtor URL.
+func (c *Cache) Put(res *Result) error {
+	_, err := c.db.Exec(
+		`INSERT INTO mls_cache (realtor_url, mpr_id, raw_json, fetched_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(realtor_url) DO UPDATE SET mpr_id = excluded.mpr_id, raw_json = excluded.raw_json, fetched_at = excluded.fetched_at`,
+		res.RealtorURL, res.MprID, string(res.RawJSON), c.now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	return nil
+}
+
+// List returns all cache entries, newest first.
+func (c *Cache) List() (entries []*CacheEntry, err error) {
+	rows, err := c.db.Query(
+		"SELECT mpr_id, realtor_url, LENGTH(raw_json), fetched_at FROM mls_cache ORDER BY fetched_at DESC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing cache: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	now := c.now()
+	for rows.Next() {
+		var e CacheEntry
+		if err := rows.Scan(&e.MprID, &e.RealtorURL, &e.Bytes, &e.FetchedAt); err != nil {
+			return nil, fmt.Errorf("scanning cache entry: %w", err)
+		}
+		e.ExpiresAt = e.FetchedAt.Add(c.ttl)
+		e.Expired = now.After(e.ExpiresAt)
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating cache: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Clear removes cached entries for mprID, or every entry if mprID is
+// empty. It returns the number of entries removed.
+func (c *Cache) Clear(mprID string) (int64, error) {
+	var result sql.Result
+	var err error
+	if mprID == "" {
+		result, err = c.db.Exec("DELETE FROM mls_cache")
+	} else {
+		result, err = c.db.Exec("DELETE FROM mls_cache WHERE mpr_id = ?", mprID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("clearing cache: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("checking rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// CachedProvider wraps a Provider with a Cache. Lookups go through the
+// cache only when the wrapped provider is both a Resolver and a Refresher,
+// so the free address resolution still runs and just the paid detail
+// fetch is skipped. Otherwise lookups pass through and are stored.
+type CachedProvider struct {
+	provider Provider
+	cache    *Cache
+	read     bool
+}
+
+// NewCachedProvider wraps provider with cache.
+func NewCachedProvider(provider Provider, cache *Cache) *CachedProvider {
+	return &CachedProvider{provider: provider, cache: cache, read: true}
+}
+
+// Cache returns the underlying cache.
+func (p *CachedProvider) Cache() *Cache {
+	return p.cache
+}
+
+// Bypass returns a provider that skips cache reads but still stores
+// fresh results.
+func (p *CachedProvider) Bypass() Provider {
+	return &CachedProvider{provider: p.provider, cache: p.cache, read: false}
+}
+
+// Lookup returns listing data for address, from the cache when fresh.
+func (p *CachedProvider) Lookup(ctx context.Context, address string) (*Result, error) {
+	resolver, canResolve := p.provider.(Resolver)
+	if _, canRefresh := p.provider.(Refresher); !canResolve || !canRefresh {
+		res, err := p.provider.Lookup(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.cache.Put(res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	mprID, realtorURL, err := resolver.Resolve(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return p.Refresh(ctx, mprID, realtorURL)
+}
+
+// Suggest passes through to the wrapped provider; suggestions are free
+// and never cached.
+func (p *CachedProvider) Suggest(ctx context.Context, address string, n int) ([]Candidate, error) {
+	s, ok := p.provider.(Suggester)
+	if !ok {
+		return nil, fmt.Errorf("listing provider does not support suggestions")
+	}
+	return s.Suggest(ctx, address, n)
+}
+
+// Refresh returns listing data for a known listing, from the cache when fresh.
+func (p *CachedProvider) Refresh(ctx context.Context, mprID, realtorURL string) (*Result, error) {
+	refresher, ok := p.provider.(Refresher)
+	if !ok {
+		return nil, fmt.Errorf("listing provider does not support refresh")
+	}
+
+	if p.read {
+		cached, err := p.cache.Get(realtorURL)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			cached.MprID = mprID
+			return cached, nil
+		}
+	}
+
+	res, err := refresher.Refresh(ctx, mprID, realtorURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.cache.Put(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
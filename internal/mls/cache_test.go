@@ -0,0 +1,156 @@
+package mls
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/db"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	cache := NewCache(testDB(t), time.Hour)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	got, err := cache.Get("/detail/1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected miss, got %+v", got)
+	}
+
+	if err := cache.Put(&Result{MprID: "M1", RealtorURL: "/detail/1", RawJSON: []byte(`{"list_price": 1}`)}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := cache.Put(&Result{MprID: "M1", RealtorURL: "/detail/1", RawJSON: []byte(`{"list_price": 2}`)}); err != nil {
+		t.Fatalf("put again: %v", err)
+	}
+
+	got, err = cache.Get("/detail/1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got == nil || string(got.RawJSON) != `{"list_price": 2}` {
+		t.Fatalf("got %+v, want latest entry", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	got, err = cache.Get("/detail/1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected expired entry to miss, got %+v", got)
+	}
+
+	entries, err := cache.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Expired || entries[0].Bytes != len(`{"list_price": 2}`) {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestCacheClear(t *testing.T) {
+	cache := NewCache(testDB(t), time.Hour)
+	for _, r := range []*Result{
+		{MprID: "M1", RealtorURL: "/detail/1", RawJSON: []byte(`{}`)},
+		{MprID: "M2", RealtorURL: "/detail/2", RawJSON: []byte(`{}`)},
+		{MprID: "M3", RealtorURL: "/detail/3", RawJSON: []byte(`{}`)},
+	} {
+		if err := cache.Put(r); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+	}
+
+	n, err := cache.Clear("M2")
+	if err != nil {
+		t.Fatalf("clear one: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("removed = %d, want 1", n)
+	}
+
+	n, err = cache.Clear("")
+	if err != nil {
+		t.Fatalf("clear all: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("removed = %d, want 2", n)
+	}
+}
+
+func TestCachedProvider(t *testing.T) {
+	var rapidCalls atomic.Int32
+	suggestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(t, w, `{"autocomplete": [{"mpr_id": "M9999999999"}]}`)
+	}))
+	defer suggestServer.Close()
+
+	hulkServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(t, w, `{"data": {"home": {"href": "/detail/123-Test", "property_id": "M9999999999"}}}`)
+	}))
+	defer hulkServer.Close()
+
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rapidCalls.Add(1)
+		writeResponse(t, w, `{"list_price": 300000}`)
+	}))
+	defer rapidServer.Close()
+
+	p := NewCachedProvider(testClient(t, suggestServer.URL, hulkServer.URL, rapidServer.URL), NewCache(testDB(t), time.Hour))
+
+	for i := 0; i < 2; i++ {
+		res, err := p.Lookup(context.Background(), "123 Test St")
+		if err != nil {
+			t.Fatalf("lookup %d: %v", i, err)
+		}
+		if res.MprID != "M9999999999" || res.RealtorURL != "/detail/123-Test" {
+			t.Errorf("lookup %d = %+v", i, res)
+		}
+	}
+	if got := rapidCalls.Load(); got != 1 {
+		t.Errorf("RapidAPI calls after two lookups = %d, want 1", got)
+	}
+
+	if _, err := p.Refresh(context.Background(), "M9999999999", "/detail/123-Test"); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if got := rapidCalls.Load(); got != 1 {
+		t.Errorf("RapidAPI calls after cached refresh = %d, want 1", got)
+	}
+
+	bypass, ok := p.Bypass().(Refresher)
+	if !ok {
+		t.Fatal("bypass provider does not support refresh")
+	}
+	if _, err := bypass.Refresh(context.Background(), "M9999999999", "/detail/123-Test"); err != nil {
+		t.Fatalf("bypass refresh: %v", err)
+	}
+	if got := rapidCalls.Load(); got != 2 {
+		t.Errorf("RapidAPI calls after bypass refresh = %d, want 2", got)
+	}
+}
+
+// testDB opens a migrated SQLite database in a temp directory.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return d
+}
@@ -2,12 +2,15 @@
 package mls
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,10 +27,15 @@ type Result struct {
 	RawJSON    json.RawMessage `json:"raw_json"`
 }
 
-// Client fetches property data from external APIs.
+// Client fetches property data from external APIs. Requests are retried
+// on transient failures, and RapidAPI calls are rate limited client-side
+// so a burst of adds doesn't trip the plan's limits.
 type Client struct {
 	httpClient  *http.Client
 	rapidAPIKey string
+	limiter     *tokenBucket
+	backoff     time.Duration
+	usage       *Usage // optional; meters calls and enforces the budget
 
 	// Overridable URLs for testing.
 	suggestURL  string
@@ -41,39 +49,88 @@ func NewClient(rapidAPIKey string) (*Client, error) {
 		return nil, fmt.Errorf("RAPIDAPI_KEY is required")
 	}
 	return &Client{
-		httpClient:  &http.Client{},
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
 		rapidAPIKey: rapidAPIKey,
+		limiter:     newTokenBucket(defaultRapidAPIRate, defaultRapidAPIBurst),
+		backoff:     defaultBackoff,
 		suggestURL:  defaultSuggestURL,
 		hulkURL:     defaultHulkURL,
 		rapidAPIURL: defaultRapidAPIURL,
 	}, nil
 }
 
-// Lookup fetches property data for the given address.
-// This makes API calls: 2 free (realtor.com) + 1 RapidAPI call.
-func (c *Client) Lookup(address string) (*Result, error) {
+// SetRateLimit limits RapidAPI calls to rate per second, after a short
+// initial burst. A rate of zero turns the limit off.
+func (c *Client) SetRateLimit(rate float64) {
+	c.limiter = newTokenBucket(rate, defaultRapidAPIBurst)
+}
+
+// SetUsage records every call the client makes in u, and refuses paid
+// calls once u's monthly budget is used up.
+func (c *Client) SetUsage(u *Usage) {
+	c.usage = u
+}
+
+// Lookup fetches property data for the given address, realtor.com listing
+// URL, or property ID (see ParseListingRef).
+// This makes API calls: up to 2 free (realtor.com) + 1 RapidAPI call.
+func (c *Client) Lookup(ctx context.Context, address string) (*Result, error) {
+	mprID, href, err := c.Resolve(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Refresh(ctx, mprID, href)
+}
+
+// Resolve maps an address to its realtor.com property ID and listing URL
+// using the two free realtor.com APIs. No RapidAPI call is made. A listing
+// URL needs no calls and a property ID skips the geocoder, which avoids
+// the geocoder picking the wrong unit or town.
+func (c *Client) Resolve(ctx context.Context, address string) (mprID, realtorURL string, err error) {
 	if address == "" {
-		return nil, fmt.Errorf("address is required")
+		return "", "", fmt.Errorf("address is required")
 	}
 
-	mprID, err := c.lookupMprID(address)
+	if id, href, ok := ParseListingRef(address); ok {
+		if href != "" {
+			return id, href, nil
+		}
+		href, err = c.lookupRealtorURL(ctx, id)
+		if err != nil {
+			return "", "", fmt.Errorf("realtor URL lookup: %w", err)
+		}
+		return id, href, nil
+	}
+
+	mprID, err = c.lookupMprID(ctx, address)
 	if err != nil {
-		return nil, fmt.Errorf("geocoder lookup: %w", err)
+		return "", "", fmt.Errorf("geocoder lookup: %w", err)
 	}
 
-	href, err := c.lookupRealtorURL(mprID)
+	realtorURL, err = c.lookupRealtorURL(ctx, mprID)
 	if err != nil {
-		return nil, fmt.Errorf("realtor URL lookup: %w", err)
+		return "", "", fmt.Errorf("realtor URL lookup: %w", err)
+	}
+
+	return mprID, realtorURL, nil
+}
+
+// Refresh re-fetches property data for an already-known listing.
+// It skips the geocoder and URL lookups and makes a single RapidAPI call.
+func (c *Client) Refresh(ctx context.Context, mprID, realtorURL string) (*Result, error) {
+	if realtorURL == "" {
+		return nil, fmt.Errorf("realtor URL is required")
 	}
 
-	rawJSON, err := c.fetchPropertyDetail(href)
+	rawJSON, err := c.fetchPropertyDetail(ctx, realtorURL)
 	if err != nil {
 		return nil, fmt.Errorf("property detail fetch: %w", err)
 	}
 
 	return &Result{
 		MprID:      mprID,
-		RealtorURL: href,
+		RealtorURL: realtorURL,
 		RawJSON:    rawJSON,
 	}, nil
 }
@@ -81,27 +138,42 @@ func (c *Client) Lookup(address string) (*Result, error) {
 // suggestResponse is the response from the realtor.com suggest API.
 type suggestResponse struct {
 	Autocomplete []struct {
-		MprID string `json:"mpr_id"`
+		MprID       string   `json:"mpr_id"`
+		FullAddress []string `json:"full_address"`
+		Line        string   `json:"line"`
+		City        string   `json:"city"`
+		StateCode   string   `json:"state_code"`
+		PostalCode  string   `json:"postal_code"`
 	} `json:"autocomplete"`
 }
 
-// lookupMprID resolves an address to a realtor.com property ID.
-func (c *Client) lookupMprID(address string) (string, error) {
+// Suggest returns up to n properties matching address from the free
+// realtor.com geocoder. Entries without a property ID (cities, zip codes)
+// are skipped. No RapidAPI call is made.
+func (c *Client) Suggest(ctx context.Context, address string, n int) (candidates []Candidate, err error) {
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	if n < 1 {
+		n = 1
+	}
+
 	params := url.Values{
 		"input":     {address},
 		"client_id": {"rdc-home"},
-		"limit":     {"1"},
+		"limit":     {strconv.Itoa(n)},
 	}
 
-	req, err := http.NewRequest("GET", c.suggestURL+"?"+params.Encode(), nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "suggest", false, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.suggestURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		return req, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return nil, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -109,20 +181,36 @@ func (c *Client) lookupMprID(address string) (string, error) {
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
 	var result suggestResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
-	if len(result.Autocomplete) == 0 || result.Autocomplete[0].MprID == "" {
-		return "", fmt.Errorf("no property found for address: %s", address)
+	for _, a := range result.Autocomplete {
+		if a.MprID == "" {
+			continue
+		}
+		addr := strings.Join(a.FullAddress, ", ")
+		if addr == "" {
+			addr = listingAddress{Line: a.Line, City: a.City, StateCode: a.StateCode, PostalCode: a.PostalCode}.String()
+		}
+		candidates = append(candidates, Candidate{MprID: a.MprID, Address: addr})
 	}
 
-	return result.Autocomplete[0].MprID, nil
+	return candidates, nil
+}
+
+// lookupMprID resolves an address to a realtor.com property ID using the
+// top geocoder match.
+func (c *Client) lookupMprID(ctx context.Context, address string) (string, error) {
+	candidates, err := c.Suggest(ctx, address, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w for address: %s", ErrNotFound, address)
+	}
+	return candidates[0].MprID, nil
 }
 
 // hulkRequest is the GraphQL request body for the hulk API.
@@ -141,23 +229,24 @@ type hulkResponse struct {
 }
 
 // lookupRealtorURL resolves a property ID to a realtor.com URL.
-func (c *Client) lookupRealtorURL(mprID string) (string, error) {
+func (c *Client) lookupRealtorURL(ctx context.Context, mprID string) (href string, err error) {
 	query := fmt.Sprintf(`query { home(property_id: "%s") { href property_id } }`, mprID)
 	body, err := json.Marshal(hulkRequest{Query: query})
 	if err != nil {
 		return "", fmt.Errorf("marshaling request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.hulkURL, strings.NewReader(string(body)))
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "hulk", false, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.hulkURL, strings.NewReader(string(body)))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -165,38 +254,35 @@ func (c *Client) lookupRealtorURL(mprID string) (string, error) {
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
 	var result hulkResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return "", fmt.Errorf("decoding response: %w", err)
 	}
 
 	if result.Data.Home.Href == "" {
-		return "", fmt.Errorf("no realtor.com URL found for property ID: %s", mprID)
+		return "", fmt.Errorf("%w for property ID: %s", ErrNotFound, mprID)
 	}
 
 	return result.Data.Home.Href, nil
 }
 
 // fetchPropertyDetail fetches full property details from RapidAPI.
-func (c *Client) fetchPropertyDetail(realtorURL string) (json.RawMessage, error) {
+func (c *Client) fetchPropertyDetail(ctx context.Context, realtorURL string) (raw json.RawMessage, err error) {
 	params := url.Values{
 		"property_url": {realtorURL},
 	}
 
-	req, err := http.NewRequest("GET", c.rapidAPIURL+"?"+params.Encode(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("x-rapidapi-host", "us-real-estate-listings.p.rapidapi.com")
-	req.Header.Set("x-rapidapi-key", c.rapidAPIKey)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "rapidapi", true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.rapidAPIURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-rapidapi-host", "us-real-estate-listings.p.rapidapi.com")
+		req.Header.Set("x-rapidapi-key", c.rapidAPIKey)
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("sending request: %w", err)
+		return nil, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -204,11 +290,7 @@ func (c *Client) fetchPropertyDetail(realtorURL string) (json.RawMessage, error)
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
-	raw, err := io.ReadAll(resp.Body)
+	raw, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("reading response: %w", err)
 	}
@@ -1,11 +1,13 @@
 package mls
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -101,7 +103,7 @@ func TestLookupMprID(t *testing.T) {
 
 			c := testClient(t, server.URL, "", "")
 
-			mprID, err := c.lookupMprID(tt.address)
+			mprID, err := c.lookupMprID(context.Background(), tt.address)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -174,7 +176,7 @@ func TestLookupRealtorURL(t *testing.T) {
 
 			c := testClient(t, "", server.URL, "")
 
-			href, err := c.lookupRealtorURL(tt.mprID)
+			href, err := c.lookupRealtorURL(context.Background(), tt.mprID)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -237,7 +239,7 @@ func TestFetchPropertyDetail(t *testing.T) {
 
 			c := testClient(t, "", "", server.URL)
 
-			raw, err := c.fetchPropertyDetail(tt.realtorURL)
+			raw, err := c.fetchPropertyDetail(context.Background(), tt.realtorURL)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -260,7 +262,7 @@ func TestLookupEmptyAddress(t *testing.T) {
 		t.Fatalf("new client: %v", err)
 	}
 
-	_, err = c.Lookup("")
+	_, err = c.Lookup(context.Background(), "")
 	if err == nil {
 		t.Fatal("expected error for empty address, got nil")
 	}
@@ -285,7 +287,7 @@ func TestLookupEndToEnd(t *testing.T) {
 
 	c := testClient(t, suggestServer.URL, hulkServer.URL, rapidServer.URL)
 
-	result, err := c.Lookup("123 Test St, City, ST 00000")
+	result, err := c.Lookup(context.Background(), "123 Test St, City, ST 00000")
 	if err != nil {
 		t.Fatalf("lookup: %v", err)
 	}
@@ -301,6 +303,117 @@ func TestLookupEndToEnd(t *testing.T) {
 	}
 }
 
+func TestRefresh(t *testing.T) {
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("property_url"); got != "/detail/123-Test" {
+			t.Errorf("property_url = %q, want %q", got, "/detail/123-Test")
+		}
+		writeResponse(t, w, `{"list_price": 275000}`)
+	}))
+	defer rapidServer.Close()
+
+	// Geocoder and hulk must not be called on refresh.
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failServer.Close()
+
+	c := testClient(t, failServer.URL, failServer.URL, rapidServer.URL)
+
+	result, err := c.Refresh(context.Background(), "M1234567890", "/detail/123-Test")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if result.MprID != "M1234567890" {
+		t.Errorf("MprID = %q, want %q", result.MprID, "M1234567890")
+	}
+	if !json.Valid(result.RawJSON) {
+		t.Error("RawJSON is not valid JSON")
+	}
+
+	if _, err := c.Refresh(context.Background(), "M1234567890", ""); err == nil {
+		t.Error("expected error for empty realtor URL")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("limit"); got != "5" {
+			t.Errorf("limit = %q, want 5", got)
+		}
+		writeResponse(t, w, `{"autocomplete": [
+			{"mpr_id": "1111111111", "full_address": ["100 Main St Apt 1, Yukon, OK 73099"]},
+			{"area_type": "city", "city": "Yukon", "state_code": "OK"},
+			{"mpr_id": "2222222222", "line": "100 Main St Apt 2", "city": "Yukon", "state_code": "OK", "postal_code": "73099"}
+		]}`)
+	}))
+	defer server.Close()
+
+	c := testClient(t, server.URL, "", "")
+
+	got, err := c.Suggest(context.Background(), "100 Main St", 5)
+	if err != nil {
+		t.Fatalf("suggest: %v", err)
+	}
+	want := []Candidate{
+		{MprID: "1111111111", Address: "100 Main St Apt 1, Yukon, OK 73099"},
+		{MprID: "2222222222", Address: "100 Main St Apt 2, Yukon, OK 73099"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("candidate %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := c.Suggest(context.Background(), "", 5); err == nil {
+		t.Error("expected error for empty address")
+	}
+}
+
+func TestResolveListingRef(t *testing.T) {
+	// The geocoder must never be called for a URL or property ID.
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected geocoder request: %s", r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failServer.Close()
+
+	var hulkCalls int
+	hulkServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hulkCalls++
+		writeResponse(t, w, `{"data": {"home": {"href": "https://www.realtor.com/realestateandhomes-detail/1-Elm_Town_OK_73000_M75364-50927", "property_id": "7536450927"}}}`)
+	}))
+	defer hulkServer.Close()
+
+	c := testClient(t, failServer.URL, hulkServer.URL, failServer.URL)
+
+	mprID, href, err := c.Resolve(context.Background(), "https://www.realtor.com/realestateandhomes-detail/1-Elm_Town_OK_73000_M75364-50927")
+	if err != nil {
+		t.Fatalf("resolve URL: %v", err)
+	}
+	if mprID != "7536450927" || href != "https://www.realtor.com/realestateandhomes-detail/1-Elm_Town_OK_73000_M75364-50927" {
+		t.Errorf("resolve URL = %q, %q", mprID, href)
+	}
+	if hulkCalls != 0 {
+		t.Errorf("hulk calls for URL = %d, want 0", hulkCalls)
+	}
+
+	mprID, href, err = c.Resolve(context.Background(), "M75364-50927")
+	if err != nil {
+		t.Fatalf("resolve ID: %v", err)
+	}
+	if mprID != "7536450927" || href == "" {
+		t.Errorf("resolve ID = %q, %q", mprID, href)
+	}
+	if hulkCalls != 1 {
+		t.Errorf("hulk calls for ID = %d, want 1", hulkCalls)
+	}
+}
+
 // writeResponse writes a string to an http.ResponseWriter in tests.
 func writeResponse(t *testing.T, w http.ResponseWriter, s string) {
 	t.Helper()
@@ -325,5 +438,6 @@ func testClient(t *testing.T, suggestURL, hulkURL, rapidAPIURL string) *Client {
 	if rapidAPIURL != "" {
 		c.rapidAPIURL = rapidAPIURL
 	}
+	c.backoff = time.Millisecond
 	return c
 }
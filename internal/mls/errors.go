@@ -0,0 +1,66 @@
+package mls
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Lookup failures callers may want to tell apart. Errors from providers
+// wrap one of these when the cause is known; check with errors.Is.
+var (
+	// ErrNotFound means the address or listing doesn't exist upstream.
+	ErrNotFound = errors.New("no property found")
+
+	// ErrQuotaExceeded means the listing API refused the call for rate or
+	// plan limits, and kept refusing after retries.
+	ErrQuotaExceeded = errors.New("listing API quota exceeded")
+
+	// ErrUnavailable means a listing endpoint was unreachable or kept
+	// failing with server errors.
+	ErrUnavailable = errors.New("listing service unavailable")
+
+	// ErrBudgetExceeded means the monthly budget of paid calls set with
+	// Usage.SetBudget is used up, so the call was not made.
+	ErrBudgetExceeded = errors.New("monthly RapidAPI budget reached")
+)
+
+// StatusError is an unexpected HTTP status from a listing endpoint. It
+// unwraps to ErrNotFound, ErrQuotaExceeded or ErrUnavailable when the
+// status falls in one of those classes.
+type StatusError struct {
+	Endpoint   string // suggest, hulk or rapidapi
+	StatusCode int
+
+	// RetryAfter is how long the endpoint asked callers to wait, from its
+	// Retry-After header; zero if it didn't say.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s: unexpected status %d", e.Endpoint, e.StatusCode)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+// Unwrap returns the error class for the status, if any.
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// retryable reports whether a request that got status may succeed if
+// sent again.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,212 @@
+package mls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileProvider serves listing data from a directory of saved RapidAPI
+// responses, one JSON file per property named <mpr_id>.json. It makes no
+// network calls, so the server can run offline without a RAPIDAPI_KEY.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider reading fixtures from dir.
+func NewFileProvider(dir string) (*FileProvider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+	return &FileProvider{dir: dir}, nil
+}
+
+// fixture is a loaded fixture file.
+type fixture struct {
+	mprID   string
+	address string
+	href    string
+	raw     json.RawMessage
+}
+
+// Lookup finds the fixture whose address matches, or whose mpr_id or
+// listing URL equals address.
+func (p *FileProvider) Lookup(_ context.Context, address string) (*Result, error) {
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+
+	fixtures, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	query := normalizeAddress(address)
+	for _, f := range fixtures {
+		if f.mprID == address || f.href == address || (f.address != "" && addressMatches(f.address, query)) {
+			return f.result(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w for address: %s", ErrNotFound, address)
+}
+
+// Refresh re-reads the fixture for mprID.
+func (p *FileProvider) Refresh(_ context.Context, mprID, realtorURL string) (*Result, error) {
+	f, err := p.read(filepath.Join(p.dir, mprID+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for property ID: %s", ErrNotFound, mprID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture %s: %w", mprID, err)
+	}
+	res := f.result()
+	if realtorURL != "" {
+		res.RealtorURL = realtorURL
+	}
+	return res, nil
+}
+
+// Suggest returns up to n fixtures whose address contains every word of address.
+func (p *FileProvider) Suggest(_ context.Context, address string, n int) ([]Candidate, error) {
+	fixtures, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(normalizeAddress(address))
+	var out []Candidate
+	for _, f := range fixtures {
+		addr := normalizeAddress(f.address)
+		match := len(words) > 0
+		for _, w := range words {
+			if !strings.Contains(addr, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, Candidate{MprID: f.mprID, Address: f.address})
+			if n > 0 && len(out) >= n {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fixture) result() *Result {
+	href := f.href
+	if href == "" {
+		href = "/realestateandhomes-detail/" + f.mprID
+	}
+	return &Result{MprID: f.mprID, RealtorURL: href, RawJSON: f.raw}
+}
+
+// load reads every fixture in the directory, sorted by file name.
+func (p *FileProvider) load() ([]*fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(p.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing fixtures: %w", err)
+	}
+	sort.Strings(paths)
+
+	var fixtures []*fixture
+	for _, path := range paths {
+		f, err := p.read(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading fixture %s: %w", filepath.Base(path), err)
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// read loads a single fixture file and extracts its address and href.
+func (p *FileProvider) read(path string) (*fixture, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(raw) {
+		return nil, fmt.Errorf("not valid JSON")
+	}
+
+	var doc struct {
+		Data     *fixtureListing `json:"data"`
+		Href     string          `json:"href"`
+		Location fixtureLocation `json:"location"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("decoding fixture: %w", err)
+	}
+	listing := fixtureListing{Href: doc.Href, Location: doc.Location}
+	if doc.Data != nil {
+		listing = *doc.Data
+	}
+
+	return &fixture{
+		mprID:   strings.TrimSuffix(filepath.Base(path), ".json"),
+		address: listing.Location.Address.String(),
+		href:    listing.Href,
+		raw:     json.RawMessage(raw),
+	}, nil
+}
+
+type fixtureListing struct {
+	Href     string          `json:"href"`
+	Location fixtureLocation `json:"location"`
+}
+
+type fixtureLocation struct {
+	Address listingAddress `json:"address"`
+}
+
+type listingAddress struct {
+	Line       string `json:"line"`
+	City       string `json:"city"`
+	StateCode  string `json:"state_code"`
+	PostalCode string `json:"postal_code"`
+}
+
+// String formats the address as "line, city, ST 12345".
+func (a listingAddress) String() string {
+	var parts []string
+	if a.Line != "" {
+		parts = append(parts, a.Line)
+	}
+	if a.City != "" {
+		parts = append(parts, a.City)
+	}
+	stateZip := strings.TrimSpace(a.StateCode + " " + a.PostalCode)
+	if stateZip != "" {
+		parts = append(parts, stateZip)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addressMatches reports whether a fixture address matches a normalized
+// query. The street line must match; city/state/zip are optional.
+func addressMatches(fixtureAddr, query string) bool {
+	addr := normalizeAddress(fixtureAddr)
+	if addr == query {
+		return true
+	}
+	line := strings.SplitN(query, ",", 2)[0]
+	return line != "" && strings.HasPrefix(addr, strings.TrimSpace(line))
+}
+
+// normalizeAddress lowercases and collapses whitespace for matching.
+func normalizeAddress(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,142 @@
+package mls
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewFileProvider(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "x.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"existing directory", "testdata", false},
+		{"missing directory", filepath.Join(t.TempDir(), "nope"), true},
+		{"regular file", file, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileProvider(tt.dir)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFileProviderLookup(t *testing.T) {
+	p, err := NewFileProvider("testdata")
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		wantMprID string
+		wantErr   bool
+	}{
+		{"full address", "123 Main St, Yukon, OK 73099", "M1234567890", false},
+		{"street line only", "456 oak ave", "M2222222222", false},
+		{"extra whitespace", "  123   Main St,  Yukon ", "M1234567890", false},
+		{"by mpr_id", "M2222222222", "M2222222222", false},
+		{"no match", "789 Elm St", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := p.Lookup(context.Background(), tt.address)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.MprID != tt.wantMprID {
+				t.Errorf("mpr_id = %q, want %q", res.MprID, tt.wantMprID)
+			}
+			if res.RealtorURL == "" {
+				t.Error("expected realtor URL")
+			}
+			if len(res.RawJSON) == 0 {
+				t.Error("expected raw JSON")
+			}
+		})
+	}
+}
+
+func TestFileProviderRefresh(t *testing.T) {
+	p, err := NewFileProvider("testdata")
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	res, err := p.Refresh(context.Background(), "M1234567890", "https://example.com/listing")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if res.RealtorURL != "https://example.com/listing" {
+		t.Errorf("realtor_url = %q, want stored URL", res.RealtorURL)
+	}
+	if !strings.Contains(string(res.RawJSON), "250000") {
+		t.Errorf("unexpected raw JSON: %s", res.RawJSON)
+	}
+
+	if _, err := p.Refresh(context.Background(), "M0000000000", ""); err == nil {
+		t.Error("expected error for unknown mpr_id")
+	}
+}
+
+func TestFileProviderSuggest(t *testing.T) {
+	p, err := NewFileProvider("testdata")
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{"single match", "main", 10, []string{"M1234567890"}},
+		{"by state", "ok", 10, []string{"M1234567890", "M2222222222"}},
+		{"limit", "ok", 1, []string{"M1234567890"}},
+		{"all words must match", "main mustang", 10, nil},
+		{"empty query", "", 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Suggest(context.Background(), tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d candidates, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, id := range tt.want {
+				if got[i].MprID != id {
+					t.Errorf("candidate %d = %q, want %q", i, got[i].MprID, id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package mls
+
+import "context"
+
+// Provider looks up listing data for an address. The RapidAPI-backed
+// Client is the default implementation; FileProvider serves local fixtures.
+// Cancelling ctx abandons the lookup, including any retry waits.
+type Provider interface {
+	Lookup(ctx context.Context, address string) (*Result, error)
+}
+
+// Refresher is an optional Provider capability for re-fetching a listing
+// that is already known, without repeating the address lookup.
+type Refresher interface {
+	Refresh(ctx context.Context, mprID, realtorURL string) (*Result, error)
+}
+
+// Resolver is an optional Provider capability for mapping an address to
+// its listing identity without fetching listing data. Together with
+// Refresher it lets CachedProvider skip the paid fetch on a cache hit.
+type Resolver interface {
+	Resolve(ctx context.Context, address string) (mprID, realtorURL string, err error)
+}
+
+// CacheBypasser is implemented by providers that cache results. Bypass
+// returns a provider that always fetches fresh data.
+type CacheBypasser interface {
+	Bypass() Provider
+}
+
+// Suggester is an optional Provider capability for listing candidate
+// properties matching an address, so the user can pick the right one
+// before any paid fetch runs.
+type Suggester interface {
+	Suggest(ctx context.Context, address string, n int) ([]Candidate, error)
+}
+
+// Candidate is a property matched by Suggest.
+type Candidate struct {
+	MprID   string `json:"mpr_id"`
+	Address string `json:"address"`
+}
+
+var (
+	_ Provider  = (*Client)(nil)
+	_ Refresher = (*Client)(nil)
+	_ Resolver  = (*Client)(nil)
+	_ Suggester = (*Client)(nil)
+	_ Provider  = (*FileProvider)(nil)
+	_ Refresher = (*FileProvider)(nil)
+	_ Suggester = (*FileProvider)(nil)
+
+	_ Provider      = (*CachedProvider)(nil)
+	_ Refresher     = (*CachedProvider)(nil)
+	_ CacheBypasser = (*CachedProvider)(nil)
+	_ Suggester     = (*CachedProvider)(nil)
+)
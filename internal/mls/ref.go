@@ -0,0 +1,49 @@
+package mls
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const realtorDetailPath = "/realestateandhomes-detail/"
+
+var (
+	// mprIDPattern matches a bare property ID such as 7536450927,
+	// M7536450927 or M75364-50927.
+	mprIDPattern = regexp.MustCompile(`^[Mm]?(\d{5})-?(\d{5,})$`)
+
+	// urlMprIDPattern matches the _M75364-50927 suffix of a listing slug.
+	urlMprIDPattern = regexp.MustCompile(`_M(\d+)-(\d+)$`)
+)
+
+// ParseListingRef reports whether s identifies a listing directly rather
+// than by address. For a realtor.com listing URL it returns the property
+// ID and a normalized URL; for a bare property ID it returns the ID and an
+// empty URL.
+func ParseListingRef(s string) (mprID, realtorURL string, ok bool) {
+	s = strings.TrimSpace(s)
+
+	if m := mprIDPattern.FindStringSubmatch(s); m != nil {
+		return m[1] + m[2], "", true
+	}
+
+	if !strings.Contains(s, "realtor.com"+realtorDetailPath) {
+		return "", "", false
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", "", false
+	}
+
+	slug := strings.Trim(strings.TrimPrefix(u.Path, realtorDetailPath), "/")
+	m := urlMprIDPattern.FindStringSubmatch(slug)
+	if m == nil {
+		return "", "", false
+	}
+
+	return m[1] + m[2], "https://www.realtor.com" + realtorDetailPath + slug, true
+}
@@ -0,0 +1,50 @@
+package mls
+
+import "testing"
+
+func TestParseListingRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantID  string
+		wantURL string
+		wantOK  bool
+	}{
+		{
+			name:    "full listing URL",
+			input:   "https://www.realtor.com/realestateandhomes-detail/123-Main-St_Yukon_OK_73099_M75364-50927",
+			wantID:  "7536450927",
+			wantURL: "https://www.realtor.com/realestateandhomes-detail/123-Main-St_Yukon_OK_73099_M75364-50927",
+			wantOK:  true,
+		},
+		{
+			name:    "URL without scheme, with query and trailing slash",
+			input:   "realtor.com/realestateandhomes-detail/123-Main-St_Yukon_OK_73099_M75364-50927/?from=search",
+			wantID:  "7536450927",
+			wantURL: "https://www.realtor.com/realestateandhomes-detail/123-Main-St_Yukon_OK_73099_M75364-50927",
+			wantOK:  true,
+		},
+		{name: "M-number with dash", input: "M75364-50927", wantID: "7536450927", wantOK: true},
+		{name: "M-number", input: "m7536450927", wantID: "7536450927", wantOK: true},
+		{name: "bare property ID", input: " 7536450927 ", wantID: "7536450927", wantOK: true},
+		{name: "street address", input: "123 Main St, Yukon, OK 73099", wantOK: false},
+		{name: "zip code", input: "73099", wantOK: false},
+		{name: "other realtor.com page", input: "https://www.realtor.com/realestateandhomes-search/Yukon_OK", wantOK: false},
+		{name: "listing URL without ID", input: "https://www.realtor.com/realestateandhomes-detail/123-Main-St", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, href, ok := ParseListingRef(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if id != tt.wantID {
+				t.Errorf("mprID = %q, want %q", id, tt.wantID)
+			}
+			if href != tt.wantURL {
+				t.Errorf("realtorURL = %q, want %q", href, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package mls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAttempts is how many times a request is sent before giving up.
+	maxAttempts = 3
+
+	// defaultBackoff is the base delay before the first retry; each
+	// further retry doubles it.
+	defaultBackoff = 500 * time.Millisecond
+
+	// maxRetryWait caps how long one retry may be delayed. An endpoint
+	// asking for a longer Retry-After is reported as failed rather than
+	// holding the caller's request open.
+	maxRetryWait = 10 * time.Second
+
+	// Default RapidAPI rate: a short burst, then two calls a second.
+	defaultRapidAPIRate  = 2
+	defaultRapidAPIBurst = 5
+)
+
+// send sends the request built by newReq, retrying with jittered backoff
+// on network errors, 429 and 5xx, and honoring Retry-After. With paid
+// set, each attempt is first checked against the monthly budget and waits
+// for a token from the RapidAPI bucket. Every attempt is recorded when
+// the client meters usage. Any status other than 200 is returned as a
+// *StatusError; the caller must close the body of a successful response.
+func (c *Client) send(ctx context.Context, endpoint string, paid bool, newReq func(ctx context.Context) (*http.Request, error)) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := c.backoffDelay(attempt, lastErr)
+			if delay > maxRetryWait {
+				break
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		if paid {
+			if c.usage != nil {
+				if err := c.usage.CheckBudget(); err != nil {
+					return nil, err
+				}
+			}
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newReq(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.record(ctx, endpoint, status, time.Since(start))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("%w: %s: %w", ErrUnavailable, endpoint, err)
+			continue
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		statusErr := &StatusError{
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			return nil, fmt.Errorf("%w (also failed to close body: %v)", statusErr, closeErr)
+		}
+		if !retryable(resp.StatusCode) {
+			return nil, statusErr
+		}
+		lastErr = statusErr
+	}
+	return nil, lastErr
+}
+
+// record meters one attempt. A failure to record is logged rather than
+// failing a call that has already been paid for.
+func (c *Client) record(ctx context.Context, endpoint string, status int, latency time.Duration) {
+	if c.usage == nil {
+		return
+	}
+	call := Call{Endpoint: endpoint, StatusCode: status, Latency: latency, User: callerFrom(ctx)}
+	if err := c.usage.Record(call); err != nil {
+		slog.Warn("metering listing call", "endpoint", endpoint, "err", err)
+	}
+}
+
+// backoffDelay returns how long to wait before retry number attempt: the
+// endpoint's Retry-After if it gave one, else exponential backoff with
+// jitter so concurrent lookups don't retry in lockstep.
+func (c *Client) backoffDelay(attempt int, lastErr error) time.Duration {
+	var se *StatusError
+	if errors.As(lastErr, &se) && se.RetryAfter > 0 {
+		return se.RetryAfter
+	}
+	d := c.backoff << (attempt - 1)
+	return d/2 + rand.N(d/2+1)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date. It returns zero if the header is missing or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// tokenBucket limits calls to rate per second, allowing bursts of up to
+// burst calls. A zero rate means no limit.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, blocking until one is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		if b.rate <= 0 {
+			b.mu.Unlock()
+			return nil
+		}
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package mls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer answers each request with the next of statuses, repeating
+// the last one, and counts hits.
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&hits, 1))
+		status := statuses[min(n, len(statuses))-1]
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		writeResponse(t, w, `{"list_price": 1}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestFetchRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		header   http.Header
+		wantErr  error
+		wantHits int32
+	}{
+		{"recovers after server errors", []int{503, 502, 200}, nil, nil, 3},
+		{"recovers after rate limit", []int{429, 200}, http.Header{"Retry-After": {"0"}}, nil, 2},
+		{"gives up on server errors", []int{500}, nil, ErrUnavailable, maxAttempts},
+		{"gives up on rate limit", []int{429}, nil, ErrQuotaExceeded, maxAttempts},
+		{"long Retry-After is not waited out", []int{429}, http.Header{"Retry-After": {"3600"}}, ErrQuotaExceeded, 1},
+		{"not found is not retried", []int{404}, nil, ErrNotFound, 1},
+		{"other client errors are not retried", []int{401}, nil, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := statusServer(t, tt.header, tt.statuses...)
+			c := testClient(t, "", "", srv.URL)
+
+			_, err := c.fetchPropertyDetail(context.Background(), "/detail/1")
+			last := tt.statuses[len(tt.statuses)-1]
+			switch {
+			case last == http.StatusOK && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case last != http.StatusOK && err == nil:
+				t.Fatal("expected error, got nil")
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(hits); got != tt.wantHits {
+				t.Errorf("hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestStatusErrorRetryAfter(t *testing.T) {
+	srv, _ := statusServer(t, http.Header{"Retry-After": {"120"}}, http.StatusTooManyRequests)
+	c := testClient(t, "", "", srv.URL)
+
+	_, err := c.fetchPropertyDetail(context.Background(), "/detail/1")
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("error = %v, want *StatusError", err)
+	}
+	if se.Endpoint != "rapidapi" || se.StatusCode != 429 || se.RetryAfter != 2*time.Minute {
+		t.Errorf("status error = %+v", se)
+	}
+}
+
+func TestLookupCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	c := testClient(t, srv.URL, "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.Lookup(ctx, "123 Main St")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("lookup took %v after cancellation", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-5", 0},
+		{"soon", 0},
+		{"Fri, 01 Mar 2024 12:01:30 GMT", 90 * time.Second},
+		{"Fri, 01 Mar 2024 11:00:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(50, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := b.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	// Two calls ride the burst; the next two wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("four calls took %v, want at least 35ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait on empty bucket with cancelled ctx = %v, want context.Canceled", err)
+	}
+
+	unlimited := newTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		if err := unlimited.wait(ctx); err != nil {
+			t.Fatalf("unlimited wait: %v", err)
+		}
+	}
+}
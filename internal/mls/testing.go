@@ -1,5 +1,7 @@
 package mls
 
+import "time"
+
 // SetTestURLs overrides the API URLs on a client for testing.
 // This should only be used in tests.
 func SetTestURLs(c *Client, suggestURL, hulkURL, rapidAPIURL string) {
@@ -13,3 +15,9 @@ func SetTestURLs(c *Client, suggestURL, hulkURL, rapidAPIURL string) {
 		c.rapidAPIURL = rapidAPIURL
 	}
 }
+
+// SetTestBackoff sets the base delay between retries on a client for
+// testing, so tests of failing endpoints don't wait out real backoff.
+func SetTestBackoff(c *Client, d time.Duration) {
+	c.backoff = d
+}
@@ -0,0 +1,266 @@
+package mls
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// paidEndpoint is the endpoint billed per call by the RapidAPI plan. The
+// realtor.com suggest and hulk endpoints are free.
+const paidEndpoint = "rapidapi"
+
+// Report windows for Usage.Report.
+const (
+	usageReportDays   = 30
+	usageReportMonths = 12
+)
+
+// callTimeFormat is how call times are stored: UTC, sortable, and sliced
+// by prefix into days and months.
+const callTimeFormat = "2006-01-02 15:04:05"
+
+// Usage meters outbound listing API calls in SQLite and holds the
+// monthly budget of paid calls. Months and days are counted in UTC.
+type Usage struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewUsage creates a usage meter.
+func NewUsage(db *sql.DB) *Usage {
+	return &Usage{db: db, now: time.Now}
+}
+
+// Call is one request sent to a listing endpoint. Retries are separate
+// calls.
+type Call struct {
+	Endpoint   string // suggest, hulk or rapidapi
+	StatusCode int    // zero if no response came back
+	Latency    time.Duration
+	User       string // who triggered it; see WithCaller
+}
+
+// UsageTotal sums the calls in one day (2006-01-02) or month (2006-01).
+type UsageTotal struct {
+	Period       string `json:"period"`
+	Calls        int    `json:"calls"`
+	Paid         int    `json:"paid"`
+	Failed       int    `json:"failed"`
+	AvgLatencyMS int64  `json:"avg_latency_ms"`
+}
+
+// UserUsage sums one user's calls this month. An empty User covers calls
+// made with auth disabled.
+type UserUsage struct {
+	User  string `json:"user"`
+	Calls int    `json:"calls"`
+	Paid  int    `json:"paid"`
+}
+
+// UsageReport summarizes listing API usage against the monthly budget.
+type UsageReport struct {
+	Month         string       `json:"month"`
+	MonthlyBudget int          `json:"monthly_budget"` // paid calls; 0 = no budget
+	PaidThisMonth int          `json:"paid_this_month"`
+	Daily         []UsageTotal `json:"daily"`   // last 30 days with calls, newest first
+	Monthly       []UsageTotal `json:"monthly"` // last 12 months with calls, newest first
+	Users         []UserUsage  `json:"users"`   // this month, busiest first
+}
+
+// Record stores a call.
+func (u *Usage) Record(c Call) error {
+	_, err := u.db.Exec(
+		"INSERT INTO mls_calls (endpoint, status, latency_ms, user, created_at) VALUES (?, ?, ?, ?, ?)",
+		c.Endpoint, c.StatusCode, c.Latency.Milliseconds(), c.User, u.now().UTC().Format(callTimeFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("recording listing call: %w", err)
+	}
+	return nil
+}
+
+// Budget returns the monthly budget of paid calls, or 0 if none is set.
+func (u *Usage) Budget() (int, error) {
+	var n int
+	err := u.db.QueryRow("SELECT monthly_calls FROM mls_budget WHERE id = 1").Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading budget: %w", err)
+	}
+	return n, nil
+}
+
+// SetBudget sets the monthly budget of paid calls. Zero removes it.
+func (u *Usage) SetBudget(calls int, by string) error {
+	if calls < 0 {
+		return fmt.Errorf("budget must be zero or more calls, got %d", calls)
+	}
+
+	var err error
+	if calls == 0 {
+		_, err = u.db.Exec("DELETE FROM mls_budget")
+	} else {
+		_, err = u.db.Exec(
+			`INSERT INTO mls_budget (id, monthly_calls, updated_by, updated_at) VALUES (1, ?, ?, CURRENT_TIMESTAMP)
+			 ON CONFLICT(id) DO UPDATE SET monthly_calls = excluded.monthly_calls, updated_by = excluded.updated_by, updated_at = excluded.updated_at`,
+			calls, by,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("saving budget: %w", err)
+	}
+	return nil
+}
+
+// CheckBudget returns an error wrapping ErrBudgetExceeded if this month's
+// paid calls have used up the budget. Calls already in flight aren't
+// counted, so concurrent lookups can overshoot by a few.
+func (u *Usage) CheckBudget() error {
+	budget, err := u.Budget()
+	if err != nil || budget == 0 {
+		return err
+	}
+
+	month := u.now().UTC().Format("2006-01")
+	used, err := u.paidSince(month)
+	if err != nil {
+		return err
+	}
+	if used >= budget {
+		return fmt.Errorf("%w: %d of %d calls used in %s", ErrBudgetExceeded, used, budget, month)
+	}
+	return nil
+}
+
+// paidSince counts paid calls made at or after since, a prefix of
+// callTimeFormat.
+func (u *Usage) paidSince(since string) (int, error) {
+	var n int
+	err := u.db.QueryRow(
+		"SELECT COUNT(*) FROM mls_calls WHERE endpoint = ? AND created_at >= ?",
+		paidEndpoint, since,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("counting paid calls: %w", err)
+	}
+	return n, nil
+}
+
+// Report returns daily and monthly totals and this month's usage against
+// the budget.
+func (u *Usage) Report() (*UsageReport, error) {
+	now := u.now().UTC()
+	month := now.Format("2006-01")
+
+	budget, err := u.Budget()
+	if err != nil {
+		return nil, err
+	}
+	paid, err := u.paidSince(month)
+	if err != nil {
+		return nil, err
+	}
+
+	firstDay := now.AddDate(0, 0, -(usageReportDays - 1)).Format("2006-01-02")
+	daily, err := u.totals(10, firstDay)
+	if err != nil {
+		return nil, err
+	}
+	firstMonth := time.Date(now.Year(), now.Month()-(usageReportMonths-1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+	monthly, err := u.totals(7, firstMonth)
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.users(month)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UsageReport{
+		Month:         month,
+		MonthlyBudget: budget,
+		PaidThisMonth: paid,
+		Daily:         daily,
+		Monthly:       monthly,
+		Users:         users,
+	}, nil
+}
+
+// totals groups calls since since by the first width characters of their
+// time: 10 for days, 7 for months.
+func (u *Usage) totals(width int, since string) (totals []UsageTotal, err error) {
+	rows, err := u.db.Query(
+		`SELECT substr(created_at, 1, ?) AS period, COUNT(*), SUM(endpoint = ?), SUM(status <> 200), CAST(AVG(latency_ms) AS INTEGER)
+		 FROM mls_calls WHERE created_at >= ? GROUP BY period ORDER BY period DESC`,
+		width, paidEndpoint, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("summing listing calls: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	totals = make([]UsageTotal, 0)
+	for rows.Next() {
+		var t UsageTotal
+		if err := rows.Scan(&t.Period, &t.Calls, &t.Paid, &t.Failed, &t.AvgLatencyMS); err != nil {
+			return nil, fmt.Errorf("scanning usage total: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating usage totals: %w", err)
+	}
+	return totals, nil
+}
+
+// users sums calls since since per user.
+func (u *Usage) users(since string) (users []UserUsage, err error) {
+	rows, err := u.db.Query(
+		`SELECT user, COUNT(*) AS calls, SUM(endpoint = ?) FROM mls_calls
+		 WHERE created_at >= ? GROUP BY user ORDER BY calls DESC, user`,
+		paidEndpoint, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("summing calls by user: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	users = make([]UserUsage, 0)
+	for rows.Next() {
+		var uu UserUsage
+		if err := rows.Scan(&uu.User, &uu.Calls, &uu.Paid); err != nil {
+			return nil, fmt.Errorf("scanning user usage: %w", err)
+		}
+		users = append(users, uu)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating user usage: %w", err)
+	}
+	return users, nil
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx whose listing calls are recorded as
+// triggered by who, such as a user's email or "watcher".
+func WithCaller(ctx context.Context, who string) context.Context {
+	return context.WithValue(ctx, callerKey{}, who)
+}
+
+// callerFrom returns who ctx was tagged with by WithCaller.
+func callerFrom(ctx context.Context) string {
+	who, _ := ctx.Value(callerKey{}).(string)
+	return who
+}
@@ -0,0 +1,151 @@
+package mls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUsageReport(t *testing.T) {
+	usage := NewUsage(testDB(t))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	usage.now = func() time.Time { return now }
+
+	record := func(c Call) {
+		t.Helper()
+		if err := usage.Record(c); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	// Last day of February, then two days into March.
+	now = now.Add(-36 * time.Hour)
+	record(Call{Endpoint: "rapidapi", StatusCode: 200, Latency: 300 * time.Millisecond, User: "a@example.com"})
+	now = now.Add(36 * time.Hour)
+	record(Call{Endpoint: "suggest", StatusCode: 200, Latency: 100 * time.Millisecond, User: "a@example.com"})
+	record(Call{Endpoint: "rapidapi", StatusCode: 503, Latency: 200 * time.Millisecond, User: "b@example.com"})
+	record(Call{Endpoint: "rapidapi", StatusCode: 200, Latency: 300 * time.Millisecond, User: "b@example.com"})
+	now = now.Add(24 * time.Hour)
+	record(Call{Endpoint: "hulk", StatusCode: 0, Latency: 0, User: "watcher"})
+
+	report, err := usage.Report()
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if report.Month != "2026-03" || report.MonthlyBudget != 0 || report.PaidThisMonth != 2 {
+		t.Errorf("report = %+v, want 2 paid calls in 2026-03 and no budget", report)
+	}
+
+	wantDaily := []UsageTotal{
+		{Period: "2026-03-02", Calls: 1, Failed: 1},
+		{Period: "2026-03-01", Calls: 3, Paid: 2, Failed: 1, AvgLatencyMS: 200},
+		{Period: "2026-02-28", Calls: 1, Paid: 1, AvgLatencyMS: 300},
+	}
+	if len(report.Daily) != len(wantDaily) {
+		t.Fatalf("daily = %+v, want %+v", report.Daily, wantDaily)
+	}
+	for i, want := range wantDaily {
+		if report.Daily[i] != want {
+			t.Errorf("daily[%d] = %+v, want %+v", i, report.Daily[i], want)
+		}
+	}
+
+	if len(report.Monthly) != 2 || report.Monthly[0].Period != "2026-03" || report.Monthly[0].Calls != 4 || report.Monthly[1].Calls != 1 {
+		t.Errorf("monthly = %+v", report.Monthly)
+	}
+
+	wantUsers := []UserUsage{
+		{User: "b@example.com", Calls: 2, Paid: 2},
+		{User: "a@example.com", Calls: 1},
+		{User: "watcher", Calls: 1},
+	}
+	if len(report.Users) != len(wantUsers) {
+		t.Fatalf("users = %+v, want %+v", report.Users, wantUsers)
+	}
+	for i, want := range wantUsers {
+		if report.Users[i] != want {
+			t.Errorf("users[%d] = %+v, want %+v", i, report.Users[i], want)
+		}
+	}
+}
+
+func TestUsageBudget(t *testing.T) {
+	usage := NewUsage(testDB(t))
+
+	if err := usage.CheckBudget(); err != nil {
+		t.Fatalf("check with no budget: %v", err)
+	}
+	if err := usage.SetBudget(-1, ""); err == nil {
+		t.Error("expected error for negative budget")
+	}
+	if err := usage.SetBudget(1, "admin@example.com"); err != nil {
+		t.Fatalf("set budget: %v", err)
+	}
+	if err := usage.SetBudget(2, "admin@example.com"); err != nil {
+		t.Fatalf("set budget again: %v", err)
+	}
+	if n, err := usage.Budget(); err != nil || n != 2 {
+		t.Fatalf("budget = %d, %v; want 2", n, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := usage.CheckBudget(); err != nil {
+			t.Fatalf("check after %d calls: %v", i, err)
+		}
+		if err := usage.Record(Call{Endpoint: "rapidapi", StatusCode: 200}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	// Free calls don't count.
+	if err := usage.Record(Call{Endpoint: "suggest", StatusCode: 200}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := usage.CheckBudget(); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("check = %v, want ErrBudgetExceeded", err)
+	}
+
+	if err := usage.SetBudget(0, "admin@example.com"); err != nil {
+		t.Fatalf("remove budget: %v", err)
+	}
+	if err := usage.CheckBudget(); err != nil {
+		t.Errorf("check after removing budget: %v", err)
+	}
+}
+
+func TestClientMetersCalls(t *testing.T) {
+	srv, hits := statusServer(t, nil, http.StatusServiceUnavailable, http.StatusOK)
+	c := testClient(t, "", "", srv.URL)
+	usage := NewUsage(testDB(t))
+	c.SetUsage(usage)
+	if err := usage.SetBudget(2, ""); err != nil {
+		t.Fatalf("set budget: %v", err)
+	}
+
+	ctx := WithCaller(context.Background(), "a@example.com")
+	if _, err := c.fetchPropertyDetail(ctx, "/detail/1"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	// The retry used up the budget, so the next fetch isn't sent.
+	_, err := c.fetchPropertyDetail(ctx, "/detail/2")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("error = %v, want ErrBudgetExceeded", err)
+	}
+	if got := atomic.LoadInt32(hits); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+
+	report, err := usage.Report()
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if len(report.Daily) != 1 || report.Daily[0].Calls != 2 || report.Daily[0].Failed != 1 {
+		t.Errorf("daily = %+v, want 2 calls with 1 failed", report.Daily)
+	}
+	if len(report.Users) != 1 || report.Users[0].User != "a@example.com" || report.Users[0].Paid != 2 {
+		t.Errorf("users = %+v", report.Users)
+	}
+}
@@ -0,0 +1,158 @@
+// Package place provides the household's named places (work, school,
+// family) and straight-line distances from properties to them.
+package place
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Place is a named location the household cares about being near.
+type Place struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Distance is how far a property is from a place.
+type Distance struct {
+	Place string  `json:"place"`
+	Miles float64 `json:"miles"`
+	Drive string  `json:"drive"` // estimated drive time band, e.g. "10-20 min"
+}
+
+// Limit caps the distance to a place, as parsed from "work=15mi".
+type Limit struct {
+	Place string
+	Miles float64
+}
+
+const (
+	earthRadiusMiles = 3958.8
+	kmPerMile        = 1.609344
+
+	// Roads wind, so a drive is longer than the straight line, and suburban
+	// trips average well under highway speed. These are rough on purpose;
+	// the band is a hint for comparing houses, not a route.
+	roadFactor = 1.3
+	avgMPH     = 35
+)
+
+// driveBands are the upper bounds, in minutes, of the drive time bands.
+var driveBands = []struct {
+	max   float64
+	label string
+}{
+	{10, "under 10 min"},
+	{20, "10-20 min"},
+	{30, "20-30 min"},
+	{45, "30-45 min"},
+	{60, "45-60 min"},
+}
+
+// Miles returns the great-circle distance between two coordinates.
+func Miles(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(a))
+}
+
+// DriveBand estimates a drive time band for a straight-line distance.
+func DriveBand(miles float64) string {
+	minutes := miles * roadFactor / avgMPH * 60
+	for _, b := range driveBands {
+		if minutes < b.max {
+			return b.label
+		}
+	}
+	return "over 1 hr"
+}
+
+// Measure returns the distance from a coordinate to each place, in the
+// order given. It returns nil when the coordinate is unknown.
+func Measure(places []*Place, lat, lon *float64) []Distance {
+	if lat == nil || lon == nil || len(places) == 0 {
+		return nil
+	}
+	distances := make([]Distance, len(places))
+	for i, pl := range places {
+		miles := Miles(*lat, *lon, pl.Latitude, pl.Longitude)
+		distances[i] = Distance{
+			Place: pl.Name,
+			Miles: math.Round(miles*10) / 10,
+			Drive: DriveBand(miles),
+		}
+	}
+	return distances
+}
+
+// Find returns the distance to the named place, if measured.
+func Find(distances []Distance, name string) (Distance, bool) {
+	for _, d := range distances {
+		if strings.EqualFold(d.Place, name) {
+			return d, true
+		}
+	}
+	return Distance{}, false
+}
+
+// ParseLimit parses "name=15mi", "name=20km", or "name=15" (miles).
+func ParseLimit(s string) (Limit, error) {
+	name, value, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !ok || name == "" || value == "" {
+		return Limit{}, fmt.Errorf("invalid distance limit %q (want place=15mi)", s)
+	}
+
+	factor := 1.0
+	switch {
+	case strings.HasSuffix(value, "mi"):
+		value = strings.TrimSuffix(value, "mi")
+	case strings.HasSuffix(value, "km"):
+		value = strings.TrimSuffix(value, "km")
+		factor = 1 / kmPerMile
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return Limit{}, fmt.Errorf("invalid distance in %q (want a positive number of mi or km)", s)
+	}
+	return Limit{Place: name, Miles: n * factor}, nil
+}
+
+// String formats the limit the way ParseLimit reads it.
+func (l Limit) String() string {
+	return l.Place + "=" + strconv.FormatFloat(l.Miles, 'f', -1, 64) + "mi"
+}
+
+// Within reports whether distances satisfy every limit. A property with no
+// distance to a limited place (no coordinates) never matches.
+func Within(distances []Distance, limits []Limit) bool {
+	for _, l := range limits {
+		d, ok := Find(distances, l.Place)
+		if !ok || d.Miles > l.Miles {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidCoordinate checks latitude and longitude ranges.
+func ValidCoordinate(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package place
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMiles(t *testing.T) {
+	// Oklahoma City to Tulsa is about 98 miles as the crow flies.
+	got := Miles(35.4676, -97.5164, 36.1540, -95.9928)
+	if math.Abs(got-98) > 2 {
+		t.Errorf("Miles(OKC, Tulsa) = %.1f, want about 98", got)
+	}
+	if got := Miles(35.5, -97.5, 35.5, -97.5); got != 0 {
+		t.Errorf("Miles(same point) = %v, want 0", got)
+	}
+}
+
+func TestDriveBand(t *testing.T) {
+	tests := []struct {
+		miles float64
+		want  string
+	}{
+		{1, "under 10 min"},
+		{6, "10-20 min"},
+		{12, "20-30 min"},
+		{20, "30-45 min"},
+		{25, "45-60 min"},
+		{40, "over 1 hr"},
+	}
+	for _, tt := range tests {
+		if got := DriveBand(tt.miles); got != tt.want {
+			t.Errorf("DriveBand(%v) = %q, want %q", tt.miles, got, tt.want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	places := []*Place{
+		{Name: "work", Latitude: 35.4676, Longitude: -97.5164},
+		{Name: "school", Latitude: 35.5067, Longitude: -97.7625},
+	}
+	lat, lon := 35.5067, -97.7625
+
+	got := Measure(places, &lat, &lon)
+	if len(got) != 2 {
+		t.Fatalf("got %d distances, want 2", len(got))
+	}
+	if got[0].Place != "work" || got[0].Miles < 13 || got[0].Miles > 15 {
+		t.Errorf("work distance = %+v, want about 14 mi", got[0])
+	}
+	if got[1].Miles != 0 || got[1].Drive != "under 10 min" {
+		t.Errorf("school distance = %+v, want 0 mi", got[1])
+	}
+
+	if got := Measure(places, nil, &lon); got != nil {
+		t.Errorf("Measure without latitude = %v, want nil", got)
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "work=15mi", want: Limit{"work", 15}},
+		{in: "work=15", want: Limit{"work", 15}},
+		{in: "school = 2.5 MI", want: Limit{"school", 2.5}},
+		{in: "mom's house=16.09344km", want: Limit{"mom's house", 10}},
+		{in: "work", wantErr: true},
+		{in: "=15mi", wantErr: true},
+		{in: "work=", wantErr: true},
+		{in: "work=far", wantErr: true},
+		{in: "work=-3mi", wantErr: true},
+		{in: "work=0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Place != tt.want.Place || math.Abs(got.Miles-tt.want.Miles) > 1e-9 {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	distances := []Distance{{Place: "work", Miles: 12}, {Place: "school", Miles: 3}}
+
+	tests := []struct {
+		name   string
+		limits []Limit
+		want   bool
+	}{
+		{"no limits", nil, true},
+		{"within", []Limit{{"work", 15}}, true},
+		{"case-insensitive", []Limit{{"Work", 15}}, true},
+		{"too far", []Limit{{"work", 10}}, false},
+		{"all must hold", []Limit{{"work", 15}, {"school", 2}}, false},
+		{"unmeasured place", []Limit{{"gym", 50}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Within(distances, tt.limits); got != tt.want {
+				t.Errorf("Within = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
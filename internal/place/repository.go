@@ -0,0 +1,126 @@
+package place
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned when a named place doesn't exist.
+var ErrNotFound = errors.New("place not found")
+
+// Repository provides CRUD operations for places.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a place repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Add saves a named place. Names are unique, ignoring case.
+func (r *Repository) Add(name string, lat, lon float64) (*Place, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("place name is required")
+	}
+	if strings.ContainsAny(name, "=/") {
+		return nil, fmt.Errorf("place name can't contain '=' or '/'")
+	}
+	if err := ValidCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.GetByName(name); err == nil {
+		return nil, fmt.Errorf("place %q already exists", name)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO places (name, latitude, longitude) VALUES (?, ?, ?)",
+		name, lat, lon,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting place: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("getting insert id: %w", err)
+	}
+
+	var p Place
+	err = r.db.QueryRow(
+		"SELECT id, name, latitude, longitude, created_at FROM places WHERE id = ?", id,
+	).Scan(&p.ID, &p.Name, &p.Latitude, &p.Longitude, &p.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("reading back place: %w", err)
+	}
+
+	return &p, nil
+}
+
+// GetByName returns the place with the given name, ignoring case.
+func (r *Repository) GetByName(name string) (*Place, error) {
+	var p Place
+	err := r.db.QueryRow(
+		"SELECT id, name, latitude, longitude, created_at FROM places WHERE name = ? COLLATE NOCASE",
+		strings.TrimSpace(name),
+	).Scan(&p.ID, &p.Name, &p.Latitude, &p.Longitude, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting place: %w", err)
+	}
+	return &p, nil
+}
+
+// List returns all places in the order they were added.
+func (r *Repository) List() (_ []*Place, err error) {
+	rows, err := r.db.Query("SELECT id, name, latitude, longitude, created_at FROM places ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("listing places: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var places []*Place
+	for rows.Next() {
+		var p Place
+		if err := rows.Scan(&p.ID, &p.Name, &p.Latitude, &p.Longitude, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning place: %w", err)
+		}
+		places = append(places, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating places: %w", err)
+	}
+
+	return places, nil
+}
+
+// Delete removes a place by name, ignoring case.
+func (r *Repository) Delete(name string) error {
+	result, err := r.db.Exec("DELETE FROM places WHERE name = ? COLLATE NOCASE", strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("deleting place: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	return nil
+}
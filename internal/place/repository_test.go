@@ -0,0 +1,94 @@
+package place
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/db"
+)
+
+func testRepo(t *testing.T) *Repository {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return NewRepository(d)
+}
+
+func TestAddListDelete(t *testing.T) {
+	repo := testRepo(t)
+
+	work, err := repo.Add(" work ", 35.4676, -97.5164)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if work.ID == 0 || work.Name != "work" {
+		t.Errorf("added place = %+v", work)
+	}
+	if _, err := repo.Add("school", 35.5067, -97.7625); err != nil {
+		t.Fatalf("add school: %v", err)
+	}
+
+	places, err := repo.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(places) != 2 || places[0].Name != "work" || places[1].Name != "school" {
+		t.Fatalf("places = %+v, want work then school", places)
+	}
+
+	got, err := repo.GetByName("WORK")
+	if err != nil {
+		t.Fatalf("get by name: %v", err)
+	}
+	if got.ID != work.ID {
+		t.Errorf("GetByName(WORK) = %d, want %d", got.ID, work.ID)
+	}
+
+	if err := repo.Delete("Work"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Delete("work"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete = %v, want ErrNotFound", err)
+	}
+	places, err = repo.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(places) != 1 {
+		t.Errorf("got %d places after delete, want 1", len(places))
+	}
+}
+
+func TestAddInvalid(t *testing.T) {
+	repo := testRepo(t)
+	if _, err := repo.Add("work", 35.4, -97.5); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		place    string
+		lat, lon float64
+	}{
+		{"empty name", "  ", 35, -97},
+		{"name with equals", "a=b", 35, -97},
+		{"duplicate ignoring case", "Work", 35, -97},
+		{"latitude out of range", "north", 91, 0},
+		{"longitude out of range", "east", 0, 181},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.Add(tt.place, tt.lat, tt.lon); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
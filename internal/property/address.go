@@ -0,0 +1,172 @@
+package property
+
+import (
+	"regexp"
+	"strings"
+)
+
+// streetSuffixes maps street types to their USPS abbreviations.
+var streetSuffixes = map[string]string{
+	"ALLEY": "ALY", "AVENUE": "AVE", "AV": "AVE", "BOULEVARD": "BLVD", "CIRCLE": "CIR",
+	"COURT": "CT", "COVE": "CV", "CROSSING": "XING", "DRIVE": "DR", "EXPRESSWAY": "EXPY",
+	"FREEWAY": "FWY", "HIGHWAY": "HWY", "HOLLOW": "HOLW", "LANE": "LN", "LOOP": "LOOP",
+	"PARKWAY": "PKWY", "PLACE": "PL", "PLAZA": "PLZ", "POINT": "PT", "ROAD": "RD",
+	"ROUTE": "RTE", "SQUARE": "SQ", "STREET": "ST", "TERRACE": "TER", "TRAIL": "TRL",
+	"TURNPIKE": "TPKE", "WAY": "WAY",
+}
+
+// directionals maps compass words to their USPS abbreviations.
+var directionals = map[string]string{
+	"NORTH": "N", "SOUTH": "S", "EAST": "E", "WEST": "W",
+	"NORTHEAST": "NE", "NORTHWEST": "NW", "SOUTHEAST": "SE", "SOUTHWEST": "SW",
+}
+
+// unitDesignators are the words that introduce a unit number. They all
+// normalize to UNIT so "Apt 4" and "#4" compare equal.
+var unitDesignators = map[string]bool{
+	"#": true, "APT": true, "APARTMENT": true, "STE": true, "SUITE": true,
+	"UNIT": true, "UNT": true,
+}
+
+// states maps state names to their postal codes.
+var states = map[string]string{
+	"ALABAMA": "AL", "ALASKA": "AK", "ARIZONA": "AZ", "ARKANSAS": "AR", "CALIFORNIA": "CA",
+	"COLORADO": "CO", "CONNECTICUT": "CT", "DELAWARE": "DE", "DISTRICT OF COLUMBIA": "DC",
+	"FLORIDA": "FL", "GEORGIA": "GA", "HAWAII": "HI", "IDAHO": "ID", "ILLINOIS": "IL",
+	"INDIANA": "IN", "IOWA": "IA", "KANSAS": "KS", "KENTUCKY": "KY", "LOUISIANA": "LA",
+	"MAINE": "ME", "MARYLAND": "MD", "MASSACHUSETTS": "MA", "MICHIGAN": "MI", "MINNESOTA": "MN",
+	"MISSISSIPPI": "MS", "MISSOURI": "MO", "MONTANA": "MT", "NEBRASKA": "NE", "NEVADA": "NV",
+	"NEW HAMPSHIRE": "NH", "NEW JERSEY": "NJ", "NEW MEXICO": "NM", "NEW YORK": "NY",
+	"NORTH CAROLINA": "NC", "NORTH DAKOTA": "ND", "OHIO": "OH", "OKLAHOMA": "OK", "OREGON": "OR",
+	"PENNSYLVANIA": "PA", "RHODE ISLAND": "RI", "SOUTH CAROLINA": "SC", "SOUTH DAKOTA": "SD",
+	"TENNESSEE": "TN", "TEXAS": "TX", "UTAH": "UT", "VERMONT": "VT", "VIRGINIA": "VA",
+	"WASHINGTON": "WA", "WEST VIRGINIA": "WV", "WISCONSIN": "WI", "WYOMING": "WY",
+}
+
+var (
+	zipPattern   = regexp.MustCompile(`^(\d{5})(-\d{4})?$`)
+	stateZipLine = regexp.MustCompile(`^([A-Z ]+?)(?: (\d{5}))?$`)
+)
+
+// NormalizeAddress returns a canonical form of a US street address for
+// spotting duplicates: upper case, punctuation dropped, USPS street suffix
+// and directional abbreviations, every unit designator as UNIT, state names
+// as postal codes and ZIP+4 cut to five digits. Comma-separated parts are
+// kept, so "123 North Main Street, Apt. 4, Edmond, Oklahoma 73034-1234"
+// becomes "123 N MAIN ST UNIT 4, EDMOND, OK 73034".
+func NormalizeAddress(addr string) string {
+	var parts []string
+	for _, raw := range strings.Split(addr, ",") {
+		words := normalizeWords(raw)
+		if len(words) == 0 {
+			continue
+		}
+		// A unit on its own ("123 Main St, Apt 4") belongs to the street line.
+		if words[0] == "UNIT" && len(parts) > 0 {
+			parts[len(parts)-1] += " " + strings.Join(words, " ")
+			continue
+		}
+		parts = append(parts, strings.Join(words, " "))
+	}
+
+	if n := len(parts); n > 1 {
+		switch parts[n-1] {
+		case "US", "USA", "UNITED STATES", "UNITED STATES OF AMERICA":
+			parts = parts[:n-1]
+		}
+	}
+	if n := len(parts); n > 1 {
+		parts[n-1] = normalizeStateZip(parts[n-1])
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// normalizeWords upper-cases one comma-separated part of an address,
+// strips punctuation and abbreviates each word.
+func normalizeWords(s string) []string {
+	s = strings.ToUpper(s)
+	s = strings.NewReplacer(".", "", "'", "", "#", " # ").Replace(s)
+
+	var words []string
+	for _, w := range strings.Fields(s) {
+		w = strings.Trim(w, `"()`)
+		switch {
+		case w == "":
+			continue
+		case unitDesignators[w]:
+			// "Apt #4" has two designators; keep one.
+			if len(words) > 0 && words[len(words)-1] == "UNIT" {
+				continue
+			}
+			w = "UNIT"
+		case streetSuffixes[w] != "":
+			w = streetSuffixes[w]
+		case directionals[w] != "":
+			w = directionals[w]
+		default:
+			if m := zipPattern.FindStringSubmatch(w); m != nil {
+				w = m[1]
+			}
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// normalizeStateZip abbreviates a spelled-out state in the final part of
+// an address ("OKLAHOMA 73034" → "OK 73034"). Directionals were already
+// abbreviated, so "N CAROLINA" is matched too.
+func normalizeStateZip(s string) string {
+	m := stateZipLine.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	name := m[1]
+	if i := strings.IndexByte(name, ' '); i > 0 {
+		for word, abbr := range directionals {
+			if name[:i] == abbr {
+				name = word + name[i:]
+				break
+			}
+		}
+	}
+	code, ok := states[name]
+	if !ok {
+		return s
+	}
+	if m[2] != "" {
+		return code + " " + m[2]
+	}
+	return code
+}
+
+// addressParts splits a canonical address into its street line and ZIP
+// code, either of which may be empty.
+func addressParts(canonical string) (street, zip string) {
+	parts := strings.Split(canonical, ", ")
+	street = parts[0]
+	if len(parts) > 1 {
+		fields := strings.Fields(parts[len(parts)-1])
+		if n := len(fields); n > 0 && zipPattern.MatchString(fields[n-1]) {
+			zip = fields[n-1]
+		}
+	}
+	return street, zip
+}
+
+// SameHouse reports whether two canonical addresses look like the same
+// house: the same street line (including any unit) and no conflicting ZIP
+// codes. City names are ignored, since people type "OKC" for "Oklahoma
+// City" and leave the city off entirely.
+func SameHouse(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	streetA, zipA := addressParts(a)
+	streetB, zipB := addressParts(b)
+	if streetA != streetB {
+		return false
+	}
+	return zipA == "" || zipB == "" || zipA == zipB
+}
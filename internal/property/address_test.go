@@ -0,0 +1,50 @@
+package property
+
+import "testing"
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"123 North Main Street, Apt. 4, Edmond, Oklahoma 73034-1234", "123 N MAIN ST UNIT 4, EDMOND, OK 73034"},
+		{"123 N Main St #4, Edmond, OK 73034", "123 N MAIN ST UNIT 4, EDMOND, OK 73034"},
+		{"123 n. main st. apt #4,  edmond , ok 73034", "123 N MAIN ST UNIT 4, EDMOND, OK 73034"},
+		{"500 Elm Avenue Suite 200, Norman, OK, USA", "500 ELM AVE UNIT 200, NORMAN, OK"},
+		{"9 Pine Ct, Raleigh, North Carolina 27601", "9 PINE CT, RALEIGH, NC 27601"},
+		{"9 Pine Ct, Raleigh, NC", "9 PINE CT, RALEIGH, NC"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := NormalizeAddress(tt.in); got != tt.want {
+				t.Errorf("NormalizeAddress(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameHouse(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"identical", "123 Main St, Edmond, OK 73034", "123 Main Street, Edmond, Oklahoma 73034", true},
+		{"city spelled differently", "123 Main St, OKC, OK 73101", "123 Main St, Oklahoma City, OK 73101", true},
+		{"one without city or zip", "123 Main St", "123 Main St, Edmond, OK 73034", true},
+		{"different zip", "123 Main St, Edmond, OK 73034", "123 Main St, Tulsa, OK 74103", false},
+		{"different unit", "123 Main St Apt 4, Edmond", "123 Main St Apt 5, Edmond", false},
+		{"unit vs none", "123 Main St Apt 4", "123 Main St", false},
+		{"different number", "123 Main St", "125 Main St", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameHouse(NormalizeAddress(tt.a), NormalizeAddress(tt.b)); got != tt.want {
+				t.Errorf("SameHouse(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package property
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Duplicate identifies another tracked property that looks like the same
+// house.
+type Duplicate struct {
+	ID      int64  `json:"id"`
+	Address string `json:"address"`
+}
+
+// DuplicateError is returned when a property being added looks like the
+// same house as ones already tracked. Nothing is saved; adding again with
+// force set skips the check.
+type DuplicateError struct {
+	Duplicates []Duplicate `json:"duplicates"`
+}
+
+func (e *DuplicateError) Error() string {
+	matches := make([]string, len(e.Duplicates))
+	for i, d := range e.Duplicates {
+		matches[i] = fmt.Sprintf("#%d (%s)", d.ID, d.Address)
+	}
+	return "looks like a house already tracked: " + strings.Join(matches, ", ")
+}
+
+// DuplicateGroup is a set of tracked properties that look like the same
+// house, oldest first.
+type DuplicateGroup struct {
+	Address    string      `json:"address"` // canonical address of the oldest
+	Properties []*Property `json:"properties"`
+}
+
+// canonicalAddress is the normalized address stored for duplicate checks.
+// The listing's own address is preferred because it carries the city and
+// ZIP; the address as typed is the fallback for manual entries.
+func canonicalAddress(p *Property) string {
+	if f := parseRawJSON(p.RawJSON); f.Address != nil {
+		return NormalizeAddress(*f.Address)
+	}
+	return NormalizeAddress(p.Address)
+}
+
+// addressRow is the subset of a property needed to compare addresses.
+type addressRow struct {
+	id        int64
+	address   string
+	canonical string
+}
+
+// listAddresses returns the ID and addresses of every property not in the
+// trash, oldest first. Archived houses are included: they were looked at
+// before, which is worth knowing when the same house turns up again.
+func (r *Repository) listAddresses() (_ []addressRow, err error) {
+	rows, err := r.db.Query("SELECT id, address, address_canonical FROM properties WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("listing addresses: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var addrs []addressRow
+	for rows.Next() {
+		var a addressRow
+		if err := rows.Scan(&a.id, &a.address, &a.canonical); err != nil {
+			return nil, fmt.Errorf("scanning address: %w", err)
+		}
+		addrs = append(addrs, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating addresses: %w", err)
+	}
+
+	return addrs, nil
+}
+
+// findDuplicates returns the properties other than id whose address looks
+// like the same house as canonical.
+func (r *Repository) findDuplicates(id int64, canonical string) ([]Duplicate, error) {
+	addrs, err := r.listAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	var dups []Duplicate
+	for _, a := range addrs {
+		if a.id != id && SameHouse(a.canonical, canonical) {
+			dups = append(dups, Duplicate{ID: a.id, Address: a.address})
+		}
+	}
+	return dups, nil
+}
+
+// DuplicatesOf returns the other tracked properties that look like the
+// same house as p, which need not be saved yet.
+func (r *Repository) DuplicatesOf(p *Property) ([]Duplicate, error) {
+	canonical := p.Canonical
+	if canonical == "" {
+		canonical = canonicalAddress(p)
+	}
+	return r.findDuplicates(p.ID, canonical)
+}
+
+// checkDuplicates returns a *DuplicateError if the unsaved p looks like
+// the same house as any tracked property.
+func (r *Repository) checkDuplicates(p *Property) error {
+	dups, err := r.DuplicatesOf(p)
+	if err != nil {
+		return err
+	}
+	if len(dups) > 0 {
+		return &DuplicateError{Duplicates: dups}
+	}
+	return nil
+}
+
+// DuplicateGroups returns every set of two or more tracked properties
+// that look like the same house.
+func (r *Repository) DuplicateGroups() ([]*DuplicateGroup, error) {
+	addrs, err := r.listAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	// Group transitively: if a matches b and b matches c, all three are
+	// one house even when a and c alone wouldn't match (say a has no ZIP).
+	group := make([]int, len(addrs))
+	for i := range group {
+		group[i] = i
+	}
+	root := func(i int) int {
+		for group[i] != i {
+			i = group[i]
+		}
+		return i
+	}
+	for i := range addrs {
+		for j := i + 1; j < len(addrs); j++ {
+			if SameHouse(addrs[i].canonical, addrs[j].canonical) {
+				group[root(j)] = root(i)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	var order []int
+	for i := range addrs {
+		g := root(i)
+		if _, ok := members[g]; !ok {
+			order = append(order, g)
+		}
+		members[g] = append(members[g], i)
+	}
+
+	var ids []int64
+	for _, g := range order {
+		if len(members[g]) < 2 {
+			continue
+		}
+		for _, i := range members[g] {
+			ids = append(ids, addrs[i].id)
+		}
+	}
+	props, err := r.getByIDs(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*DuplicateGroup, 0)
+	for _, g := range order {
+		if len(members[g]) < 2 {
+			continue
+		}
+		dg := &DuplicateGroup{Address: addrs[members[g][0]].canonical}
+		for _, i := range members[g] {
+			dg.Properties = append(dg.Properties, props[addrs[i].id])
+		}
+		groups = append(groups, dg)
+	}
+	return groups, nil
+}
+
+// BackfillCanonical fills the canonical address of properties saved
+// before it was tracked. It returns how many were updated.
+func (r *Repository) BackfillCanonical() (int, error) {
+	props, err := r.listListings(ListOptions{State: StateAll})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, p := range props {
+		if p.Canonical != "" {
+			continue
+		}
+		if _, err := r.db.Exec(
+			"UPDATE properties SET address_canonical = ? WHERE id = ?", canonicalAddress(p), p.ID,
+		); err != nil {
+			return n, fmt.Errorf("updating canonical address for property %d: %w", p.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// visitStatusRank orders visit statuses by how far along they are.
+var visitStatusRank = map[VisitStatus]int{
+	VisitStatusNotVisited:  0,
+	VisitStatusWantToVisit: 1,
+	VisitStatusVisited:     2,
+}
+
+// Merge folds property dropID into keepID and deletes dropID. Comments,
+// visits, listing history, alerts, tags, ratings, hand scores and hand
+// edits move to keepID (keepID's own ratings, scores and edits win a
+// conflict), and keepID takes whichever visit status is further along.
+// keepID's listing data is left alone, so keep the one linked to the
+// current MLS listing.
+func (r *Repository) Merge(keepID, dropID int64) (merged *Property, err error) {
+	if keepID == dropID {
+		return nil, fmt.Errorf("cannot merge property %d into itself", keepID)
+	}
+	keep, err := r.getListing(keepID)
+	if err != nil {
+		return nil, err
+	}
+	drop, err := r.getListing(dropID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	for _, stmt := range []string{
+		"UPDATE comments SET property_id = ? WHERE property_id = ?",
+		"UPDATE visits SET property_id = ? WHERE property_id = ?",
+		"UPDATE property_snapshots SET property_id = ? WHERE property_id = ?",
+		"UPDATE listing_events SET property_id = ? WHERE property_id = ?",
+		"UPDATE OR IGNORE property_overrides SET property_id = ? WHERE property_id = ?",
+		"UPDATE OR IGNORE property_tags SET property_id = ? WHERE property_id = ?",
+		"UPDATE OR IGNORE property_scores SET property_id = ? WHERE property_id = ?",
+		"UPDATE OR IGNORE property_ratings SET property_id = ? WHERE property_id = ?",
+	} {
+		if _, err = tx.Exec(stmt, keepID, dropID); err != nil {
+			return nil, fmt.Errorf("moving records: %w", err)
+		}
+	}
+
+	status := keep.VisitStatus
+	if visitStatusRank[drop.VisitStatus] > visitStatusRank[status] {
+		status = drop.VisitStatus
+	}
+	if _, err = tx.Exec(
+		"UPDATE properties SET visit_status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		string(status), keepID,
+	); err != nil {
+		return nil, fmt.Errorf("updating merged property: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM properties WHERE id = ?", dropID); err != nil {
+		return nil, fmt.Errorf("deleting merged property: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing merge: %w", err)
+	}
+
+	return r.GetByID(keepID)
+}
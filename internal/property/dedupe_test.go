@@ -0,0 +1,229 @@
+package property
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
+)
+
+func insertAt(t *testing.T, repo *Repository, address, mprID string) *Property {
+	t.Helper()
+	p, err := repo.Insert(&Property{
+		Address:    address,
+		MprID:      mprID,
+		RealtorURL: "https://realtor.com/" + mprID,
+		RawJSON:    json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert %s: %v", address, err)
+	}
+	return p
+}
+
+func TestInsertManualRefusesDuplicates(t *testing.T) {
+	repo := testRepo(t)
+
+	first, err := repo.InsertManual(ManualInput{Address: "123 North Main Street, Edmond, OK 73034"})
+	if err != nil {
+		t.Fatalf("first insert: %v", err)
+	}
+	if first.Canonical != "123 N MAIN ST, EDMOND, OK 73034" {
+		t.Errorf("canonical = %q", first.Canonical)
+	}
+
+	relisted := ManualInput{Address: "123 N. Main St, Edmond, Oklahoma"}
+	_, err = repo.InsertManual(relisted)
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || len(dupErr.Duplicates) != 1 || dupErr.Duplicates[0].ID != first.ID {
+		t.Fatalf("relisted insert error = %v, want a duplicate of #%d", err, first.ID)
+	}
+	all, err := repo.List(ListOptions{State: StateAll})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("%d properties saved, want the duplicate refused", len(all))
+	}
+
+	relisted.Force = true
+	if _, err := repo.InsertManual(relisted); err != nil {
+		t.Errorf("forced insert: %v", err)
+	}
+	if _, err := repo.InsertManual(ManualInput{Address: "125 N Main St, Edmond, OK 73034"}); err != nil {
+		t.Errorf("neighbor insert: %v", err)
+	}
+
+	// The listing's own address is used when there is one.
+	raw := json.RawMessage(`{"data":{"location":{"address":{"line":"9 Elm Ct","city":"Norman","state_code":"OK","postal_code":"73069"}}}}`)
+	p, err := repo.Insert(&Property{Address: "9 elm court", MprID: "M4", RealtorURL: "https://realtor.com/M4", RawJSON: raw})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if p.Canonical != "9 ELM CT, NORMAN, OK 73069" {
+		t.Errorf("canonical from listing = %q", p.Canonical)
+	}
+}
+
+func TestDuplicateGroups(t *testing.T) {
+	repo := testRepo(t)
+
+	a := insertAt(t, repo, "123 Main St", "M1")
+	insertAt(t, repo, "500 Elm Ave, Norman, OK 73069", "M2")
+	b := insertAt(t, repo, "123 Main Street, Edmond, OK 73034", "M3")
+	c := insertAt(t, repo, "123 MAIN ST, EDMOND, OKLAHOMA 73034", "M4")
+
+	groups, err := repo.DuplicateGroups()
+	if err != nil {
+		t.Fatalf("duplicate groups: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1: %+v", len(groups), groups)
+	}
+	g := groups[0]
+	if g.Address != "123 MAIN ST" {
+		t.Errorf("group address = %q, want the oldest entry's", g.Address)
+	}
+	if len(g.Properties) != 3 || g.Properties[0].ID != a.ID || g.Properties[1].ID != b.ID || g.Properties[2].ID != c.ID {
+		t.Errorf("group = %+v", g.Properties)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	d, repo := testDBAndRepo(t)
+
+	keep := insertAt(t, repo, "123 Main St", "M1")
+	dup := insertAt(t, repo, "123 Main Street", "M2")
+
+	for _, r := range []struct {
+		id     int64
+		email  string
+		rating int
+	}{
+		{keep.ID, "b@example.com", 1},
+		{dup.ID, "", 2},
+		{dup.ID, "a@example.com", 3},
+		{dup.ID, "b@example.com", 4},
+	} {
+		if err := repo.UpdateRating(r.id, r.email, r.rating); err != nil {
+			t.Fatalf("rate: %v", err)
+		}
+	}
+	if err := repo.UpdateVisitStatus(dup.ID, VisitStatusVisited); err != nil {
+		t.Fatalf("visit status: %v", err)
+	}
+	for _, stmt := range []string{
+		"INSERT INTO comments (property_id, text) VALUES (?, 'great yard')",
+		"INSERT INTO visits (property_id, visit_date, visit_type) VALUES (?, '2024-03-02', 'showing')",
+		"INSERT INTO property_snapshots (property_id, field, old_value, new_value) VALUES (?, 'price', '250000', '240000')",
+	} {
+		if _, err := d.Exec(stmt, dup.ID); err != nil {
+			t.Fatalf("seed %q: %v", stmt, err)
+		}
+	}
+	tags := tag.NewRepository(d)
+	if err := tags.Add(keep.ID, "top pick"); err != nil {
+		t.Fatalf("tag keep: %v", err)
+	}
+	if err := tags.Add(dup.ID, "top pick", "big yard"); err != nil {
+		t.Fatalf("tag dup: %v", err)
+	}
+	scores := score.NewRepository(d)
+	if _, err := scores.Add(&score.Criterion{Name: "kitchen", Weight: 1}); err != nil {
+		t.Fatalf("add criterion: %v", err)
+	}
+	if err := scores.SetScores(dup.ID, map[string]int{"kitchen": 5}); err != nil {
+		t.Fatalf("score dup: %v", err)
+	}
+	if err := repo.ApplyEdit(keep.ID, Edit{"bedrooms": strPtr("4")}); err != nil {
+		t.Fatalf("edit keep: %v", err)
+	}
+	if err := repo.ApplyEdit(dup.ID, Edit{"bedrooms": strPtr("3"), "sqft": strPtr("1800")}); err != nil {
+		t.Fatalf("edit dup: %v", err)
+	}
+
+	merged, err := repo.Merge(keep.ID, dup.ID)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if r := merged.RatingBy("a@example.com"); r == nil || *r != 3 {
+		t.Errorf("a's rating = %v, want 3 from the duplicate", r)
+	}
+	if r := merged.RatingBy("b@example.com"); r == nil || *r != 1 {
+		t.Errorf("b's rating = %v, want their own 1", r)
+	}
+	if r := merged.RatingBy(""); r == nil || *r != 2 {
+		t.Errorf("shared rating = %v, want 2 from the duplicate", r)
+	}
+	if merged.VisitStatus != VisitStatusVisited {
+		t.Errorf("visit status = %q, want visited", merged.VisitStatus)
+	}
+	if merged.MprID != "M1" {
+		t.Errorf("mpr_id = %q, want the kept listing's", merged.MprID)
+	}
+	if merged.Bedrooms == nil || *merged.Bedrooms != 4 {
+		t.Errorf("bedrooms = %v, want the kept property's edit", merged.Bedrooms)
+	}
+	if merged.Sqft == nil || *merged.Sqft != 1800 {
+		t.Errorf("sqft = %v, want the duplicate's edit carried over", merged.Sqft)
+	}
+	if fmt.Sprint(merged.Tags) != "[big yard top pick]" {
+		t.Errorf("tags = %v, want both properties' tags", merged.Tags)
+	}
+	if merged.Score == nil || merged.Score.Total == nil || *merged.Score.Total != 100 {
+		t.Errorf("score = %+v, want the duplicate's kitchen score carried over", merged.Score)
+	}
+
+	for _, table := range []string{"comments", "visits", "property_snapshots"} {
+		var n int
+		if err := d.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE property_id = ?", keep.ID).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != 1 {
+			t.Errorf("%s on kept property = %d, want 1", table, n)
+		}
+	}
+
+	if _, err := repo.GetByID(dup.ID); err == nil {
+		t.Error("duplicate still exists after merge")
+	}
+	if _, err := repo.Merge(keep.ID, keep.ID); err == nil {
+		t.Error("expected error merging a property into itself")
+	}
+	if _, err := repo.Merge(keep.ID, dup.ID); err == nil {
+		t.Error("expected error merging a deleted property")
+	}
+}
+
+func TestBackfillCanonical(t *testing.T) {
+	d, repo := testDBAndRepo(t)
+
+	if _, err := d.Exec(
+		"INSERT INTO properties (address, mpr_id, realtor_url, raw_json) VALUES ('42 West Oak Road', 'M1', '', '{}')",
+	); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	n, err := repo.BackfillCanonical()
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("backfilled %d, want 1", n)
+	}
+	p, err := repo.GetByMprID("M1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if p.Canonical != "42 W OAK RD" {
+		t.Errorf("canonical = %q", p.Canonical)
+	}
+
+	if n, err := repo.BackfillCanonical(); err != nil || n != 0 {
+		t.Errorf("second backfill = %d, %v; want 0", n, err)
+	}
+}
@@ -0,0 +1,250 @@
+package property
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/place"
+)
+
+// Range bounds a numeric listing field. A nil end is open; a property
+// without a value for the field never falls in a range with either end set.
+type Range struct {
+	Min *float64
+	Max *float64
+}
+
+// IsZero reports whether neither end is set.
+func (r Range) IsZero() bool {
+	return r.Min == nil && r.Max == nil
+}
+
+func (r Range) contains(v *float64) bool {
+	if r.IsZero() {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+	return (r.Min == nil || *v >= *r.Min) && (r.Max == nil || *v <= *r.Max)
+}
+
+// City returns the city from an address formatted "line, city, ST 12345",
+// or "" if the address has no city part.
+func (p *Property) City() string {
+	parts := strings.Split(p.Address, ",")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.TrimSpace(parts[len(parts)-2])
+}
+
+// Zip returns the five-digit ZIP code at the end of the address, or "".
+func (p *Property) Zip() string {
+	_, zip := addressParts(p.Canonical)
+	return zip
+}
+
+// matches reports whether p passes opts' listing-field filters. They run
+// after overrides are applied, so a corrected value is what's filtered on.
+func (opts ListOptions) matches(p *Property) bool {
+	return opts.Price.contains(AsFloat(p.Price)) &&
+		opts.Beds.contains(p.Bedrooms) &&
+		opts.Baths.contains(p.Bathrooms) &&
+		opts.Sqft.contains(AsFloat(p.Sqft)) &&
+		opts.YearBuilt.contains(AsFloat(p.YearBuilt)) &&
+		anyOf(opts.Types, StringValue(p.PropertyType)) &&
+		anyOf(opts.Statuses, StringValue(p.Status)) &&
+		anyOf(opts.Cities, p.City()) &&
+		anyOf(opts.Zips, p.Zip())
+}
+
+// matchesHousehold reports whether p passes opts' visit status, tag and
+// rating filters, which List runs in SQL.
+func (opts ListOptions) matchesHousehold(p *Property) bool {
+	if opts.VisitStatus != "" && p.VisitStatus != opts.VisitStatus {
+		return false
+	}
+	for _, name := range opts.Tags {
+		name = strings.Join(strings.Fields(name), " ")
+		if !slices.ContainsFunc(p.Tags, func(t string) bool { return strings.EqualFold(t, name) }) {
+			return false
+		}
+	}
+	if opts.MinRating != nil {
+		rating := p.Rating
+		if opts.RatedBy != "" {
+			rating = p.RatingBy(opts.RatedBy)
+		}
+		if rating == nil || *rating < int64(*opts.MinRating) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the properties in props that pass opts, sorted by
+// opts.Sort, as List would have returned them. It lets a caller load the
+// houses once and slice them several ways. props should come from List in
+// its default order; opts.State is not applied, and tags and places named
+// in opts aren't checked to exist.
+func Filter(props []*Property, opts ListOptions) ([]*Property, error) {
+	order, err := ParseSort(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Property
+	for _, p := range props {
+		if opts.matches(p) && opts.matchesHousehold(p) && place.Within(p.Distances, opts.MaxDistance) {
+			matched = append(matched, p)
+		}
+	}
+	sortProperties(matched, order)
+	return matched, nil
+}
+
+// anyOf reports whether v is one of want, ignoring case; an empty want
+// matches everything.
+func anyOf(want []string, v string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if strings.EqualFold(strings.TrimSpace(w), v) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortKey is a field properties can be sorted on. desc is its natural
+// direction, best first: cheapest, biggest, newest, highest rated.
+type sortKey struct {
+	name  string
+	desc  bool
+	value func(*Property) *float64
+}
+
+var sortKeys = []sortKey{
+	{"price", false, func(p *Property) *float64 { return AsFloat(p.Price) }},
+	{"price_per_sqft", false, func(p *Property) *float64 {
+		if p.Price == nil || p.Sqft == nil || *p.Sqft <= 0 {
+			return nil
+		}
+		v := float64(*p.Price) / float64(*p.Sqft)
+		return &v
+	}},
+	{"bedrooms", true, func(p *Property) *float64 { return p.Bedrooms }},
+	{"bathrooms", true, func(p *Property) *float64 { return p.Bathrooms }},
+	{"sqft", true, func(p *Property) *float64 { return AsFloat(p.Sqft) }},
+	{"lot_size", true, func(p *Property) *float64 { return p.LotSize }},
+	{"year_built", true, func(p *Property) *float64 { return AsFloat(p.YearBuilt) }},
+	{"hoa_fee", false, func(p *Property) *float64 { return AsFloat(p.HOAFee) }},
+	{"days_on_market", false, func(p *Property) *float64 { return AsFloat(p.DaysOnMarket) }},
+	{"rating", true, func(p *Property) *float64 {
+		if p.Ratings == nil {
+			return nil
+		}
+		return &p.Ratings.Average
+	}},
+	{SortScore, true, func(p *Property) *float64 {
+		if p.Score == nil {
+			return nil
+		}
+		return p.Score.Total
+	}},
+	{"added", true, func(p *Property) *float64 {
+		v := float64(p.CreatedAt.Unix())
+		return &v
+	}},
+}
+
+// SortKeys returns the names of the keys properties can be sorted on.
+func SortKeys() []string {
+	names := make([]string, len(sortKeys))
+	for i, k := range sortKeys {
+		names[i] = k.name
+	}
+	return names
+}
+
+// SortField is one key of a list order and its direction.
+type SortField struct {
+	Key  string
+	Desc bool
+}
+
+// ParseSort parses a list order: comma-separated sort keys (see SortKeys),
+// each optionally followed by :asc or :desc, later keys breaking ties in
+// earlier ones. A key without a direction sorts best first, so "price"
+// is cheapest first and "sqft" biggest first.
+func ParseSort(s string) ([]SortField, error) {
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, dir, hasDir := strings.Cut(part, ":")
+		key := findSortKey(strings.ToLower(strings.TrimSpace(name)))
+		if key == nil {
+			return nil, fmt.Errorf("invalid sort %q (keys: %s)", name, strings.Join(SortKeys(), ", "))
+		}
+		f := SortField{Key: key.name, Desc: key.desc}
+		if hasDir {
+			switch strings.ToLower(strings.TrimSpace(dir)) {
+			case "asc":
+				f.Desc = false
+			case "desc":
+				f.Desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q for %s: want asc or desc", dir, key.name)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func findSortKey(name string) *sortKey {
+	for i := range sortKeys {
+		if sortKeys[i].name == name {
+			return &sortKeys[i]
+		}
+	}
+	return nil
+}
+
+// sortProperties orders properties by fields, keeping the existing order
+// among equals. Properties without a value for a key come after those
+// with one, whichever the direction.
+func sortProperties(props []*Property, fields []SortField) {
+	if len(fields) == 0 {
+		return
+	}
+	slices.SortStableFunc(props, func(a, b *Property) int {
+		for _, f := range fields {
+			value := findSortKey(f.Key).value
+			va, vb := value(a), value(b)
+			switch {
+			case va == nil && vb == nil:
+				continue
+			case va == nil:
+				return 1
+			case vb == nil:
+				return -1
+			}
+			c := cmp.Compare(*va, *vb)
+			if f.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
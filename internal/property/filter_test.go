@@ -0,0 +1,141 @@
+package property
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/tag"
+)
+
+func TestListFilterAndSort(t *testing.T) {
+	d, repo := testDBAndRepo(t)
+
+	i64 := func(v int64) *int64 { return &v }
+	f64 := func(v float64) *float64 { return &v }
+	str := func(v string) *string { return &v }
+	houses := []*Property{
+		{Address: "1 Elm St, Edmond, OK 73034", Price: i64(250000), Bedrooms: f64(3), Bathrooms: f64(2), Sqft: i64(1500), YearBuilt: i64(1995), PropertyType: str("single_family"), Status: str("for_sale")},
+		{Address: "2 Oak Ave, Edmond, OK 73013", Price: i64(320000), Bedrooms: f64(4), Bathrooms: f64(3), Sqft: i64(2400), YearBuilt: i64(2012), PropertyType: str("single_family"), Status: str("pending")},
+		{Address: "3 Pine Ct, Norman, OK 73069", Price: i64(180000), Bedrooms: f64(2), Bathrooms: f64(1), Sqft: i64(1100), YearBuilt: i64(1978), PropertyType: str("condo"), Status: str("for_sale")},
+		{Address: "4 Ash Rd, Norman, OK 73072", Bedrooms: f64(3)}, // manual, no price
+	}
+	ids := make([]int64, len(houses))
+	for i, h := range houses {
+		h.MprID = fmt.Sprintf("M-FILTER-%d", i)
+		h.RealtorURL = "https://realtor.com/" + h.MprID
+		h.RawJSON = json.RawMessage(`{}`)
+		saved, err := repo.Insert(h)
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		ids[i] = saved.ID
+	}
+	// A hand-corrected sqft is what's filtered and sorted on.
+	sqft := "2600"
+	if err := repo.ApplyEdit(ids[0], Edit{"sqft": &sqft}); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if err := repo.UpdateVisitStatus(ids[1], VisitStatusVisited); err != nil {
+		t.Fatalf("visit status: %v", err)
+	}
+	if err := tag.NewRepository(d).Add(ids[2], "Big Yard"); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	for _, r := range []struct {
+		id     int64
+		email  string
+		rating int
+	}{{ids[0], "a@example.com", 4}, {ids[3], "a@example.com", 2}, {ids[3], "b@example.com", 4}} {
+		if err := repo.UpdateRating(r.id, r.email, r.rating); err != nil {
+			t.Fatalf("rate: %v", err)
+		}
+	}
+	everything, err := repo.List(ListOptions{})
+	if err != nil {
+		t.Fatalf("list all: %v", err)
+	}
+
+	idsOf := func(props []*Property) string {
+		var got []int64
+		for _, p := range props {
+			got = append(got, p.ID)
+		}
+		return fmt.Sprint(got)
+	}
+	want := func(idx ...int) string {
+		var w []int64
+		for _, i := range idx {
+			w = append(w, ids[i])
+		}
+		return fmt.Sprint(w)
+	}
+
+	three := 3
+	tests := []struct {
+		name string
+		opts ListOptions
+		want string
+	}{
+		{"visit status", ListOptions{VisitStatus: VisitStatusVisited}, want(1)},
+		{"tag ignores case and spacing", ListOptions{Tags: []string{"big  yard"}}, want(2)},
+		{"household rating", ListOptions{MinRating: &three}, want(0, 3)},
+		{"member's rating", ListOptions{MinRating: &three, RatedBy: "a@example.com"}, want(0)},
+		{"price range leaves out unpriced", ListOptions{Price: Range{Min: f64(200000), Max: f64(300000)}, Sort: "price"}, want(0)},
+		{"max price", ListOptions{Price: Range{Max: f64(300000)}, Sort: "price"}, want(2, 0)},
+		{"beds and baths", ListOptions{Beds: Range{Min: f64(3)}, Baths: Range{Min: f64(2)}, Sort: "price"}, want(0, 1)},
+		{"overridden sqft", ListOptions{Sqft: Range{Min: f64(2500)}}, want(0)},
+		{"year", ListOptions{YearBuilt: Range{Min: f64(1990), Max: f64(2000)}}, want(0)},
+		{"type ignores case", ListOptions{Types: []string{"CONDO"}}, want(2)},
+		{"statuses any of", ListOptions{Statuses: []string{"pending", "sold"}}, want(1)},
+		{"city", ListOptions{Cities: []string{"norman"}, Sort: "bedrooms"}, want(3, 2)},
+		{"zips", ListOptions{Zips: []string{"73034", "73069"}, Sort: "price"}, want(2, 0)},
+		{"price desc, unpriced last", ListOptions{Sort: "price:desc"}, want(1, 0, 2, 3)},
+		{"sqft, biggest first", ListOptions{Sort: "sqft"}, want(0, 1, 2, 3)},
+		{"ties broken by later keys", ListOptions{Sort: "bedrooms:asc, price"}, want(2, 0, 3, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props, err := repo.List(tt.opts)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if got := idsOf(props); got != tt.want {
+				t.Errorf("list got %s, want %s", got, tt.want)
+			}
+			// Filtering the loaded houses in memory gives the same answer.
+			filtered, err := Filter(everything, tt.opts)
+			if err != nil {
+				t.Fatalf("filter: %v", err)
+			}
+			if got := idsOf(filtered); got != tt.want {
+				t.Errorf("filter got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, sort := range []string{"pool", "price:up", "price:desc,"} {
+		_, err := repo.List(ListOptions{Sort: sort})
+		if (err != nil) != (sort != "price:desc,") {
+			t.Errorf("sort %q: err = %v", sort, err)
+		}
+	}
+}
+
+func TestCityAndZip(t *testing.T) {
+	tests := []struct {
+		address   string
+		city, zip string
+	}{
+		{"123 Main St, Edmond, OK 73034-1234", "Edmond", "73034"},
+		{"9 Oak Ave, Apt 2, Oklahoma City, OK 73102", "Oklahoma City", "73102"},
+		{"123 Main St, OK 73034", "", "73034"},
+		{"123 Main St", "", ""},
+	}
+	for _, tt := range tests {
+		p := &Property{Address: tt.address, Canonical: NormalizeAddress(tt.address)}
+		if p.City() != tt.city || p.Zip() != tt.zip {
+			t.Errorf("%q: city %q, zip %q; want %q, %q", tt.address, p.City(), p.Zip(), tt.city, tt.zip)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package property
+
+import "strconv"
+
+// FormatDollars formats a whole-dollar amount with thousands separators,
+// 1234567 as "1,234,567"; callers add the "$" and any unit.
+func FormatDollars(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// AsFloat returns a listing number as a float64, or nil if it is unknown.
+func AsFloat(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// StringValue returns a listing string, or "" if it is unknown.
+func StringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
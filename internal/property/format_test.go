@@ -0,0 +1,23 @@
+package property
+
+import "testing"
+
+func TestFormatDollars(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{250000, "250,000"},
+		{1234567, "1,234,567"},
+		{-1500, "-1,500"},
+		{-123456, "-123,456"},
+	}
+	for _, tt := range tests {
+		if got := FormatDollars(tt.n); got != tt.want {
+			t.Errorf("FormatDollars(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
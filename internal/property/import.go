@@ -0,0 +1,295 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+// Import row outcomes.
+const (
+	ImportAdded   = "added"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// Default throttling for Import. Each MLS lookup costs a RapidAPI call, so
+// a long list is spread out rather than sent all at once.
+const (
+	DefaultImportConcurrency = 4
+	DefaultImportInterval    = 500 * time.Millisecond
+)
+
+// ImportRow is one address in a bulk import, with optional values to set
+// once the property is saved. Address may also be a realtor.com listing
+// URL or property ID, as with Add. Row numbers the row in reports, such as
+// its line in the source file; zero means its 1-based position.
+type ImportRow struct {
+	Row         int    `json:"row,omitempty"`
+	Address     string `json:"address"`
+	Rating      *int   `json:"rating,omitempty"`
+	VisitStatus string `json:"visit_status,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// Validate checks a row before any lookup is spent on it.
+func (row ImportRow) Validate() error {
+	if strings.TrimSpace(row.Address) == "" {
+		return fmt.Errorf("address is required")
+	}
+	if row.Rating != nil && (*row.Rating < 1 || *row.Rating > 4) {
+		return fmt.Errorf("rating must be 1-4, got %d", *row.Rating)
+	}
+	if row.VisitStatus != "" && !ValidVisitStatus(row.VisitStatus) {
+		return fmt.Errorf("visit status must be not_visited, want_to_visit, or visited, got %q", row.VisitStatus)
+	}
+	return nil
+}
+
+// ImportResult reports what happened to one row. Results come back in
+// the order the rows were given.
+type ImportResult struct {
+	Row      int       `json:"row"`
+	Address  string    `json:"address"`
+	Status   string    `json:"status"`
+	Property *Property `json:"property,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// ImportOptions control how Import fetches listings.
+type ImportOptions struct {
+	FetchOptions
+
+	// Concurrency is how many lookups may run at once
+	// (0 = DefaultImportConcurrency).
+	Concurrency int
+
+	// Interval is the minimum gap between lookup starts
+	// (0 = DefaultImportInterval).
+	Interval time.Duration
+
+	// Rater is the email of the member whose rating a row's rating is
+	// recorded as.
+	Rater string
+}
+
+// Import adds each row's property, looking listings up in parallel but
+// no faster than opts allow. Rows for houses already tracked, or repeated
+// earlier in rows, are skipped without a lookup when the address alone
+// gives them away, and after the lookup when the listing does. One row
+// failing doesn't stop the others; the results are in row order. Rows
+// not yet looked up when ctx is cancelled fail with its error.
+func (s *Service) Import(ctx context.Context, rows []ImportRow, opts ImportOptions) ([]ImportResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultImportConcurrency
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultImportInterval
+	}
+
+	results := make([]ImportResult, len(rows))
+	pending, err := s.screenImport(rows, results)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex // serializes the tracked-check and insert per listing
+		limit   = newThrottle(opts.Interval)
+		indexes = make(chan int)
+	)
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := limit.wait(ctx); err != nil {
+					results[i].Status = ImportFailed
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i] = s.importRow(ctx, rows[i], results[i], opts, &mu)
+			}
+		}()
+	}
+	for _, i := range pending {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+// screenImport fills in results for rows that can be settled without a
+// lookup (invalid, or a house already tracked) and returns the indexes of
+// the rest.
+func (s *Service) screenImport(rows []ImportRow, results []ImportResult) ([]int, error) {
+	existing, err := s.repo.listAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows queued so far, by canonical address or, for listing URLs and
+	// IDs, mpr_id.
+	type batchKey struct {
+		key string
+		row int
+	}
+	var (
+		pending []int
+		earlier []batchKey
+	)
+	for i, row := range rows {
+		if row.Row == 0 {
+			row.Row = i + 1
+		}
+		row.Address = strings.TrimSpace(row.Address)
+		results[i] = ImportResult{Row: row.Row, Address: row.Address}
+
+		if err := row.Validate(); err != nil {
+			results[i].Status = ImportFailed
+			results[i].Error = err.Error()
+			continue
+		}
+
+		key, reason, err := s.trackedAs(row.Address, existing)
+		if err != nil {
+			return nil, err
+		}
+		for _, prev := range earlier {
+			if reason == "" && (prev.key == key || SameHouse(prev.key, key)) {
+				reason = fmt.Sprintf("same house as row %d", prev.row)
+			}
+		}
+		if reason != "" {
+			results[i].Status = ImportSkipped
+			results[i].Error = reason
+			continue
+		}
+
+		earlier = append(earlier, batchKey{key: key, row: row.Row})
+		pending = append(pending, i)
+	}
+	return pending, nil
+}
+
+// trackedAs returns the key that identifies address within a batch and,
+// if the house is already tracked, why the row should be skipped.
+func (s *Service) trackedAs(address string, existing []addressRow) (key, reason string, err error) {
+	if mprID, _, ok := mls.ParseListingRef(address); ok {
+		p, err := s.repo.GetByMprID(mprID)
+		if err != nil {
+			return "", "", err
+		}
+		if p != nil {
+			return mprID, fmt.Sprintf("already tracked as #%d", p.ID), nil
+		}
+		return mprID, "", nil
+	}
+
+	canonical := NormalizeAddress(address)
+	for _, a := range existing {
+		if SameHouse(a.canonical, canonical) {
+			return canonical, fmt.Sprintf("already tracked as #%d", a.id), nil
+		}
+	}
+	return canonical, "", nil
+}
+
+// importRow looks up and saves one row, then applies its initial rating
+// and visit status.
+func (s *Service) importRow(ctx context.Context, row ImportRow, res ImportResult, opts ImportOptions, mu *sync.Mutex) ImportResult {
+	fail := func(err error) ImportResult {
+		res.Status = ImportFailed
+		res.Error = err.Error()
+		return res
+	}
+
+	p, err := s.lookup(ctx, res.Address, opts.FetchOptions)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Two addresses can resolve to the same listing, or to listings that
+	// look like the same house; only the first is kept.
+	mu.Lock()
+	tracked, err := s.repo.GetByMprID(p.MprID)
+	var dups []Duplicate
+	if err == nil && tracked == nil {
+		dups, err = s.repo.DuplicatesOf(p)
+	}
+	if err == nil && tracked == nil && len(dups) == 0 {
+		p, err = s.repo.Insert(p)
+	}
+	mu.Unlock()
+	if err != nil {
+		return fail(err)
+	}
+	if tracked != nil {
+		res.Status = ImportSkipped
+		res.Error = fmt.Sprintf("already tracked as #%d", tracked.ID)
+		return res
+	}
+	if len(dups) > 0 {
+		res.Status = ImportSkipped
+		res.Error = fmt.Sprintf("looks like #%d", dups[0].ID)
+		return res
+	}
+
+	res.Status = ImportAdded
+	res.Property = p
+	if row.Rating != nil {
+		if err := s.repo.UpdateRating(p.ID, opts.Rater, *row.Rating); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+		p.setRatings([]MemberRating{{Email: opts.Rater, Rating: int64(*row.Rating), UpdatedAt: time.Now()}})
+	}
+	if row.VisitStatus != "" {
+		if err := s.repo.UpdateVisitStatus(p.ID, VisitStatus(row.VisitStatus)); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+		p.VisitStatus = VisitStatus(row.VisitStatus)
+	}
+	return res
+}
+
+// throttle spaces out events so that no two start less than interval
+// apart. The first event goes immediately.
+type throttle struct {
+	mu       sync.Mutex
+	next     time.Time
+	interval time.Duration
+}
+
+func newThrottle(interval time.Duration) *throttle {
+	return &throttle{interval: interval}
+}
+
+// wait blocks until the caller's turn or until ctx is done.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(t.interval)
+	t.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
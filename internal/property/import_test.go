@@ -0,0 +1,110 @@
+package property
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestServiceImport(t *testing.T) {
+	_, repo := testDBAndRepo(t)
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	svc := NewService(repo, provider)
+
+	existing, err := repo.InsertManual(ManualInput{Address: "456 Oak Avenue, Mustang, OK"})
+	if err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	three, seven := 3, 7
+	rows := []ImportRow{
+		{Address: "123 Main St, Yukon, OK 73099", Rating: &three, VisitStatus: "want_to_visit"},
+		{Address: "456 Oak Ave, Mustang, OK 73064"},
+		{Address: "123 Main Street, Yukon OK"},
+		{Address: "  "},
+		{Address: "999 Nowhere Rd, Yukon, OK"},
+		{Address: "1 Elm St", Rating: &seven},
+		{Address: "M1234567890"},
+		{Row: 42, Address: "M1234567890"},
+	}
+
+	// One worker so the listing-level skip of the last row is deterministic.
+	results, err := svc.Import(context.Background(), rows, ImportOptions{Concurrency: 1, Interval: time.Millisecond, Rater: "a@example.com"})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(results) != len(rows) {
+		t.Fatalf("got %d results, want %d", len(results), len(rows))
+	}
+
+	want := []string{ImportAdded, ImportSkipped, ImportSkipped, ImportFailed, ImportFailed, ImportFailed, ImportSkipped, ImportSkipped}
+	for i, res := range results {
+		wantRow := i + 1
+		if rows[i].Row != 0 {
+			wantRow = rows[i].Row
+		}
+		if res.Row != wantRow {
+			t.Errorf("results[%d].Row = %d, want %d", i, res.Row, wantRow)
+		}
+		if res.Status != want[i] {
+			t.Errorf("row %d status = %q (%s), want %q", res.Row, res.Status, res.Error, want[i])
+		}
+	}
+
+	added := results[0].Property
+	if added == nil || added.MprID != "M1234567890" {
+		t.Fatalf("row 1 property = %+v", added)
+	}
+	if added.Rating == nil || *added.Rating != 3 || added.VisitStatus != VisitStatusWantToVisit {
+		t.Errorf("rating = %v, visit_status = %q, want 3 and want_to_visit", added.Rating, added.VisitStatus)
+	}
+	saved, err := repo.GetByID(added.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if r := saved.RatingBy("a@example.com"); r == nil || *r != 3 {
+		t.Errorf("stored rating = %v, want 3 from the importer", r)
+	}
+
+	for _, tt := range []struct {
+		index int
+		want  string
+	}{
+		{1, fmt.Sprintf("already tracked as #%d", existing.ID)},
+		{2, "same house as row 1"},
+		{6, fmt.Sprintf("already tracked as #%d", added.ID)},
+		{7, "same house as row 7"},
+	} {
+		if got := results[tt.index].Error; got != tt.want {
+			t.Errorf("row %d reason = %q, want %q", results[tt.index].Row, got, tt.want)
+		}
+	}
+
+	all, err := repo.List(ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("got %d properties, want 2", len(all))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	limit := newThrottle(20 * time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limit.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("three waits took %v, want at least 40ms", elapsed)
+	}
+}
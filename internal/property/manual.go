@@ -0,0 +1,133 @@
+package property
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ManualInput holds user-supplied details for a property the MLS doesn't
+// know about, such as a for-sale-by-owner or pocket listing.
+type ManualInput struct {
+	Address   string   `json:"address"`
+	Price     *int64   `json:"price,omitempty"`
+	Bedrooms  *float64 `json:"bedrooms,omitempty"`
+	Bathrooms *float64 `json:"bathrooms,omitempty"`
+	Sqft      *int64   `json:"sqft,omitempty"`
+
+	// Force adds the house even when it looks like one already tracked.
+	Force bool `json:"force,omitempty"`
+}
+
+// Validate checks that the input is complete and sensible.
+func (in ManualInput) Validate() error {
+	if strings.TrimSpace(in.Address) == "" {
+		return fmt.Errorf("address is required")
+	}
+	if in.Price != nil && *in.Price < 0 {
+		return fmt.Errorf("price must not be negative")
+	}
+	if in.Bedrooms != nil && *in.Bedrooms < 0 {
+		return fmt.Errorf("bedrooms must not be negative")
+	}
+	if in.Bathrooms != nil && *in.Bathrooms < 0 {
+		return fmt.Errorf("bathrooms must not be negative")
+	}
+	if in.Sqft != nil && *in.Sqft < 0 {
+		return fmt.Errorf("sqft must not be negative")
+	}
+	return nil
+}
+
+// InsertManual stores a manually entered property under a synthetic
+// mpr_id. It has no realtor URL and an empty raw_json until linked. Unless
+// in.Force is set, a house that looks like one already tracked is not
+// stored and a *DuplicateError is returned.
+func (r *Repository) InsertManual(in ManualInput) (*Property, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	mprID, err := newManualID()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Property{
+		Address:   strings.TrimSpace(in.Address),
+		MprID:     mprID,
+		Price:     in.Price,
+		Bedrooms:  in.Bedrooms,
+		Bathrooms: in.Bathrooms,
+		Sqft:      in.Sqft,
+		Source:    SourceManual,
+		RawJSON:   json.RawMessage(`{}`),
+	}
+	if !in.Force {
+		if err := r.checkDuplicates(p); err != nil {
+			return nil, err
+		}
+	}
+	return r.Insert(p)
+}
+
+// Link attaches a manually entered property to a real MLS listing found by
+// address, realtor.com URL, or property ID. The MLS data replaces the
+// manual values and each changed field is recorded in the history.
+func (s *Service) Link(ctx context.Context, id int64, ref string, opts FetchOptions) (*RefreshResult, error) {
+	current, err := s.repo.getListing(id)
+	if err != nil {
+		return nil, err
+	}
+	if !current.IsManual() {
+		return nil, fmt.Errorf("property %d is already linked to MLS listing %s", id, current.MprID)
+	}
+
+	result, err := s.providerFor(opts).Lookup(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("looking up property: %w", err)
+	}
+
+	existing, err := s.repo.GetByMprID(result.MprID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, trackedError(existing)
+	}
+
+	updated := *current
+	updated.MprID = result.MprID
+	updated.RealtorURL = result.RealtorURL
+	updated.RawJSON = result.RawJSON
+	updated.Source = SourceMLS
+	applyParsed(&updated, parseRawJSON(result.RawJSON))
+
+	changes := diffListing(current, &updated)
+	if err := s.repo.UpdateListing(&updated, changes); err != nil {
+		return nil, fmt.Errorf("saving link: %w", err)
+	}
+
+	saved, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes == nil {
+		changes = make([]FieldChange, 0)
+	}
+
+	return &RefreshResult{Property: saved, Changes: changes}, nil
+}
+
+// newManualID returns a random synthetic mpr_id for a manual entry.
+func newManualID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating manual ID: %w", err)
+	}
+	return manualIDPrefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,131 @@
+package property
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestInsertManual(t *testing.T) {
+	repo := testRepo(t)
+
+	price := int64(240000)
+	beds := 3.0
+	p, err := repo.InsertManual(ManualInput{Address: " 789 Elm St, Yukon, OK 73099 ", Price: &price, Bedrooms: &beds})
+	if err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	if p.Address != "789 Elm St, Yukon, OK 73099" {
+		t.Errorf("address = %q", p.Address)
+	}
+	if !p.IsManual() {
+		t.Errorf("source = %q, want %q", p.Source, SourceManual)
+	}
+	if !strings.HasPrefix(p.MprID, manualIDPrefix) {
+		t.Errorf("mpr_id = %q, want %s prefix", p.MprID, manualIDPrefix)
+	}
+	if p.RealtorURL != "" {
+		t.Errorf("realtor_url = %q, want empty", p.RealtorURL)
+	}
+	assertInt64(t, "price", p.Price, 240000)
+	assertFloat64(t, "bedrooms", p.Bedrooms, 3)
+	if p.Bathrooms != nil {
+		t.Errorf("bathrooms = %v, want nil", *p.Bathrooms)
+	}
+
+	// Each manual entry gets its own synthetic mpr_id.
+	other, err := repo.InsertManual(ManualInput{Address: "790 Elm St"})
+	if err != nil {
+		t.Fatalf("second insert manual: %v", err)
+	}
+	if other.MprID == p.MprID {
+		t.Errorf("duplicate manual mpr_id %q", p.MprID)
+	}
+}
+
+func TestInsertManualValidation(t *testing.T) {
+	repo := testRepo(t)
+	negative := int64(-1)
+
+	tests := []struct {
+		name string
+		in   ManualInput
+	}{
+		{"missing address", ManualInput{Address: "  "}},
+		{"negative price", ManualInput{Address: "1 Elm St", Price: &negative}},
+		{"negative sqft", ManualInput{Address: "1 Elm St", Sqft: &negative}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.InsertManual(tt.in); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestServiceLink(t *testing.T) {
+	_, repo := testDBAndRepo(t)
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	svc := NewService(repo, provider)
+
+	price := int64(260000)
+	manual, err := repo.InsertManual(ManualInput{Address: "123 Main St", Price: &price})
+	if err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	// Manual entries can't be refreshed until they're linked.
+	if _, err := svc.Refresh(context.Background(), manual.ID, FetchOptions{}); err == nil {
+		t.Error("expected refresh of manual property to fail")
+	}
+
+	res, err := svc.Link(context.Background(), manual.ID, "M1234567890", FetchOptions{})
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if res.Property.ID != manual.ID {
+		t.Errorf("id = %d, want %d", res.Property.ID, manual.ID)
+	}
+	if res.Property.IsManual() || res.Property.MprID != "M1234567890" {
+		t.Errorf("source = %q, mpr_id = %q after link", res.Property.Source, res.Property.MprID)
+	}
+	if res.Property.RealtorURL == "" {
+		t.Error("expected realtor_url after link")
+	}
+	assertInt64(t, "price", res.Property.Price, 250000)
+
+	var priceChange *FieldChange
+	for i := range res.Changes {
+		if res.Changes[i].Field == "price" {
+			priceChange = &res.Changes[i]
+		}
+	}
+	if priceChange == nil || *priceChange.OldValue != "260000" || *priceChange.NewValue != "250000" {
+		t.Errorf("price change = %+v", priceChange)
+	}
+
+	// A linked property refreshes normally and can't be linked again.
+	if _, err := svc.Refresh(context.Background(), manual.ID, FetchOptions{}); err != nil {
+		t.Errorf("refresh after link: %v", err)
+	}
+	if _, err := svc.Link(context.Background(), manual.ID, "M2222222222", FetchOptions{}); err == nil {
+		t.Error("expected relinking to fail")
+	}
+
+	// A listing that's already tracked can't be linked to another entry.
+	second, err := repo.InsertManual(ManualInput{Address: "123 Main St", Force: true})
+	if err != nil {
+		t.Fatalf("insert second manual: %v", err)
+	}
+	if _, err := svc.Link(context.Background(), second.ID, "M1234567890", FetchOptions{}); err == nil {
+		t.Error("expected linking a tracked listing to fail")
+	}
+}
@@ -4,7 +4,11 @@ package property
 import (
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"time"
+
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/score"
 )
 
 // VisitStatus represents where a property is in the visit workflow.
@@ -25,42 +29,109 @@ func ValidVisitStatus(s string) bool {
 	return false
 }
 
+// Source records where a property's listing data came from.
+type Source string
+
+const (
+	SourceMLS    Source = "mls"
+	SourceManual Source = "manual"
+)
+
+// State is where a property is in its lifecycle. Archived houses drop out
+// of lists but keep their history; deleted ones sit in the trash until
+// restored or purged.
+type State string
+
+const (
+	StateActive   State = "active"
+	StateArchived State = "archived"
+	StateDeleted  State = "deleted"
+
+	// StateAll is a list filter matching every state; no property has it.
+	StateAll State = "all"
+)
+
+// ValidState returns true if s names a state or StateAll.
+func ValidState(s string) bool {
+	switch State(s) {
+	case StateActive, StateArchived, StateDeleted, StateAll:
+		return true
+	}
+	return false
+}
+
+// dateLayout is the format of stored listing dates.
+const dateLayout = "2006-01-02"
+
+// manualIDPrefix marks the synthetic mpr_id given to manual entries.
+const manualIDPrefix = "manual-"
+
 // Property represents a tracked house listing.
 type Property struct {
-	ID           int64           `json:"id"`
-	Address      string          `json:"address"`
-	MprID        string          `json:"mpr_id"`
-	RealtorURL   string          `json:"realtor_url"`
-	Price        *int64          `json:"price,omitempty"`
-	Bedrooms     *float64        `json:"bedrooms,omitempty"`
-	Bathrooms    *float64        `json:"bathrooms,omitempty"`
-	Sqft         *int64          `json:"sqft,omitempty"`
-	LotSize      *float64        `json:"lot_size,omitempty"`
-	YearBuilt    *int64          `json:"year_built,omitempty"`
-	PropertyType *string         `json:"property_type,omitempty"`
-	Status       *string         `json:"status,omitempty"`
-	Rating       *int64          `json:"rating,omitempty"`
-	VisitStatus  VisitStatus     `json:"visit_status"`
-	PhotoURL     string          `json:"photo_url,omitempty"`
-	RawJSON      json.RawMessage `json:"raw_json"`
-	CreatedAt    time.Time       `json:"created_at"`
-	UpdatedAt    time.Time       `json:"updated_at"`
+	ID            int64              `json:"id"`
+	Address       string             `json:"address"`
+	MprID         string             `json:"mpr_id"`
+	RealtorURL    string             `json:"realtor_url"`
+	Price         *int64             `json:"price,omitempty"`
+	Bedrooms      *float64           `json:"bedrooms,omitempty"`
+	Bathrooms     *float64           `json:"bathrooms,omitempty"`
+	Sqft          *int64             `json:"sqft,omitempty"`
+	LotSize       *float64           `json:"lot_size,omitempty"`
+	YearBuilt     *int64             `json:"year_built,omitempty"`
+	PropertyType  *string            `json:"property_type,omitempty"`
+	Status        *string            `json:"status,omitempty"`
+	HOAFee        *int64             `json:"hoa_fee,omitempty"`         // dollars per month
+	AnnualTax     *int64             `json:"annual_tax,omitempty"`      // dollars, most recent tax year
+	ListDate      *string            `json:"list_date,omitempty"`       // YYYY-MM-DD
+	DaysOnMarket  *int64             `json:"days_on_market,omitempty"`  // derived from ListDate when read
+	LastSoldPrice *int64             `json:"last_sold_price,omitempty"` // dollars
+	LastSoldDate  *string            `json:"last_sold_date,omitempty"`  // YYYY-MM-DD
+	Latitude      *float64           `json:"latitude,omitempty"`
+	Longitude     *float64           `json:"longitude,omitempty"`
+	Distances     []place.Distance   `json:"distances,omitempty"` // to each household place, derived when read
+	Garage        *int64             `json:"garage,omitempty"`    // spaces
+	Stories       *int64             `json:"stories,omitempty"`
+	Heating       *string            `json:"heating,omitempty"`
+	Cooling       *string            `json:"cooling,omitempty"`
+	ListingAgent  *string            `json:"listing_agent,omitempty"`
+	Canonical     string             `json:"canonical_address,omitempty"` // normalized address for duplicate checks
+	Rating        *int64             `json:"rating,omitempty"`            // household average, rounded; derived when read
+	Ratings       *Ratings           `json:"ratings,omitempty"`           // each member's rating, loaded when read
+	Tags          []string           `json:"tags,omitempty"`              // by name, loaded when read
+	Score         *score.Score       `json:"score,omitempty"`             // against the household's criteria, derived when read
+	VisitStatus   VisitStatus        `json:"visit_status"`
+	Source        Source             `json:"source"`
+	State         State              `json:"state"`                 // derived from ArchivedAt and DeletedAt
+	ArchivedAt    *time.Time         `json:"archived_at,omitempty"` // set by Archive
+	DeletedAt     *time.Time         `json:"deleted_at,omitempty"`  // set by Delete; purged after the retention period
+	Overrides     map[string]*string `json:"overrides,omitempty"`   // hand-edited field → MLS value it hides
+	PhotoURL      string             `json:"photo_url,omitempty"`
+	RawJSON       json.RawMessage    `json:"raw_json"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
 }
 
 // scanProperty scans a property from a database row.
 func scanProperty(row interface{ Scan(...interface{}) error }) (*Property, error) {
 	var p Property
-	var price, sqft, yearBuilt, rating sql.NullInt64
+	var price, sqft, yearBuilt sql.NullInt64
 	var bedrooms, bathrooms, lotSize sql.NullFloat64
 	var propertyType, status sql.NullString
+	var hoaFee, annualTax, lastSoldPrice, garage, stories sql.NullInt64
+	var latitude, longitude sql.NullFloat64
+	var listDate, lastSoldDate, heating, cooling, listingAgent sql.NullString
 	var rawJSON string
+	var archivedAt, deletedAt sql.NullTime
 
-	var visitStatus string
+	var visitStatus, source string
 	err := row.Scan(
 		&p.ID, &p.Address, &p.MprID, &p.RealtorURL,
 		&price, &bedrooms, &bathrooms, &sqft, &lotSize,
-		&yearBuilt, &propertyType, &status, &rating,
-		&visitStatus, &rawJSON, &p.CreatedAt, &p.UpdatedAt,
+		&yearBuilt, &propertyType, &status,
+		&visitStatus, &source,
+		&hoaFee, &annualTax, &listDate, &lastSoldPrice, &lastSoldDate,
+		&latitude, &longitude, &garage, &stories, &heating, &cooling, &listingAgent,
+		&p.Canonical, &archivedAt, &deletedAt, &rawJSON, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -90,25 +161,162 @@ func scanProperty(row interface{ Scan(...interface{}) error }) (*Property, error
 	if status.Valid {
 		p.Status = &status.String
 	}
-	if rating.Valid {
-		p.Rating = &rating.Int64
-	}
+	p.HOAFee = nullInt64(hoaFee)
+	p.AnnualTax = nullInt64(annualTax)
+	p.ListDate = nullString(listDate)
+	p.LastSoldPrice = nullInt64(lastSoldPrice)
+	p.LastSoldDate = nullString(lastSoldDate)
+	p.Latitude = nullFloat64(latitude)
+	p.Longitude = nullFloat64(longitude)
+	p.Garage = nullInt64(garage)
+	p.Stories = nullInt64(stories)
+	p.Heating = nullString(heating)
+	p.Cooling = nullString(cooling)
+	p.ListingAgent = nullString(listingAgent)
 	p.VisitStatus = VisitStatus(visitStatus)
 	if p.VisitStatus == "" {
 		p.VisitStatus = VisitStatusNotVisited
 	}
+	p.Source = Source(source)
+	p.ArchivedAt = nullTime(archivedAt)
+	p.DeletedAt = nullTime(deletedAt)
+	switch {
+	case p.DeletedAt != nil:
+		p.State = StateDeleted
+	case p.ArchivedAt != nil:
+		p.State = StateArchived
+	default:
+		p.State = StateActive
+	}
 	p.RawJSON = json.RawMessage(rawJSON)
 	p.PhotoURL = extractPhotoURL(p.RawJSON)
 
 	return &p, nil
 }
 
-// extractPhotoURL finds the primary exterior photo from raw API JSON.
-// Prefers the first photo tagged "house_view", falls back to first photo.
-func extractPhotoURL(raw json.RawMessage) string {
+func nullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullFloat64(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+func nullString(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+func nullTime(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
+
+// daysOnMarket returns whole days from a YYYY-MM-DD list date to now, or
+// nil if the date is missing or unparseable.
+func daysOnMarket(listDate *string, now time.Time) *int64 {
+	if listDate == nil {
+		return nil
+	}
+	listed, err := time.Parse(dateLayout, *listDate)
+	if err != nil {
+		return nil
+	}
+	days := int64(now.Sub(listed).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	return &days
+}
+
+// scoreValue returns the listing value a computed criterion reads, or nil
+// when the property doesn't have it. Distances must already be measured.
+func (p *Property) scoreValue(c *score.Criterion) *float64 {
+	f := func(v *int64) *float64 {
+		if v == nil {
+			return nil
+		}
+		x := float64(*v)
+		return &x
+	}
+
+	switch c.Field {
+	case "price":
+		return f(p.Price)
+	case "sqft":
+		return f(p.Sqft)
+	case "lot_size":
+		return p.LotSize
+	case "bedrooms":
+		return p.Bedrooms
+	case "bathrooms":
+		return p.Bathrooms
+	case "year_built":
+		return f(p.YearBuilt)
+	case "hoa_fee":
+		return f(p.HOAFee)
+	case "annual_tax":
+		return f(p.AnnualTax)
+	case "days_on_market":
+		return f(p.DaysOnMarket)
+	case "garage":
+		return f(p.Garage)
+	case score.FieldDistance:
+		for _, d := range p.Distances {
+			if strings.EqualFold(d.Place, c.Place) {
+				miles := d.Miles
+				return &miles
+			}
+		}
+	}
+	return nil
+}
+
+// IsManual reports whether the property was entered by hand rather than
+// fetched from the MLS.
+func (p *Property) IsManual() bool {
+	return p.Source == SourceManual
+}
+
+// Photo is a listing photo from the MLS data. Tags are realtor.com's
+// image labels (house_view, kitchen, bedroom, ...).
+type Photo struct {
+	URL  string   `json:"url"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// HasTag reports whether the photo carries the given label.
+func (ph Photo) HasTag(label string) bool {
+	for _, t := range ph.Tags {
+		if t == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Photos returns every listing photo in the raw MLS data, in listing order.
+// Manual entries and listings without photos return nil.
+func (p *Property) Photos() []Photo {
+	return extractPhotos(p.RawJSON)
+}
+
+// extractPhotos reads the photos array from raw API JSON, skipping
+// entries without an href.
+func extractPhotos(raw json.RawMessage) []Photo {
 	var data map[string]json.RawMessage
 	if err := json.Unmarshal(raw, &data); err != nil {
-		return ""
+		return nil
 	}
 
 	// Navigate into "data" key if present
@@ -121,28 +329,50 @@ func extractPhotoURL(raw json.RawMessage) string {
 
 	photosRaw, ok := data["photos"]
 	if !ok {
-		return ""
+		return nil
 	}
 
-	var photos []struct {
+	var entries []struct {
 		Href string `json:"href"`
 		Tags []struct {
 			Label string `json:"label"`
 		} `json:"tags"`
 	}
-	if err := json.Unmarshal(photosRaw, &photos); err != nil || len(photos) == 0 {
+	if err := json.Unmarshal(photosRaw, &entries); err != nil {
+		return nil
+	}
+
+	var photos []Photo
+	for _, e := range entries {
+		if e.Href == "" {
+			continue
+		}
+		ph := Photo{URL: e.Href}
+		for _, t := range e.Tags {
+			if t.Label != "" {
+				ph.Tags = append(ph.Tags, t.Label)
+			}
+		}
+		photos = append(photos, ph)
+	}
+	return photos
+}
+
+// extractPhotoURL finds the primary exterior photo from raw API JSON.
+// Prefers the first photo tagged "house_view", falls back to first photo.
+func extractPhotoURL(raw json.RawMessage) string {
+	photos := extractPhotos(raw)
+	if len(photos) == 0 {
 		return ""
 	}
 
 	// Prefer first photo with house_view tag
-	for _, p := range photos {
-		for _, t := range p.Tags {
-			if t.Label == "house_view" && p.Href != "" {
-				return p.Href
-			}
+	for _, ph := range photos {
+		if ph.HasTag("house_view") {
+			return ph.URL
 		}
 	}
 
 	// Fallback to first photo
-	return photos[0].Href
+	return photos[0].URL
 }
@@ -3,6 +3,7 @@ package property
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestExtractPhotoURL(t *testing.T) {
@@ -61,3 +62,60 @@ func TestExtractPhotoURL(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractPhotos(t *testing.T) {
+	raw := json.RawMessage(`{"data":{"photos":[
+		{"href":"https://example.com/house.jpg","tags":[{"label":"house_view","probability":0.98},{"label":"yard"}]},
+		{"href":""},
+		{"href":"https://example.com/kitchen.jpg"}
+	]}}`)
+
+	photos := extractPhotos(raw)
+	if len(photos) != 2 {
+		t.Fatalf("got %d photos, want 2: %+v", len(photos), photos)
+	}
+	if photos[0].URL != "https://example.com/house.jpg" {
+		t.Errorf("photos[0].URL = %q", photos[0].URL)
+	}
+	if !photos[0].HasTag("house_view") || !photos[0].HasTag("yard") {
+		t.Errorf("photos[0].Tags = %v, want house_view and yard", photos[0].Tags)
+	}
+	if photos[1].URL != "https://example.com/kitchen.jpg" || len(photos[1].Tags) != 0 {
+		t.Errorf("photos[1] = %+v", photos[1])
+	}
+
+	if got := extractPhotos(json.RawMessage(`{}`)); got != nil {
+		t.Errorf("extractPhotos({}) = %v, want nil", got)
+	}
+}
+
+func TestDaysOnMarket(t *testing.T) {
+	now := time.Date(2024, 3, 11, 15, 0, 0, 0, time.UTC)
+	listed := "2024-03-01"
+	future := "2024-04-01"
+	bad := "March 1"
+
+	tests := []struct {
+		name string
+		date *string
+		want *int64
+	}{
+		{"listed", &listed, int64Ptr(10)},
+		{"future clamps to zero", &future, int64Ptr(0)},
+		{"missing", nil, nil},
+		{"unparseable", &bad, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := daysOnMarket(tt.date, now)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("daysOnMarket = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
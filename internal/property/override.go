@@ -0,0 +1,296 @@
+package property
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Edit is a set of hand corrections keyed by listing field name (see
+// EditableFields). A non-nil value overrides the MLS-derived value; nil
+// removes the override so the MLS value shows again.
+type Edit map[string]*string
+
+// UnmarshalJSON accepts numbers as well as strings, so API callers can
+// send {"price": 240000, "sqft": null}.
+func (e *Edit) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	edit := make(Edit, len(raw))
+	for field, v := range raw {
+		v = bytes.TrimSpace(v)
+		switch {
+		case bytes.Equal(v, []byte("null")):
+			edit[field] = nil
+		case len(v) > 0 && v[0] == '"':
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			edit[field] = &s
+		default:
+			var n json.Number
+			if err := json.Unmarshal(v, &n); err != nil {
+				return fmt.Errorf("%s must be a number, string, or null", field)
+			}
+			s := n.String()
+			edit[field] = &s
+		}
+	}
+	*e = edit
+	return nil
+}
+
+// Validate checks that every field is editable and every value parses.
+func (e Edit) Validate() error {
+	if len(e) == 0 {
+		return fmt.Errorf("no fields to edit")
+	}
+	for field, v := range e {
+		lf := findListingField(field)
+		if lf == nil {
+			return fmt.Errorf("unknown field %q (editable: %s)", field, strings.Join(EditableFields(), ", "))
+		}
+		if v == nil {
+			continue
+		}
+		if err := lf.set(&Property{}, strings.TrimSpace(*v)); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// EditableFields returns the names of the fields that can be overridden.
+func EditableFields() []string {
+	names := make([]string, len(listingFields))
+	for i, lf := range listingFields {
+		names[i] = lf.name
+	}
+	return names
+}
+
+// IsOverridden reports whether field holds a hand-edited value.
+func (p *Property) IsOverridden(field string) bool {
+	_, ok := p.Overrides[field]
+	return ok
+}
+
+// FieldText returns a listing field's current value as text, or "" if
+// unset or unknown.
+func (p *Property) FieldText(field string) string {
+	lf := findListingField(field)
+	if lf == nil {
+		return ""
+	}
+	if v := lf.value(p); v != nil {
+		return *v
+	}
+	return ""
+}
+
+// MLSText returns the MLS value hidden by an override, or "none" if the
+// listing has no value for the field.
+func (p *Property) MLSText(field string) string {
+	if v := p.Overrides[field]; v != nil {
+		return *v
+	}
+	return "none"
+}
+
+// ApplyEdit stores or clears per-field overrides for a property. The
+// MLS-derived columns are never touched, so later refreshes keep updating
+// them underneath the corrections.
+func (r *Repository) ApplyEdit(id int64, edit Edit) (err error) {
+	if err := edit.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	result, err := tx.Exec("UPDATE properties SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("updating property: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("property %d not found", id)
+	}
+
+	for field, v := range edit {
+		if v == nil {
+			_, err = tx.Exec("DELETE FROM property_overrides WHERE property_id = ? AND field = ?", id, field)
+		} else {
+			_, err = tx.Exec(
+				`INSERT INTO property_overrides (property_id, field, value) VALUES (?, ?, ?)
+				 ON CONFLICT(property_id, field) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`,
+				id, field, strings.TrimSpace(*v),
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("saving %s override: %w", field, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing edit: %w", err)
+	}
+
+	return nil
+}
+
+// applyOverrides layers stored overrides onto properties, recording the
+// MLS value each one hides in Property.Overrides.
+func (r *Repository) applyOverrides(props ...*Property) (err error) {
+	if len(props) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*Property, len(props))
+	ids := make([]interface{}, len(props))
+	for i, p := range props {
+		byID[p.ID] = p
+		ids[i] = p.ID
+	}
+
+	query := fmt.Sprintf(
+		"SELECT property_id, field, value FROM property_overrides WHERE property_id IN (%s) ORDER BY field",
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","),
+	)
+	rows, err := r.db.Query(query, ids...)
+	if err != nil {
+		return fmt.Errorf("loading overrides: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+		var field, value string
+		if err := rows.Scan(&id, &field, &value); err != nil {
+			return fmt.Errorf("scanning override: %w", err)
+		}
+		lf := findListingField(field)
+		if lf == nil {
+			continue
+		}
+		p := byID[id]
+		mlsValue := lf.value(p)
+		if err := lf.set(p, value); err != nil {
+			return fmt.Errorf("applying %s override to property %d: %w", field, id, err)
+		}
+		if p.Overrides == nil {
+			p.Overrides = make(map[string]*string)
+		}
+		p.Overrides[field] = mlsValue
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating overrides: %w", err)
+	}
+
+	return nil
+}
+
+// OverriddenFields returns the hand-edited field names in sorted order.
+func (p *Property) OverriddenFields() []string {
+	fields := make([]string, 0, len(p.Overrides))
+	for f := range p.Overrides {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func findListingField(name string) *listingField {
+	for i := range listingFields {
+		if listingFields[i].name == name {
+			return &listingFields[i]
+		}
+	}
+	return nil
+}
+
+// setInt64 parses a non-negative whole number into dst.
+func setInt64(dst **int64, s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("%q is not a whole number", s)
+		}
+		v = int64(f)
+	}
+	if v < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	*dst = &v
+	return nil
+}
+
+// setFloat64 parses a non-negative number into dst.
+func setFloat64(dst **float64, s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	if v < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	*dst = &v
+	return nil
+}
+
+// setDate parses a YYYY-MM-DD date into dst.
+func setDate(dst **string, s string) error {
+	if _, err := time.Parse(dateLayout, s); err != nil {
+		return fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+	}
+	*dst = &s
+	return nil
+}
+
+// setCoordinate parses a latitude or longitude within ±limit into dst.
+func setCoordinate(dst **float64, s string, limit float64) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	if v < -limit || v > limit {
+		return fmt.Errorf("must be between -%g and %g", limit, limit)
+	}
+	*dst = &v
+	return nil
+}
+
+// setString stores a non-empty string in dst.
+func setString(dst **string, s string) error {
+	if s == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	*dst = &s
+	return nil
+}
@@ -0,0 +1,150 @@
+package property
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestEditUnmarshalJSON(t *testing.T) {
+	var e Edit
+	if err := json.Unmarshal([]byte(`{"price": 240000, "bedrooms": "4", "sqft": null}`), &e); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if e["price"] == nil || *e["price"] != "240000" {
+		t.Errorf("price = %v, want 240000", e["price"])
+	}
+	if e["bedrooms"] == nil || *e["bedrooms"] != "4" {
+		t.Errorf("bedrooms = %v, want 4", e["bedrooms"])
+	}
+	if v, ok := e["sqft"]; !ok || v != nil {
+		t.Errorf("sqft = %v (present %v), want nil", v, ok)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": true}`), &e); err == nil {
+		t.Error("expected error for boolean value")
+	}
+}
+
+func TestEditValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    Edit
+		wantErr bool
+	}{
+		{"valid", Edit{"price": strPtr("240000"), "lot_size": strPtr("0.25"), "status": strPtr("pending")}, false},
+		{"clear", Edit{"sqft": nil}, false},
+		{"empty", Edit{}, true},
+		{"unknown field", Edit{"pool": strPtr("yes")}, true},
+		{"not a number", Edit{"bedrooms": strPtr("four")}, true},
+		{"fractional price", Edit{"price": strPtr("1.5")}, true},
+		{"negative", Edit{"sqft": strPtr("-10")}, true},
+		{"empty string", Edit{"status": strPtr(" ")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.edit.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyEdit(t *testing.T) {
+	repo := testRepo(t)
+	beds := 3.0
+	saved, err := repo.Insert(&Property{
+		Address:    "123 Override St",
+		MprID:      "M-OVERRIDE",
+		RealtorURL: "/detail/override",
+		Bedrooms:   &beds,
+		RawJSON:    json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if err := repo.ApplyEdit(saved.ID, Edit{"bedrooms": strPtr("4"), "sqft": strPtr("1800")}); err != nil {
+		t.Fatalf("apply edit: %v", err)
+	}
+
+	p, err := repo.GetByID(saved.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	assertFloat64(t, "bedrooms", p.Bedrooms, 4)
+	assertInt64(t, "sqft", p.Sqft, 1800)
+	if !p.IsOverridden("bedrooms") || !p.IsOverridden("sqft") || p.IsOverridden("price") {
+		t.Errorf("overrides = %v", p.Overrides)
+	}
+	if mls := p.Overrides["bedrooms"]; mls == nil || *mls != "3" {
+		t.Errorf("bedrooms MLS value = %v, want 3", mls)
+	}
+	if mls := p.Overrides["sqft"]; mls != nil {
+		t.Errorf("sqft MLS value = %v, want nil", *mls)
+	}
+
+	// Overrides show up in List too.
+	props, err := repo.List(ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	assertFloat64(t, "listed bedrooms", props[0].Bedrooms, 4)
+
+	// Clearing an override brings the MLS value back.
+	if err := repo.ApplyEdit(saved.ID, Edit{"bedrooms": nil}); err != nil {
+		t.Fatalf("clear edit: %v", err)
+	}
+	p, err = repo.GetByID(saved.ID)
+	if err != nil {
+		t.Fatalf("get after clear: %v", err)
+	}
+	assertFloat64(t, "bedrooms", p.Bedrooms, 3)
+	if got := p.OverriddenFields(); len(got) != 1 || got[0] != "sqft" {
+		t.Errorf("overridden fields = %v, want [sqft]", got)
+	}
+
+	if err := repo.ApplyEdit(9999, Edit{"sqft": strPtr("1")}); err == nil {
+		t.Error("expected error for missing property")
+	}
+}
+
+func TestRefreshKeepsOverrides(t *testing.T) {
+	_, repo := testDBAndRepo(t)
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	svc := NewService(repo, provider)
+
+	p, err := svc.Add(context.Background(), "M1234567890", AddOptions{})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := repo.ApplyEdit(p.ID, Edit{"price": strPtr("245000")}); err != nil {
+		t.Fatalf("apply edit: %v", err)
+	}
+
+	// The fixture is unchanged, so the override must not appear as a change
+	// and must survive the refresh.
+	res, err := svc.Refresh(context.Background(), p.ID, FetchOptions{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("got %d changes, want 0: %+v", len(res.Changes), res.Changes)
+	}
+	assertInt64(t, "price", res.Property.Price, 245000)
+	if mls := res.Property.Overrides["price"]; mls == nil || *mls != "250000" {
+		t.Errorf("price MLS value = %v, want 250000", mls)
+	}
+}
+
+func strPtr(s string) *string { return &s }
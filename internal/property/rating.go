@@ -0,0 +1,160 @@
+package property
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// splitSpread is how many stars apart members' ratings must be for the
+// household to count as split on a property.
+const splitSpread = 2
+
+// averageRatingSQL is a property's average rating, or NULL while nobody
+// has rated it, for use in a query on properties.
+const averageRatingSQL = "(SELECT AVG(rating) FROM property_ratings WHERE property_id = properties.id)"
+
+// MemberRating is one household member's rating (1-4) of a property.
+// Email is empty for a rating given before ratings were kept per member.
+type MemberRating struct {
+	Email     string    `json:"email"`
+	Rating    int64     `json:"rating"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Ratings are the household's ratings of a property and how they compare.
+type Ratings struct {
+	Members []MemberRating `json:"members"`
+	Average float64        `json:"average"` // to one decimal place
+	Min     int64          `json:"min"`
+	Spread  int64          `json:"spread"` // highest rating minus lowest
+}
+
+// Split reports whether members' ratings are far enough apart that the
+// household should talk it over.
+func (r *Ratings) Split() bool {
+	return r.Spread >= splitSpread
+}
+
+// RatingBy returns the rating given by the member with this email, or nil
+// if they haven't rated the property.
+func (p *Property) RatingBy(email string) *int64 {
+	if p.Ratings == nil {
+		return nil
+	}
+	for _, m := range p.Ratings.Members {
+		if strings.EqualFold(m.Email, email) {
+			rating := m.Rating
+			return &rating
+		}
+	}
+	return nil
+}
+
+// setRatings fills in Ratings from members' ratings, and Rating from
+// their average. Both are nil when nobody has rated the property.
+func (p *Property) setRatings(members []MemberRating) {
+	p.Rating, p.Ratings = nil, nil
+	if len(members) == 0 {
+		return
+	}
+
+	r := &Ratings{Members: members, Min: members[0].Rating}
+	var sum, high int64
+	for _, m := range members {
+		sum += m.Rating
+		r.Min = min(r.Min, m.Rating)
+		high = max(high, m.Rating)
+	}
+	avg := float64(sum) / float64(len(members))
+	r.Average = math.Round(avg*10) / 10
+	r.Spread = high - r.Min
+
+	rounded := int64(math.Round(avg))
+	p.Rating = &rounded
+	p.Ratings = r
+}
+
+// UpdateRating records one member's rating (1-4) of a property, replacing
+// their earlier one. An empty email rates as the shared, unattributed
+// rating carried over from before ratings were kept per member, which is
+// what every rating is when auth is disabled. Members' ratings sit
+// alongside the shared one and never replace it.
+func (r *Repository) UpdateRating(id int64, email string, rating int) (err error) {
+	if rating < 1 || rating > 4 {
+		return fmt.Errorf("rating must be 1-4, got %d", rating)
+	}
+	if _, err := r.getListing(id); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if _, err = tx.Exec(
+		`INSERT INTO property_ratings (property_id, email, rating) VALUES (?, ?, ?)
+		 ON CONFLICT (property_id, email) DO UPDATE SET rating = excluded.rating, updated_at = CURRENT_TIMESTAMP`,
+		id, email, rating,
+	); err != nil {
+		return fmt.Errorf("updating rating: %w", err)
+	}
+	if _, err = tx.Exec("UPDATE properties SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id); err != nil {
+		return fmt.Errorf("updating property: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing rating: %w", err)
+	}
+	return nil
+}
+
+// ratingsFor returns the members' ratings of the given properties, keyed
+// by property ID and ordered by email.
+func (r *Repository) ratingsFor(ids ...int64) (ratings map[int64][]MemberRating, err error) {
+	ratings = make(map[int64][]MemberRating)
+	if len(ids) == 0 {
+		return ratings, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.Query(fmt.Sprintf(
+		"SELECT property_id, email, rating, updated_at FROM property_ratings WHERE property_id IN (%s) ORDER BY email",
+		placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing ratings: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var propertyID int64
+		var m MemberRating
+		if err := rows.Scan(&propertyID, &m.Email, &m.Rating, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning rating: %w", err)
+		}
+		ratings[propertyID] = append(ratings[propertyID], m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating ratings: %w", err)
+	}
+
+	return ratings, nil
+}
@@ -0,0 +1,102 @@
+package property
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRatingsPerMember(t *testing.T) {
+	d, repo := testDBAndRepo(t)
+
+	split := insertAt(t, repo, "1 Split St", "M1")
+	agreed := insertAt(t, repo, "2 Agreed St", "M2")
+	legacy := insertAt(t, repo, "3 Legacy St", "M3")
+	unrated := insertAt(t, repo, "4 Unrated St", "M4")
+
+	for _, r := range []struct {
+		id     int64
+		email  string
+		rating int
+	}{
+		{split.ID, "a@example.com", 4},
+		{split.ID, "b@example.com", 1},
+		{split.ID, "a@example.com", 3}, // replaces a's 4
+		{agreed.ID, "a@example.com", 2},
+		{agreed.ID, "b@example.com", 3},
+	} {
+		if err := repo.UpdateRating(r.id, r.email, r.rating); err != nil {
+			t.Fatalf("rate %d as %s: %v", r.id, r.email, err)
+		}
+	}
+	if _, err := d.Exec("INSERT INTO property_ratings (property_id, email, rating) VALUES (?, '', 4)", legacy.ID); err != nil {
+		t.Fatalf("seeding unattributed rating: %v", err)
+	}
+
+	got, err := repo.GetByID(split.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	r := got.Ratings
+	if r == nil || len(r.Members) != 2 {
+		t.Fatalf("ratings = %+v, want two members", r)
+	}
+	if r.Average != 2 || r.Min != 1 || r.Spread != 2 || !r.Split() {
+		t.Errorf("average %v, min %d, spread %d, split %v; want 2, 1, 2, true", r.Average, r.Min, r.Spread, r.Split())
+	}
+	if got.Rating == nil || *got.Rating != 2 {
+		t.Errorf("rating = %v, want the average 2", got.Rating)
+	}
+	if mine := got.RatingBy("A@example.com"); mine == nil || *mine != 3 {
+		t.Errorf("a's rating = %v, want 3", mine)
+	}
+	if other := got.RatingBy("c@example.com"); other != nil {
+		t.Errorf("c's rating = %v, want none", *other)
+	}
+
+	got, err = repo.GetByID(agreed.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Ratings.Average != 2.5 || got.Ratings.Split() || *got.Rating != 3 {
+		t.Errorf("agreed: average %v, split %v, rating %d; want 2.5, false, 3", got.Ratings.Average, got.Ratings.Split(), *got.Rating)
+	}
+
+	ids := func(opts ListOptions) string {
+		t.Helper()
+		props, err := repo.List(opts)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		var ids []int64
+		for _, p := range props {
+			ids = append(ids, p.ID)
+		}
+		return fmt.Sprint(ids)
+	}
+	three := 3
+	if got, want := ids(ListOptions{}), fmt.Sprint([]int64{legacy.ID, agreed.ID, split.ID, unrated.ID}); got != want {
+		t.Errorf("list order = %s, want %s (highest average first)", got, want)
+	}
+	if got, want := ids(ListOptions{MinRating: &three}), fmt.Sprint([]int64{legacy.ID, agreed.ID}); got != want {
+		t.Errorf("household average >= 3 = %s, want %s", got, want)
+	}
+	if got, want := ids(ListOptions{MinRating: &three, RatedBy: "a@example.com"}), fmt.Sprint([]int64{split.ID}); got != want {
+		t.Errorf("a's rating >= 3 = %s, want %s", got, want)
+	}
+
+	// A member's rating sits alongside the shared one rather than
+	// replacing it.
+	if err := repo.UpdateRating(legacy.ID, "b@example.com", 2); err != nil {
+		t.Fatalf("rate legacy: %v", err)
+	}
+	got, err = repo.GetByID(legacy.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if shared := got.RatingBy(""); shared == nil || *shared != 4 {
+		t.Errorf("shared rating = %v, want the carried-over 4", shared)
+	}
+	if len(got.Ratings.Members) != 2 || got.Ratings.Average != 3 {
+		t.Errorf("members = %+v, average %v; want shared and b averaging 3", got.Ratings.Members, got.Ratings.Average)
+	}
+}
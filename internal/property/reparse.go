@@ -0,0 +1,49 @@
+package property
+
+import "fmt"
+
+// ReparseReport summarizes a re-derivation of listing columns from stored
+// raw_json.
+type ReparseReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Checked int              `json:"checked"`
+	Changed []*RefreshResult `json:"changed"`
+}
+
+// Reparse re-runs parseRawJSON over every property's stored raw_json,
+// archived and trashed ones included, and
+// rewrites the listing columns that come out different. It makes no
+// network calls and records no snapshots, since the listing itself didn't
+// change. Manual entries have no raw data and are skipped. With dryRun set
+// nothing is written and the report shows what would change.
+func (r *Repository) Reparse(dryRun bool) (*ReparseReport, error) {
+	props, err := r.listListings(ListOptions{State: StateAll})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReparseReport{DryRun: dryRun, Changed: make([]*RefreshResult, 0)}
+	for _, p := range props {
+		if p.IsManual() {
+			continue
+		}
+		report.Checked++
+
+		updated := *p
+		applyParsed(&updated, parseRawJSON(p.RawJSON))
+
+		changes := diffListing(p, &updated)
+		if len(changes) == 0 {
+			continue
+		}
+
+		if !dryRun {
+			if err := r.UpdateListing(&updated, nil); err != nil {
+				return nil, fmt.Errorf("reparsing property %d: %w", p.ID, err)
+			}
+		}
+		report.Changed = append(report.Changed, &RefreshResult{Property: &updated, Changes: changes})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package property
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReparse(t *testing.T) {
+	repo := testRepo(t)
+
+	// Stored columns that disagree with raw_json, as if the parser had
+	// missed the description block when the row was inserted.
+	stale, err := repo.Insert(&Property{
+		Address:    "1 Stale St",
+		MprID:      "M-STALE",
+		RealtorURL: "/detail/stale",
+		RawJSON:    json.RawMessage(`{"list_price": 300000, "description": {"beds": 4, "sqft": 2000}}`),
+	})
+	if err != nil {
+		t.Fatalf("insert stale: %v", err)
+	}
+	price := int64(300000)
+	if _, err := repo.Insert(&Property{
+		Address:    "2 Fresh St",
+		MprID:      "M-FRESH",
+		RealtorURL: "/detail/fresh",
+		Price:      &price,
+		RawJSON:    json.RawMessage(`{"list_price": 300000}`),
+	}); err != nil {
+		t.Fatalf("insert fresh: %v", err)
+	}
+	if _, err := repo.InsertManual(ManualInput{Address: "3 Manual St", Price: &price}); err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	report, err := repo.Reparse(true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Checked != 2 {
+		t.Errorf("checked = %d, want 2 (manual skipped)", report.Checked)
+	}
+	if len(report.Changed) != 1 || report.Changed[0].Property.ID != stale.ID {
+		t.Fatalf("changed = %+v, want only property %d", report.Changed, stale.ID)
+	}
+	if len(report.Changed[0].Changes) != 3 {
+		t.Errorf("got %d changes, want 3: %+v", len(report.Changed[0].Changes), report.Changed[0].Changes)
+	}
+
+	p, err := repo.GetByID(stale.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if p.Price != nil {
+		t.Errorf("dry run saved price %d", *p.Price)
+	}
+
+	if _, err := repo.Reparse(false); err != nil {
+		t.Fatalf("reparse: %v", err)
+	}
+	p, err = repo.GetByID(stale.ID)
+	if err != nil {
+		t.Fatalf("get after reparse: %v", err)
+	}
+	assertInt64(t, "price", p.Price, 300000)
+	assertFloat64(t, "bedrooms", p.Bedrooms, 4)
+	assertInt64(t, "sqft", p.Sqft, 2000)
+
+	snapshots, err := repo.ListSnapshots(stale.ID)
+	if err != nil {
+		t.Fatalf("list snapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("got %d snapshots, want 0", len(snapshots))
+	}
+
+	// A second pass finds nothing left to change.
+	report, err = repo.Reparse(false)
+	if err != nil {
+		t.Fatalf("second reparse: %v", err)
+	}
+	if len(report.Changed) != 0 {
+		t.Errorf("second pass changed %d, want 0", len(report.Changed))
+	}
+}
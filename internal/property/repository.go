@@ -5,6 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
 )
 
 // Repository provides CRUD operations for properties.
@@ -18,18 +23,32 @@ func NewRepository(db *sql.DB) *Repository {
 }
 
 const insertSQL = `INSERT INTO properties
-	(address, mpr_id, realtor_url, price, bedrooms, bathrooms, sqft, lot_size, year_built, property_type, status, raw_json)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	(address, mpr_id, realtor_url, price, bedrooms, bathrooms, sqft, lot_size, year_built, property_type, status, source,
+	 hoa_fee, annual_tax, list_date, last_sold_price, last_sold_date, latitude, longitude, garage, stories, heating, cooling, listing_agent,
+	 address_canonical, raw_json)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-const selectColumns = `id, address, mpr_id, realtor_url, price, bedrooms, bathrooms, sqft, lot_size, year_built, property_type, status, rating, visit_status, raw_json, created_at, updated_at`
+const selectColumns = `id, address, mpr_id, realtor_url, price, bedrooms, bathrooms, sqft, lot_size, year_built, property_type, status, visit_status, source,
+	hoa_fee, annual_tax, list_date, last_sold_price, last_sold_date, latitude, longitude, garage, stories, heating, cooling, listing_agent,
+	address_canonical, archived_at, deleted_at, raw_json, created_at, updated_at`
 
 // Insert adds a new property and returns it with its generated ID.
+// An empty Source is stored as SourceMLS. It doesn't check for duplicates;
+// callers adding a house on someone's behalf check first.
 func (r *Repository) Insert(p *Property) (*Property, error) {
+	source := p.Source
+	if source == "" {
+		source = SourceMLS
+	}
+	canonical := canonicalAddress(p)
+
 	result, err := r.db.Exec(insertSQL,
 		p.Address, p.MprID, p.RealtorURL,
 		p.Price, p.Bedrooms, p.Bathrooms, p.Sqft, p.LotSize,
-		p.YearBuilt, p.PropertyType, p.Status,
-		string(p.RawJSON),
+		p.YearBuilt, p.PropertyType, p.Status, string(source),
+		p.HOAFee, p.AnnualTax, p.ListDate, p.LastSoldPrice, p.LastSoldDate,
+		p.Latitude, p.Longitude, p.Garage, p.Stories, p.Heating, p.Cooling, p.ListingAgent,
+		canonical, string(p.RawJSON),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("inserting property: %w", err)
@@ -43,8 +62,22 @@ func (r *Repository) Insert(p *Property) (*Property, error) {
 	return r.GetByID(id)
 }
 
-// GetByID returns a property by its ID.
+// GetByID returns a property by its ID, in any state, with any hand-edited
+// overrides applied.
 func (r *Repository) GetByID(id int64) (*Property, error) {
+	p, err := r.getListing(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.present(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// getListing returns a property with its MLS-derived values only. Refresh
+// and link diff against these so overrides never show up as changes.
+func (r *Repository) getListing(id int64) (*Property, error) {
 	query := fmt.Sprintf("SELECT %s FROM properties WHERE id = ?", selectColumns)
 	row := r.db.QueryRow(query, id)
 
@@ -59,20 +92,208 @@ func (r *Repository) GetByID(id int64) (*Property, error) {
 	return p, nil
 }
 
+// GetByMprID returns the property with the given MLS ID, in any state, or
+// nil if none is tracked.
+func (r *Repository) GetByMprID(mprID string) (*Property, error) {
+	query := fmt.Sprintf("SELECT %s FROM properties WHERE mpr_id = ?", selectColumns)
+	p, err := scanProperty(r.db.QueryRow(query, mprID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying property by mpr_id: %w", err)
+	}
+	if _, err := r.present(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 // ListOptions controls filtering for List.
 type ListOptions struct {
-	MinRating   *int
-	VisitStatus VisitStatus // empty = all
+	State       State         // empty = active only
+	MinRating   *int          // household average, rounded; or RatedBy's own rating
+	RatedBy     string        // email whose rating MinRating applies to; empty = household average
+	VisitStatus VisitStatus   // empty = all
+	MaxDistance []place.Limit // every limit must hold; properties without coordinates never match
+	Tags        []string      // every tag must be on the property
+	Price       Range
+	Beds        Range
+	Baths       Range
+	Sqft        Range
+	YearBuilt   Range
+	Types       []string // property types, any of; empty = all
+	Statuses    []string // listing statuses (for_sale, pending, ...), any of; empty = all
+	Cities      []string // any of; empty = all
+	Zips        []string // any of; empty = all
+	Sort        string   // see ParseSort; empty = average rating, then newest
 }
 
-// List returns all properties, optionally filtered.
+// SortScore lists properties by their score against the household's
+// criteria, highest first; unscored properties come last.
+const SortScore = "score"
+
+// List returns active properties, or those in opts.State, optionally
+// filtered and sorted, with any hand-edited overrides applied; listing
+// fields are filtered and sorted on their overridden values. A
+// MaxDistance limit naming an unknown place returns an error wrapping
+// place.ErrNotFound, and an unknown tag one wrapping tag.ErrNotFound.
 func (r *Repository) List(opts ListOptions) ([]*Property, error) {
+	order, err := ParseSort(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+	tags := tag.NewRepository(r.db)
+	for _, name := range opts.Tags {
+		if _, err := tags.GetByName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	properties, err := r.listListings(opts)
+	if err != nil {
+		return nil, err
+	}
+	places, err := r.present(properties...)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range opts.MaxDistance {
+		if !hasPlace(places, l.Place) {
+			return nil, fmt.Errorf("%w: %q", place.ErrNotFound, l.Place)
+		}
+	}
+
+	var matched []*Property
+	for _, p := range properties {
+		if opts.matches(p) && place.Within(p.Distances, opts.MaxDistance) {
+			matched = append(matched, p)
+		}
+	}
+	sortProperties(matched, order)
+	return matched, nil
+}
+
+// present prepares properties for display: it layers hand-edited
+// overrides over the MLS values, loads ratings and tags and fills in
+// derived fields, including scores and distances to the household's
+// places, which it returns.
+func (r *Repository) present(props ...*Property) ([]*place.Place, error) {
+	if err := r.applyOverrides(props...); err != nil {
+		return nil, err
+	}
+	places, err := place.NewRepository(r.db).List()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(props))
+	for i, p := range props {
+		ids[i] = p.ID
+	}
+	tags, err := tag.NewRepository(r.db).ForProperties(ids...)
+	if err != nil {
+		return nil, err
+	}
+	scores := score.NewRepository(r.db)
+	criteria, err := scores.List()
+	if err != nil {
+		return nil, err
+	}
+	hand, err := scores.ForProperties(ids...)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := r.ratingsFor(ids...)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, p := range props {
+		p.setRatings(ratings[p.ID])
+		p.Tags = tags[p.ID]
+		p.DaysOnMarket = daysOnMarket(p.ListDate, now)
+		p.Distances = place.Measure(places, p.Latitude, p.Longitude)
+		p.Score = score.Compute(criteria, p.scoreValue, hand[p.ID])
+	}
+	return places, nil
+}
+
+func hasPlace(places []*place.Place, name string) bool {
+	for _, pl := range places {
+		if strings.EqualFold(pl.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getByIDs returns the given properties, in any state and ready for
+// display, keyed by ID. IDs that don't exist are left out.
+func (r *Repository) getByIDs(ids ...int64) (props map[int64]*Property, err error) {
+	props = make(map[int64]*Property)
+	if len(ids) == 0 {
+		return props, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.Query(fmt.Sprintf("SELECT %s FROM properties WHERE id IN (%s)", selectColumns, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("getting properties: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var list []*Property
+	for rows.Next() {
+		p, err := scanProperty(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning property: %w", err)
+		}
+		list = append(list, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating properties: %w", err)
+	}
+
+	if _, err := r.present(list...); err != nil {
+		return nil, err
+	}
+	for _, p := range list {
+		props[p.ID] = p
+	}
+	return props, nil
+}
+
+// listListings returns properties with their MLS-derived values only.
+func (r *Repository) listListings(opts ListOptions) (properties []*Property, err error) {
 	query := fmt.Sprintf("SELECT %s FROM properties", selectColumns)
 	var args []interface{}
 	var conditions []string
 
-	if opts.MinRating != nil {
-		conditions = append(conditions, "rating >= ?")
+	switch opts.State {
+	case "", StateActive:
+		conditions = append(conditions, "archived_at IS NULL", "deleted_at IS NULL")
+	case StateArchived:
+		conditions = append(conditions, "archived_at IS NOT NULL", "deleted_at IS NULL")
+	case StateDeleted:
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	case StateAll:
+	default:
+		return nil, fmt.Errorf("invalid state: %s", opts.State)
+	}
+
+	if opts.MinRating != nil && opts.RatedBy != "" {
+		conditions = append(conditions, "id IN (SELECT property_id FROM property_ratings WHERE email = ? AND rating >= ?)")
+		args = append(args, opts.RatedBy, *opts.MinRating)
+	} else if opts.MinRating != nil {
+		conditions = append(conditions, "ROUND("+averageRatingSQL+") >= ?")
 		args = append(args, *opts.MinRating)
 	}
 
@@ -81,11 +302,17 @@ func (r *Repository) List(opts ListOptions) ([]*Property, error) {
 		args = append(args, string(opts.VisitStatus))
 	}
 
+	for _, name := range opts.Tags {
+		conditions = append(conditions, `id IN (SELECT pt.property_id FROM property_tags pt
+			JOIN tags t ON t.id = pt.tag_id WHERE t.name = ? COLLATE NOCASE)`)
+		args = append(args, strings.Join(strings.Fields(name), " "))
+	}
+
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	query += " ORDER BY COALESCE(rating, 0) DESC, created_at DESC"
+	query += " ORDER BY COALESCE(" + averageRatingSQL + ", 0) DESC, created_at DESC"
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -97,7 +324,6 @@ func (r *Repository) List(opts ListOptions) ([]*Property, error) {
 		}
 	}()
 
-	var properties []*Property
 	for rows.Next() {
 		p, err := scanProperty(rows)
 		if err != nil {
@@ -113,31 +339,6 @@ func (r *Repository) List(opts ListOptions) ([]*Property, error) {
 	return properties, nil
 }
 
-// UpdateRating sets the rating (1-4) for a property.
-func (r *Repository) UpdateRating(id int64, rating int) error {
-	if rating < 1 || rating > 4 {
-		return fmt.Errorf("rating must be 1-4, got %d", rating)
-	}
-
-	result, err := r.db.Exec(
-		"UPDATE properties SET rating = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
-		rating, id,
-	)
-	if err != nil {
-		return fmt.Errorf("updating rating: %w", err)
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("checking rows affected: %w", err)
-	}
-	if rows == 0 {
-		return fmt.Errorf("property %d not found", id)
-	}
-
-	return nil
-}
-
 // UpdateVisitStatus sets the visit status for a property.
 func (r *Repository) UpdateVisitStatus(id int64, status VisitStatus) error {
 	if !ValidVisitStatus(string(status)) {
@@ -163,22 +364,69 @@ func (r *Repository) UpdateVisitStatus(id int64, status VisitStatus) error {
 	return nil
 }
 
-// Delete removes a property by ID. Comments cascade.
+// Archive hides a property from lists while keeping it and its history.
+// Archiving a property in the trash takes it out of the trash.
+func (r *Repository) Archive(id int64) error {
+	return r.setState(id,
+		"UPDATE properties SET archived_at = CURRENT_TIMESTAMP, deleted_at = NULL WHERE id = ? AND archived_at IS NULL",
+		"archiving property", "is already archived")
+}
+
+// Delete moves a property to the trash. It stays there, comments and
+// visits included, until restored or purged.
 func (r *Repository) Delete(id int64) error {
-	result, err := r.db.Exec("DELETE FROM properties WHERE id = ?", id)
+	return r.setState(id,
+		"UPDATE properties SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL",
+		"deleting property", "is already in the trash")
+}
+
+// Restore returns an archived or deleted property to the active list.
+func (r *Repository) Restore(id int64) error {
+	return r.setState(id,
+		`UPDATE properties SET archived_at = NULL, deleted_at = NULL
+		 WHERE id = ? AND (archived_at IS NOT NULL OR deleted_at IS NOT NULL)`,
+		"restoring property", "is not archived or in the trash")
+}
+
+// setState runs a state change on property id. A change that touches no
+// row fails with "property id not found" or "property id <unchanged>".
+func (r *Repository) setState(id int64, stmt, doing, unchanged string) error {
+	result, err := r.db.Exec(stmt, id)
 	if err != nil {
-		return fmt.Errorf("deleting property: %w", err)
+		return fmt.Errorf("%s: %w", doing, err)
 	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("checking rows affected: %w", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("property %d not found", id)
+	if rows > 0 {
+		return nil
 	}
 
-	return nil
+	if _, err := r.getListing(id); err != nil {
+		return err
+	}
+	return fmt.Errorf("property %d %s", id, unchanged)
+}
+
+// Purge permanently removes properties moved to the trash before cutoff.
+// Their comments, visits and history cascade. It returns how many were
+// removed.
+func (r *Repository) Purge(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(
+		"DELETE FROM properties WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+		cutoff.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purging trash: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("checking rows affected: %w", err)
+	}
+	return n, nil
 }
 
 // parseRawJSON extracts known fields from the raw API response.
@@ -208,6 +456,8 @@ func parseRawJSON(raw json.RawMessage) parsedFields {
 		}
 	}
 
+	f.Address = jsonAddress(data)
+
 	// Price lives at the top level
 	f.Price = jsonInt64(data, "list_price", "price")
 	f.Status = jsonString(data, "prop_status", "status")
@@ -248,10 +498,161 @@ func parseRawJSON(raw json.RawMessage) parsedFields {
 		f.LotSize = &acres
 	}
 
+	parseDetails(data, desc, &f)
+
 	return f
 }
 
+// parseDetails extracts the secondary listing fields: HOA, taxes, dates,
+// last sale, coordinates, garage, stories, heating/cooling and the agent.
+func parseDetails(data, desc map[string]json.RawMessage, f *parsedFields) {
+	if hoa := jsonObject(data, "hoa"); hoa != nil {
+		f.HOAFee = jsonInt64(hoa, "fee")
+	}
+	if f.HOAFee == nil {
+		f.HOAFee = jsonInt64(data, "hoa_fee")
+	}
+	f.AnnualTax = latestTax(data)
+	f.ListDate = jsonDate(data, "list_date")
+
+	f.LastSoldPrice = jsonInt64(data, "last_sold_price")
+	f.LastSoldDate = jsonDate(data, "last_sold_date")
+	if desc != nil {
+		if f.LastSoldPrice == nil {
+			f.LastSoldPrice = jsonInt64(desc, "sold_price")
+		}
+		if f.LastSoldDate == nil {
+			f.LastSoldDate = jsonDate(desc, "sold_date")
+		}
+		f.Garage = jsonInt64(desc, "garage")
+		f.Stories = jsonInt64(desc, "stories")
+		f.Heating = jsonString(desc, "heating")
+		f.Cooling = jsonString(desc, "cooling")
+	}
+
+	if loc := jsonObject(data, "location"); loc != nil {
+		if addr := jsonObject(loc, "address"); addr != nil {
+			if coord := jsonObject(addr, "coordinate"); coord != nil {
+				f.Latitude = jsonFloat64(coord, "lat")
+				f.Longitude = jsonFloat64(coord, "lon")
+			}
+		}
+	}
+
+	heating, cooling := detailFeatures(data)
+	if f.Heating == nil {
+		f.Heating = heating
+	}
+	if f.Cooling == nil {
+		f.Cooling = cooling
+	}
+
+	f.ListingAgent = listingAgent(data)
+}
+
+// latestTax returns the tax amount for the most recent year in tax_history.
+func latestTax(data map[string]json.RawMessage) *int64 {
+	raw, ok := data["tax_history"]
+	if !ok {
+		return nil
+	}
+	var history []struct {
+		Year int      `json:"year"`
+		Tax  *float64 `json:"tax"`
+	}
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return nil
+	}
+
+	var tax *int64
+	year := -1
+	for _, h := range history {
+		if h.Tax == nil || h.Year <= year {
+			continue
+		}
+		v := int64(*h.Tax)
+		tax, year = &v, h.Year
+	}
+	return tax
+}
+
+// detailFeatures pulls "Heating Features: ..." and "Cooling Features: ..."
+// lines out of the details list.
+func detailFeatures(data map[string]json.RawMessage) (heating, cooling *string) {
+	raw, ok := data["details"]
+	if !ok {
+		return nil, nil
+	}
+	var details []struct {
+		Text []string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, nil
+	}
+
+	for _, d := range details {
+		for _, line := range d.Text {
+			label, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			switch strings.TrimSpace(label) {
+			case "Heating Features":
+				if heating == nil {
+					heating = &value
+				}
+			case "Cooling Features":
+				if cooling == nil {
+					cooling = &value
+				}
+			}
+		}
+	}
+	return heating, cooling
+}
+
+// listingAgent returns the seller's agent from advertisers, falling back
+// to the first agent in source.agents.
+func listingAgent(data map[string]json.RawMessage) *string {
+	if raw, ok := data["advertisers"]; ok {
+		var advertisers []struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &advertisers); err == nil {
+			for _, a := range advertisers {
+				if a.Type == "seller" && a.Name != "" {
+					name := a.Name
+					return &name
+				}
+			}
+		}
+	}
+
+	if src := jsonObject(data, "source"); src != nil {
+		if raw, ok := src["agents"]; ok {
+			var agents []struct {
+				Name string `json:"agent_name"`
+			}
+			if err := json.Unmarshal(raw, &agents); err == nil {
+				for _, a := range agents {
+					if a.Name != "" {
+						name := a.Name
+						return &name
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 type parsedFields struct {
+	Address      *string
 	Price        *int64
 	Bedrooms     *float64
 	Bathrooms    *float64
@@ -260,6 +661,19 @@ type parsedFields struct {
 	YearBuilt    *int64
 	PropertyType *string
 	Status       *string
+
+	HOAFee        *int64
+	AnnualTax     *int64
+	ListDate      *string
+	LastSoldPrice *int64
+	LastSoldDate  *string
+	Latitude      *float64
+	Longitude     *float64
+	Garage        *int64
+	Stories       *int64
+	Heating       *string
+	Cooling       *string
+	ListingAgent  *string
 }
 
 // jsonInt64 tries multiple keys and returns the first valid int64 value.
@@ -293,6 +707,63 @@ func jsonFloat64(data map[string]json.RawMessage, keys ...string) *float64 {
 	return nil
 }
 
+// jsonAddress formats location.address as "line, city, ST 12345".
+func jsonAddress(data map[string]json.RawMessage) *string {
+	raw, ok := data["location"]
+	if !ok {
+		return nil
+	}
+	var loc struct {
+		Address struct {
+			Line       string `json:"line"`
+			City       string `json:"city"`
+			StateCode  string `json:"state_code"`
+			PostalCode string `json:"postal_code"`
+		} `json:"address"`
+	}
+	if err := json.Unmarshal(raw, &loc); err != nil || loc.Address.Line == "" {
+		return nil
+	}
+
+	a := loc.Address
+	parts := []string{a.Line}
+	if a.City != "" {
+		parts = append(parts, a.City)
+	}
+	if stateZip := strings.TrimSpace(a.StateCode + " " + a.PostalCode); stateZip != "" {
+		parts = append(parts, stateZip)
+	}
+	s := strings.Join(parts, ", ")
+	return &s
+}
+
+// jsonObject returns the object at key, or nil if missing or not an object.
+func jsonObject(data map[string]json.RawMessage, key string) map[string]json.RawMessage {
+	raw, ok := data[key]
+	if !ok {
+		return nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// jsonDate returns the YYYY-MM-DD date of the first key holding a date or
+// RFC 3339 timestamp.
+func jsonDate(data map[string]json.RawMessage, keys ...string) *string {
+	s := jsonString(data, keys...)
+	if s == nil || len(*s) < len(dateLayout) {
+		return nil
+	}
+	d := (*s)[:len(dateLayout)]
+	if _, err := time.Parse(dateLayout, d); err != nil {
+		return nil
+	}
+	return &d
+}
+
 // jsonString tries multiple keys and returns the first valid string value.
 func jsonString(data map[string]json.RawMessage, keys ...string) *string {
 	for _, key := range keys {
@@ -2,11 +2,18 @@ package property
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
 )
 
 func TestInsertAndGetByID(t *testing.T) {
@@ -152,7 +159,7 @@ func TestListFilterByRating(t *testing.T) {
 			t.Fatalf("insert %d: %v", i, err)
 		}
 		if i > 0 { // rate properties 1-3 with ratings 1-3
-			if err := repo.UpdateRating(saved.ID, i); err != nil {
+			if err := repo.UpdateRating(saved.ID, "a@example.com", i); err != nil {
 				t.Fatalf("rate %d: %v", i, err)
 			}
 		}
@@ -228,6 +235,165 @@ func TestListFilterByVisitStatus(t *testing.T) {
 	}
 }
 
+func TestListFilterByTag(t *testing.T) {
+	repo := testRepo(t)
+	tags := tag.NewRepository(repo.db)
+
+	var ids []int64
+	for i, names := range [][]string{{"needs roof", "top pick"}, {"top pick"}, nil} {
+		saved, err := repo.Insert(&Property{
+			Address:    fmt.Sprintf("%d Tag St", i),
+			MprID:      fmt.Sprintf("M-TAG-%d", i),
+			RealtorURL: fmt.Sprintf("/detail/tag-%d", i),
+			RawJSON:    json.RawMessage(`{}`),
+		})
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		if len(names) > 0 {
+			if err := tags.Add(saved.ID, names...); err != nil {
+				t.Fatalf("tag %d: %v", i, err)
+			}
+		}
+		ids = append(ids, saved.ID)
+	}
+
+	assertListed(t, repo, ListOptions{Tags: []string{"Top Pick"}}, []int64{ids[0], ids[1]})
+	assertListed(t, repo, ListOptions{Tags: []string{"top pick", "needs roof"}}, []int64{ids[0]})
+
+	got, err := repo.GetByID(ids[0])
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if fmt.Sprint(got.Tags) != "[needs roof top pick]" {
+		t.Errorf("tags = %v, want [needs roof top pick]", got.Tags)
+	}
+
+	if _, err := repo.List(ListOptions{Tags: []string{"nope"}}); !errors.Is(err, tag.ErrNotFound) {
+		t.Errorf("unknown tag error = %v, want tag.ErrNotFound", err)
+	}
+}
+
+func TestListSortByScore(t *testing.T) {
+	repo := testRepo(t)
+	scores := score.NewRepository(repo.db)
+	best, worst := 200000.0, 400000.0
+	if _, err := scores.Add(&score.Criterion{Name: "price", Weight: 2, Field: "price", Best: &best, Worst: &worst}); err != nil {
+		t.Fatalf("add criterion: %v", err)
+	}
+	if _, err := scores.Add(&score.Criterion{Name: "kitchen", Weight: 1}); err != nil {
+		t.Fatalf("add criterion: %v", err)
+	}
+
+	var ids []int64
+	for i, price := range []int64{300000, 0, 250000} {
+		p := &Property{
+			Address:    fmt.Sprintf("%d Score St", i),
+			MprID:      fmt.Sprintf("M-SCORE-%d", i),
+			RealtorURL: fmt.Sprintf("/detail/score-%d", i),
+			RawJSON:    json.RawMessage(`{}`),
+		}
+		if price > 0 {
+			p.Price = &price
+		}
+		saved, err := repo.Insert(p)
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		ids = append(ids, saved.ID)
+	}
+	// A great kitchen lifts the first house above the cheaper third.
+	if err := scores.SetScores(ids[0], map[string]int{"kitchen": 5}); err != nil {
+		t.Fatalf("set scores: %v", err)
+	}
+	if err := scores.SetScores(ids[2], map[string]int{"kitchen": 1}); err != nil {
+		t.Fatalf("set scores: %v", err)
+	}
+
+	props, err := repo.List(ListOptions{Sort: SortScore})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var got []int64
+	for _, p := range props {
+		got = append(got, p.ID)
+	}
+	if want := []int64{ids[0], ids[2], ids[1]}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("sorted by score = %v, want %v", got, want)
+	}
+	if s := props[0].Score; s == nil || s.Total == nil || *s.Total != 66.7 {
+		t.Errorf("first score = %+v, want total 66.7", s)
+	}
+	if s := props[2].Score; s == nil || s.Total != nil || len(s.Parts) != 2 {
+		t.Errorf("unpriced, unscored house = %+v, want two parts and no total", s)
+	}
+
+	if _, err := repo.List(ListOptions{Sort: "pool"}); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+}
+
+func TestListFilterByDistance(t *testing.T) {
+	repo := testRepo(t)
+	if _, err := place.NewRepository(repo.db).Add("work", 35.4676, -97.5164); err != nil {
+		t.Fatalf("add place: %v", err)
+	}
+
+	// Downtown (~1 mi from work), Yukon (~14 mi), and one with no coordinates.
+	coords := []struct{ lat, lon *float64 }{
+		{float64Ptr(35.48), float64Ptr(-97.53)},
+		{float64Ptr(35.5067), float64Ptr(-97.7625)},
+		{nil, nil},
+	}
+	for i, c := range coords {
+		if _, err := repo.Insert(&Property{
+			Address:    fmt.Sprintf("%d Commute St", i),
+			MprID:      fmt.Sprintf("M-DIST-%d", i),
+			RealtorURL: fmt.Sprintf("/detail/dist-%d", i),
+			RawJSON:    json.RawMessage(`{}`),
+			Latitude:   c.lat,
+			Longitude:  c.lon,
+		}); err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+
+	all, err := repo.List(ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all[0].Distances) != 1 || all[0].Distances[0].Place != "work" {
+		t.Errorf("distances = %+v, want one to work", all[0].Distances)
+	}
+	if all[2].Distances != nil {
+		t.Errorf("property without coordinates has distances %+v", all[2].Distances)
+	}
+
+	tests := []struct {
+		name  string
+		limit place.Limit
+		want  int
+	}{
+		{"close", place.Limit{Place: "work", Miles: 5}, 1},
+		{"farther", place.Limit{Place: "Work", Miles: 20}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props, err := repo.List(ListOptions{MaxDistance: []place.Limit{tt.limit}})
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(props) != tt.want {
+				t.Errorf("got %d, want %d", len(props), tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.List(ListOptions{MaxDistance: []place.Limit{{Place: "gym", Miles: 5}}}); !errors.Is(err, place.ErrNotFound) {
+		t.Errorf("unknown place error = %v, want place.ErrNotFound", err)
+	}
+}
+
 func TestUpdateVisitStatus(t *testing.T) {
 	repo := testRepo(t)
 
@@ -316,7 +482,7 @@ func TestUpdateRating(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := repo.UpdateRating(saved.ID, tt.rating)
+			err := repo.UpdateRating(saved.ID, "a@example.com", tt.rating)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -341,7 +507,7 @@ func TestUpdateRating(t *testing.T) {
 func TestUpdateRatingNotFound(t *testing.T) {
 	repo := testRepo(t)
 
-	err := repo.UpdateRating(9999, 3)
+	err := repo.UpdateRating(9999, "a@example.com", 3)
 	if err == nil {
 		t.Fatal("expected error for missing property")
 	}
@@ -365,9 +531,132 @@ func TestDelete(t *testing.T) {
 		t.Fatalf("delete: %v", err)
 	}
 
-	_, err = repo.GetByID(saved.ID)
-	if err == nil {
-		t.Fatal("expected error after delete")
+	got, err := repo.GetByID(saved.ID)
+	if err != nil {
+		t.Fatalf("get after delete: %v", err)
+	}
+	if got.State != StateDeleted || got.DeletedAt == nil {
+		t.Errorf("state = %q, deleted_at = %v, want deleted with a time", got.State, got.DeletedAt)
+	}
+	assertListed(t, repo, ListOptions{}, nil)
+	assertListed(t, repo, ListOptions{State: StateDeleted}, []int64{saved.ID})
+
+	if err := repo.Delete(saved.ID); err == nil || !strings.Contains(err.Error(), "already in the trash") {
+		t.Errorf("second delete error = %v, want already in the trash", err)
+	}
+
+	if err := repo.Restore(saved.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	assertListed(t, repo, ListOptions{}, []int64{saved.ID})
+	if err := repo.Restore(saved.ID); err == nil {
+		t.Error("expected error restoring an active property")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	repo := testRepo(t)
+
+	kept, err := repo.Insert(&Property{Address: "1 Kept St", MprID: "M-KEPT", RealtorURL: "/k", RawJSON: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	archived, err := repo.Insert(&Property{Address: "2 Old St", MprID: "M-OLD", RealtorURL: "/o", RawJSON: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if err := repo.Archive(archived.ID); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	assertListed(t, repo, ListOptions{}, []int64{kept.ID})
+	assertListed(t, repo, ListOptions{State: StateArchived}, []int64{archived.ID})
+	assertListed(t, repo, ListOptions{State: StateAll}, []int64{archived.ID, kept.ID})
+
+	// Deleting an archived house trashes it; archiving it again takes it
+	// back out of the trash.
+	if err := repo.Delete(archived.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	assertListed(t, repo, ListOptions{State: StateArchived}, nil)
+	if err := repo.Archive(archived.ID); err == nil {
+		t.Error("expected error archiving an archived property")
+	}
+
+	if err := repo.Restore(archived.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	got, err := repo.GetByID(archived.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.State != StateActive || got.ArchivedAt != nil || got.DeletedAt != nil {
+		t.Errorf("after restore: state = %q, archived_at = %v, deleted_at = %v", got.State, got.ArchivedAt, got.DeletedAt)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	repo := testRepo(t)
+
+	var ids []int64
+	for _, mpr := range []string{"M-OLD", "M-RECENT", "M-ACTIVE"} {
+		p, err := repo.Insert(&Property{Address: mpr, MprID: mpr, RealtorURL: "/" + mpr, RawJSON: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		ids = append(ids, p.ID)
+	}
+	old, recent, active := ids[0], ids[1], ids[2]
+
+	for _, id := range []int64{old, recent} {
+		if err := repo.Delete(id); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	}
+	if _, err := repo.db.Exec("UPDATE properties SET deleted_at = '2020-01-01 00:00:00' WHERE id = ?", old); err != nil {
+		t.Fatalf("backdating: %v", err)
+	}
+	if _, err := repo.db.Exec("INSERT INTO comments (property_id, text) VALUES (?, 'gone')", old); err != nil {
+		t.Fatalf("insert comment: %v", err)
+	}
+
+	n, err := repo.Purge(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged %d, want 1", n)
+	}
+	if _, err := repo.GetByID(old); err == nil {
+		t.Error("expected purged property to be gone")
+	}
+	var comments int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM comments WHERE property_id = ?", old).Scan(&comments); err != nil {
+		t.Fatalf("count comments: %v", err)
+	}
+	if comments != 0 {
+		t.Errorf("%d comments left on purged property, want 0", comments)
+	}
+	assertListed(t, repo, ListOptions{State: StateDeleted}, []int64{recent})
+	assertListed(t, repo, ListOptions{}, []int64{active})
+}
+
+// assertListed checks that List(opts) returns exactly the properties want,
+// in any order.
+func assertListed(t *testing.T, repo *Repository, opts ListOptions, want []int64) {
+	t.Helper()
+	props, err := repo.List(opts)
+	if err != nil {
+		t.Fatalf("list %+v: %v", opts, err)
+	}
+	var got []int64
+	for _, p := range props {
+		got = append(got, p.ID)
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("list %+v = %v, want %v", opts, got, want)
 	}
 }
 
@@ -421,6 +710,48 @@ func TestParseRawJSON(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "location address",
+			raw:  `{"data": {"location": {"address": {"line": "123 Main St", "city": "Yukon", "state_code": "OK", "postal_code": "73099"}}}}`,
+			wantFunc: func(t *testing.T, f parsedFields) {
+				assertString(t, "address", f.Address, "123 Main St, Yukon, OK 73099")
+			},
+		},
+		{
+			name: "details",
+			raw: `{"data": {
+				"list_date": "2024-03-01T18:22:11Z", "last_sold_price": 180000, "last_sold_date": "2015-06-30",
+				"hoa": {"fee": 45},
+				"tax_history": [{"year": 2022, "tax": 2100}, {"year": 2023, "tax": 2250.4}, {"year": 2021, "tax": 2000}],
+				"location": {"address": {"line": "1 A St", "coordinate": {"lat": 35.5, "lon": -97.7}}},
+				"description": {"garage": 2, "stories": 1},
+				"details": [{"category": "Heating and Cooling", "text": ["Heating Features: Central, Natural Gas", "Cooling Features: Central Air"]}],
+				"advertisers": [{"type": "buyer", "name": "Other Agent"}, {"type": "seller", "name": "Jane Agent"}]
+			}}`,
+			wantFunc: func(t *testing.T, f parsedFields) {
+				assertInt64(t, "hoa_fee", f.HOAFee, 45)
+				assertInt64(t, "annual_tax", f.AnnualTax, 2250)
+				assertString(t, "list_date", f.ListDate, "2024-03-01")
+				assertInt64(t, "last_sold_price", f.LastSoldPrice, 180000)
+				assertString(t, "last_sold_date", f.LastSoldDate, "2015-06-30")
+				assertFloat64(t, "latitude", f.Latitude, 35.5)
+				assertFloat64(t, "longitude", f.Longitude, -97.7)
+				assertInt64(t, "garage", f.Garage, 2)
+				assertInt64(t, "stories", f.Stories, 1)
+				assertString(t, "heating", f.Heating, "Central, Natural Gas")
+				assertString(t, "cooling", f.Cooling, "Central Air")
+				assertString(t, "listing_agent", f.ListingAgent, "Jane Agent")
+			},
+		},
+		{
+			name: "agent from source and sold date from description",
+			raw:  `{"source": {"agents": [{"agent_name": "Sam Source"}]}, "description": {"sold_date": "2019-01-02", "sold_price": 150000}}`,
+			wantFunc: func(t *testing.T, f parsedFields) {
+				assertString(t, "listing_agent", f.ListingAgent, "Sam Source")
+				assertString(t, "last_sold_date", f.LastSoldDate, "2019-01-02")
+				assertInt64(t, "last_sold_price", f.LastSoldPrice, 150000)
+			},
+		},
 		{
 			name: "empty json",
 			raw:  `{}`,
@@ -0,0 +1,127 @@
+package property
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SearchLimit is the most results Search returns.
+const SearchLimit = 50
+
+// ErrEmptySearch is returned for a search with no words in it.
+var ErrEmptySearch = errors.New("search needs at least one word")
+
+// Markers snippet() puts around matched words; control characters never
+// appear in addresses, comments or notes.
+const (
+	matchStart = "\x01"
+	matchEnd   = "\x02"
+)
+
+// SnippetPart is a piece of a search snippet. Match marks the words that
+// matched the query, for highlighting.
+type SnippetPart struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// SearchResult is a property matching a search, with a snippet of the
+// address, type, comment or visit note that matched best.
+type SearchResult struct {
+	Property *Property     `json:"property"`
+	Snippet  []SnippetPart `json:"snippet"`
+}
+
+// Search finds active properties whose address, type, comments or visit
+// notes contain every word of query, each also matching as a prefix
+// ("basem" finds "basement"). Results come in list order, at most
+// SearchLimit of them.
+func (r *Repository) Search(query string) (results []*SearchResult, err error) {
+	match := matchQuery(query)
+	if match == "" {
+		return nil, ErrEmptySearch
+	}
+
+	rows, err := r.db.Query(`SELECT properties.id, snippet(property_search, -1, char(1), char(2), '…', 12)
+		FROM property_search JOIN properties ON properties.id = property_search.rowid
+		WHERE property_search MATCH ? AND archived_at IS NULL AND deleted_at IS NULL
+		ORDER BY COALESCE(`+averageRatingSQL+`, 0) DESC, created_at DESC
+		LIMIT ?`, match, SearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("searching properties: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var ids []int64
+	snippets := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var snippet string
+		if err := rows.Scan(&id, &snippet); err != nil {
+			return nil, fmt.Errorf("scanning search result: %w", err)
+		}
+		ids = append(ids, id)
+		snippets[id] = snippet
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating search results: %w", err)
+	}
+
+	props := make([]*Property, len(ids))
+	for i, id := range ids {
+		if props[i], err = r.getListing(id); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.present(props...); err != nil {
+		return nil, err
+	}
+
+	results = make([]*SearchResult, len(props))
+	for i, p := range props {
+		results[i] = &SearchResult{Property: p, Snippet: splitSnippet(snippets[p.ID])}
+	}
+	return results, nil
+}
+
+// matchQuery turns free text into an FTS5 query: every word must match,
+// as a prefix. Punctuation is dropped and each word is quoted, so nothing
+// typed is read as query syntax (AND, NEAR, column filters, ...).
+func matchQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = `"` + w + `"*`
+	}
+	return strings.Join(words, " ")
+}
+
+// splitSnippet splits a snippet on the match markers.
+func splitSnippet(s string) []SnippetPart {
+	var parts []SnippetPart
+	for s != "" {
+		start := strings.Index(s, matchStart)
+		if start < 0 {
+			parts = append(parts, SnippetPart{Text: s})
+			break
+		}
+		if start > 0 {
+			parts = append(parts, SnippetPart{Text: s[:start]})
+		}
+		s = s[start+len(matchStart):]
+		end := strings.Index(s, matchEnd)
+		if end < 0 {
+			end = len(s)
+		}
+		parts = append(parts, SnippetPart{Text: s[:end], Match: true})
+		s = strings.TrimPrefix(s[end:], matchEnd)
+	}
+	return parts
+}
@@ -0,0 +1,82 @@
+package property
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	d, repo := testDBAndRepo(t)
+
+	elm := insertAt(t, repo, "12 Elm St, Edmond, OK", "S1")
+	oak := insertAt(t, repo, "9 Oak Ave, Edmond, OK", "S2")
+	gone := insertAt(t, repo, "3 Pine Ct, Edmond, OK", "S3")
+	for _, stmt := range []string{
+		fmt.Sprintf("INSERT INTO comments (property_id, text) VALUES (%d, 'The finished basement is huge, great for a gym')", elm.ID),
+		fmt.Sprintf("INSERT INTO visits (property_id, visit_date, visit_type, notes) VALUES (%d, '2024-03-01', 'showing', 'Basement smells damp')", oak.ID),
+		fmt.Sprintf("INSERT INTO comments (property_id, text) VALUES (%d, 'basement flooded')", gone.ID),
+	} {
+		if _, err := d.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := repo.Delete(gone.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.UpdateRating(oak.ID, "a@example.com", 4); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+
+	ids := func(query string) string {
+		t.Helper()
+		results, err := repo.Search(query)
+		if err != nil {
+			t.Fatalf("search %q: %v", query, err)
+		}
+		var ids []int64
+		for _, r := range results {
+			ids = append(ids, r.Property.ID)
+		}
+		return fmt.Sprint(ids)
+	}
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"basement", []int64{oak.ID, elm.ID}}, // highest rated first; trash left out
+		{"BASEM", []int64{oak.ID, elm.ID}},
+		{"finished basement", []int64{elm.ID}},
+		{"damp", []int64{oak.ID}},
+		{"oak ave", []int64{oak.ID}},
+		{`"edmond" AND NOT`, nil},
+		{"attic", nil},
+	}
+	for _, tt := range tests {
+		if got, want := ids(tt.query), fmt.Sprint(tt.want); got != want {
+			t.Errorf("search %q = %s, want %s", tt.query, got, want)
+		}
+	}
+
+	results, err := repo.Search("gym basement")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Property.Ratings != nil {
+		t.Fatalf("results = %+v, want elm, unrated", results)
+	}
+	var matched []string
+	var text string
+	for _, part := range results[0].Snippet {
+		text += part.Text
+		if part.Match {
+			matched = append(matched, part.Text)
+		}
+	}
+	if fmt.Sprint(matched) != "[basement gym]" || text != "The finished basement is huge, great for a gym" {
+		t.Errorf("snippet = %q with matches %v", text, matched)
+	}
+
+	if _, err := repo.Search(" !? "); err == nil {
+		t.Error("expected error for a query with no words")
+	}
+}
@@ -1,6 +1,7 @@
 package property
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/evcraddock/house-finder/internal/mls"
@@ -8,38 +9,78 @@ import (
 
 // Service provides property business logic.
 type Service struct {
-	repo   *Repository
-	client *mls.Client
+	repo     *Repository
+	provider mls.Provider
 }
 
-// NewService creates a property service.
-func NewService(repo *Repository, client *mls.Client) *Service {
-	return &Service{repo: repo, client: client}
+// NewService creates a property service backed by the given listing provider.
+func NewService(repo *Repository, provider mls.Provider) *Service {
+	return &Service{repo: repo, provider: provider}
 }
 
-// Add looks up a property by address, fetches its data, and stores it.
-// This is the only operation that hits external APIs.
-func (s *Service) Add(address string) (*Property, error) {
-	result, err := s.client.Lookup(address)
+// FetchOptions control how listing data is fetched from the provider.
+type FetchOptions struct {
+	// NoCache skips cached listing data and always makes a fresh call.
+	NoCache bool
+}
+
+// AddOptions control how Add fetches and stores a new property.
+type AddOptions struct {
+	FetchOptions
+
+	// Force adds the house even when it looks like one already tracked.
+	Force bool
+}
+
+// RefreshResult is the outcome of re-fetching a property from the MLS.
+type RefreshResult struct {
+	Property *Property     `json:"property"`
+	Changes  []FieldChange `json:"changes"`
+}
+
+// CanRefresh reports whether the listing provider supports Refresh.
+func (s *Service) CanRefresh() bool {
+	_, ok := s.provider.(mls.Refresher)
+	return ok
+}
+
+// Suggest returns up to n candidate listings for an address without
+// fetching listing data, so the caller can pick one before paying for Add.
+func (s *Service) Suggest(ctx context.Context, address string, n int) ([]mls.Candidate, error) {
+	suggester, ok := s.provider.(mls.Suggester)
+	if !ok {
+		return nil, fmt.Errorf("listing provider does not support suggestions")
+	}
+
+	candidates, err := suggester.Suggest(ctx, address, n)
 	if err != nil {
-		return nil, fmt.Errorf("looking up property: %w", err)
+		return nil, fmt.Errorf("suggesting addresses: %w", err)
 	}
+	return candidates, nil
+}
 
-	fields := parseRawJSON(result.RawJSON)
+// Add looks up a property by address, fetches its data, and stores it.
+// address may also be a realtor.com listing URL or property ID, in which
+// case the stored address is taken from the listing data. Unless
+// opts.Force is set, a house that looks like one already tracked is not
+// stored and a *DuplicateError is returned.
+func (s *Service) Add(ctx context.Context, address string, opts AddOptions) (*Property, error) {
+	p, err := s.lookup(ctx, address, opts.FetchOptions)
+	if err != nil {
+		return nil, err
+	}
 
-	p := &Property{
-		Address:      address,
-		MprID:        result.MprID,
-		RealtorURL:   result.RealtorURL,
-		Price:        fields.Price,
-		Bedrooms:     fields.Bedrooms,
-		Bathrooms:    fields.Bathrooms,
-		Sqft:         fields.Sqft,
-		LotSize:      fields.LotSize,
-		YearBuilt:    fields.YearBuilt,
-		PropertyType: fields.PropertyType,
-		Status:       fields.Status,
-		RawJSON:      result.RawJSON,
+	existing, err := s.repo.GetByMprID(p.MprID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, trackedError(existing)
+	}
+	if !opts.Force {
+		if err := s.repo.checkDuplicates(p); err != nil {
+			return nil, err
+		}
 	}
 
 	saved, err := s.repo.Insert(p)
@@ -49,3 +90,90 @@ func (s *Service) Add(address string) (*Property, error) {
 
 	return saved, nil
 }
+
+// trackedError explains that the listing being added is already tracked
+// as p, pointing at restore when p is archived or in the trash.
+func trackedError(p *Property) error {
+	switch p.State {
+	case StateArchived:
+		return fmt.Errorf("MLS listing %s is already tracked as property %d, which is archived; restore it instead", p.MprID, p.ID)
+	case StateDeleted:
+		return fmt.Errorf("MLS listing %s is already tracked as property %d, which is in the trash; restore it instead", p.MprID, p.ID)
+	}
+	return fmt.Errorf("MLS listing %s is already tracked as property %d", p.MprID, p.ID)
+}
+
+// lookup fetches the listing for address and builds an unsaved property.
+func (s *Service) lookup(ctx context.Context, address string, opts FetchOptions) (*Property, error) {
+	result, err := s.providerFor(opts).Lookup(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("looking up property: %w", err)
+	}
+
+	p := &Property{
+		Address:    address,
+		MprID:      result.MprID,
+		RealtorURL: result.RealtorURL,
+		RawJSON:    result.RawJSON,
+	}
+	fields := parseRawJSON(result.RawJSON)
+	applyParsed(p, fields)
+	if _, _, ok := mls.ParseListingRef(address); ok && fields.Address != nil {
+		p.Address = *fields.Address
+	}
+	return p, nil
+}
+
+// Refresh re-fetches a tracked property via its stored realtor URL and
+// records every changed listing field. Costs one RapidAPI call with the
+// default provider. Providers without refresh support return an error.
+func (s *Service) Refresh(ctx context.Context, id int64, opts FetchOptions) (*RefreshResult, error) {
+	refresher, ok := s.providerFor(opts).(mls.Refresher)
+	if !ok {
+		return nil, fmt.Errorf("listing provider does not support refresh")
+	}
+
+	current, err := s.repo.getListing(id)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsManual() {
+		return nil, fmt.Errorf("property %d was entered manually; link it to an MLS listing first", id)
+	}
+
+	result, err := refresher.Refresh(ctx, current.MprID, current.RealtorURL)
+	if err != nil {
+		return nil, fmt.Errorf("refreshing property %d: %w", id, err)
+	}
+
+	updated := *current
+	updated.RawJSON = result.RawJSON
+	applyParsed(&updated, parseRawJSON(result.RawJSON))
+
+	changes := diffListing(current, &updated)
+	if err := s.repo.UpdateListing(&updated, changes); err != nil {
+		return nil, fmt.Errorf("saving refresh: %w", err)
+	}
+
+	saved, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes == nil {
+		changes = make([]FieldChange, 0)
+	}
+
+	return &RefreshResult{Property: saved, Changes: changes}, nil
+}
+
+// providerFor returns the provider to use for a fetch, bypassing any
+// cache when NoCache is set.
+func (s *Service) providerFor(opts FetchOptions) mls.Provider {
+	if opts.NoCache {
+		if b, ok := s.provider.(mls.CacheBypasser); ok {
+			return b.Bypass()
+		}
+	}
+	return s.provider
+}
@@ -1,13 +1,17 @@
 package property
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evcraddock/house-finder/internal/db"
 	"github.com/evcraddock/house-finder/internal/mls"
@@ -31,7 +35,7 @@ func TestServiceAdd(t *testing.T) {
 
 	svc := testService(t, suggestServer.URL, hulkServer.URL, rapidServer.URL)
 
-	p, err := svc.Add("123 Test St, City, ST 00000")
+	p, err := svc.Add(context.Background(), "123 Test St, City, ST 00000", AddOptions{})
 	if err != nil {
 		t.Fatalf("add: %v", err)
 	}
@@ -53,6 +57,27 @@ func TestServiceAdd(t *testing.T) {
 	}
 }
 
+func TestServiceAddByListingURL(t *testing.T) {
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResp(t, w, `{"data": {"list_price": 199000, "location": {"address": {"line": "1 Elm St Unit 2", "city": "Town", "state_code": "OK", "postal_code": "73000"}}}}`)
+	}))
+	defer rapidServer.Close()
+
+	// No geocoder or hulk servers: a listing URL needs neither.
+	svc := testService(t, "http://127.0.0.1:0", "http://127.0.0.1:0", rapidServer.URL)
+
+	p, err := svc.Add(context.Background(), "https://www.realtor.com/realestateandhomes-detail/1-Elm-St-Unit-2_Town_OK_73000_M75364-50927", AddOptions{})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if p.Address != "1 Elm St Unit 2, Town, OK 73000" {
+		t.Errorf("address = %q, want address from listing", p.Address)
+	}
+	if p.MprID != "7536450927" {
+		t.Errorf("mpr_id = %q, want %q", p.MprID, "7536450927")
+	}
+}
+
 func TestServiceAddAPIFailure(t *testing.T) {
 	// Suggest returns no results
 	suggestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -62,7 +87,7 @@ func TestServiceAddAPIFailure(t *testing.T) {
 
 	svc := testService(t, suggestServer.URL, "", "")
 
-	_, err := svc.Add("Nonexistent Address")
+	_, err := svc.Add(context.Background(), "Nonexistent Address", AddOptions{})
 	if err == nil {
 		t.Fatal("expected error when API fails")
 	}
@@ -84,7 +109,7 @@ func TestServiceAddNothingSavedOnFailure(t *testing.T) {
 	client := testMLSClient(t, suggestServer.URL, hulkServer.URL, "")
 	svc := NewService(repo, client)
 
-	_, err := svc.Add("123 Fail St")
+	_, err := svc.Add(context.Background(), "123 Fail St", AddOptions{})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -99,6 +124,170 @@ func TestServiceAddNothingSavedOnFailure(t *testing.T) {
 	}
 }
 
+func TestServiceAddTrashedListing(t *testing.T) {
+	_, repo := testDBAndRepo(t)
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	svc := NewService(repo, provider)
+
+	p, err := svc.Add(context.Background(), "M1234567890", AddOptions{})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := repo.Delete(p.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, err = svc.Add(context.Background(), "M1234567890", AddOptions{})
+	if err == nil || !strings.Contains(err.Error(), "in the trash; restore it instead") {
+		t.Errorf("re-add error = %v, want a pointer to restore", err)
+	}
+}
+
+func TestServiceAddRefusesDuplicates(t *testing.T) {
+	_, repo := testDBAndRepo(t)
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	svc := NewService(repo, provider)
+
+	manual, err := repo.InsertManual(ManualInput{Address: "123 Main Street"})
+	if err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	_, err = svc.Add(context.Background(), "M1234567890", AddOptions{})
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.Duplicates[0].ID != manual.ID {
+		t.Fatalf("add error = %v, want a duplicate of #%d", err, manual.ID)
+	}
+	if p, err := repo.GetByMprID("M1234567890"); err != nil || p != nil {
+		t.Fatalf("listing saved despite the duplicate: %v, %v", p, err)
+	}
+
+	if _, err := svc.Add(context.Background(), "M1234567890", AddOptions{Force: true}); err != nil {
+		t.Errorf("forced add: %v", err)
+	}
+}
+
+func TestServiceRefresh(t *testing.T) {
+	rapidResponse := `{"list_price": 250000, "beds": 3, "baths": 2, "prop_status": "active"}`
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResp(t, w, rapidResponse)
+	}))
+	defer rapidServer.Close()
+
+	_, repo := testDBAndRepo(t)
+	svc := NewService(repo, testMLSClient(t, "", "", rapidServer.URL))
+
+	saved, err := repo.Insert(&Property{
+		Address:    "123 Refresh St",
+		MprID:      "M-REFRESH",
+		RealtorURL: "/detail/refresh",
+		RawJSON:    json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// First refresh fills in every parsed field.
+	res, err := svc.Refresh(context.Background(), saved.ID, FetchOptions{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if len(res.Changes) != 4 {
+		t.Errorf("got %d changes, want 4: %+v", len(res.Changes), res.Changes)
+	}
+	if res.Property.Price == nil || *res.Property.Price != 250000 {
+		t.Errorf("price = %v, want 250000", res.Property.Price)
+	}
+
+	// Price drop and status change are recorded.
+	rapidResponse = `{"list_price": 240000, "beds": 3, "baths": 2, "prop_status": "pending"}`
+	res, err = svc.Refresh(context.Background(), saved.ID, FetchOptions{})
+	if err != nil {
+		t.Fatalf("second refresh: %v", err)
+	}
+	if len(res.Changes) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(res.Changes), res.Changes)
+	}
+	if c := res.Changes[0]; c.Field != "price" || *c.OldValue != "250000" || *c.NewValue != "240000" {
+		t.Errorf("price change = %+v", c)
+	}
+
+	// Unchanged data records nothing.
+	res, err = svc.Refresh(context.Background(), saved.ID, FetchOptions{})
+	if err != nil {
+		t.Fatalf("third refresh: %v", err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("got %d changes, want 0", len(res.Changes))
+	}
+
+	snapshots, err := repo.ListSnapshots(saved.ID)
+	if err != nil {
+		t.Fatalf("list snapshots: %v", err)
+	}
+	if len(snapshots) != 6 {
+		t.Errorf("got %d snapshots, want 6", len(snapshots))
+	}
+}
+
+func TestServiceRefreshNoCache(t *testing.T) {
+	rapidResponse := `{"list_price": 250000}`
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResp(t, w, rapidResponse)
+	}))
+	defer rapidServer.Close()
+
+	d, repo := testDBAndRepo(t)
+	provider := mls.NewCachedProvider(testMLSClient(t, "", "", rapidServer.URL), mls.NewCache(d, time.Hour))
+	svc := NewService(repo, provider)
+
+	saved, err := repo.Insert(&Property{
+		Address:    "123 Cache St",
+		MprID:      "M-CACHE",
+		RealtorURL: "/detail/cache",
+		RawJSON:    json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if _, err := svc.Refresh(context.Background(), saved.ID, FetchOptions{}); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// A cached refresh sees the old price; NoCache sees the new one.
+	rapidResponse = `{"list_price": 240000}`
+	res, err := svc.Refresh(context.Background(), saved.ID, FetchOptions{})
+	if err != nil {
+		t.Fatalf("cached refresh: %v", err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("cached refresh got %d changes, want 0", len(res.Changes))
+	}
+
+	res, err = svc.Refresh(context.Background(), saved.ID, FetchOptions{NoCache: true})
+	if err != nil {
+		t.Fatalf("uncached refresh: %v", err)
+	}
+	if res.Property.Price == nil || *res.Property.Price != 240000 {
+		t.Errorf("price = %v, want 240000", res.Property.Price)
+	}
+}
+
+func TestServiceRefreshNotFound(t *testing.T) {
+	svc := testService(t, "", "", "")
+
+	if _, err := svc.Refresh(context.Background(), 9999, FetchOptions{}); err == nil {
+		t.Fatal("expected error for missing property")
+	}
+}
+
 func testService(t *testing.T, suggestURL, hulkURL, rapidAPIURL string) *Service {
 	t.Helper()
 	_, repo := testDBAndRepo(t)
@@ -128,6 +317,7 @@ func testMLSClient(t *testing.T, suggestURL, hulkURL, rapidAPIURL string) *mls.C
 		t.Fatalf("new client: %v", err)
 	}
 	mls.SetTestURLs(client, suggestURL, hulkURL, rapidAPIURL)
+	mls.SetTestBackoff(client, time.Millisecond)
 	return client
 }
 
@@ -0,0 +1,226 @@
+package property
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FieldChange describes a listing field whose value changed between fetches.
+// Values are stored as text; nil means the field was absent.
+type FieldChange struct {
+	Field    string  `json:"field"`
+	OldValue *string `json:"old_value"`
+	NewValue *string `json:"new_value"`
+}
+
+// Snapshot is a recorded field change from a past refresh.
+type Snapshot struct {
+	ID         int64     `json:"id"`
+	PropertyID int64     `json:"property_id"`
+	Field      string    `json:"field"`
+	OldValue   *string   `json:"old_value"`
+	NewValue   *string   `json:"new_value"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// listingField is an MLS-derived column tracked across refreshes. value
+// renders it as text for snapshots; set parses text back for overrides.
+type listingField struct {
+	name  string
+	value func(p *Property) *string
+	set   func(p *Property, s string) error
+}
+
+// listingFields are the MLS-derived columns tracked across refreshes.
+var listingFields = []listingField{
+	{"price", func(p *Property) *string { return int64Text(p.Price) },
+		func(p *Property, s string) error { return setInt64(&p.Price, s) }},
+	{"bedrooms", func(p *Property) *string { return float64Text(p.Bedrooms) },
+		func(p *Property, s string) error { return setFloat64(&p.Bedrooms, s) }},
+	{"bathrooms", func(p *Property) *string { return float64Text(p.Bathrooms) },
+		func(p *Property, s string) error { return setFloat64(&p.Bathrooms, s) }},
+	{"sqft", func(p *Property) *string { return int64Text(p.Sqft) },
+		func(p *Property, s string) error { return setInt64(&p.Sqft, s) }},
+	{"lot_size", func(p *Property) *string { return float64Text(p.LotSize) },
+		func(p *Property, s string) error { return setFloat64(&p.LotSize, s) }},
+	{"year_built", func(p *Property) *string { return int64Text(p.YearBuilt) },
+		func(p *Property, s string) error { return setInt64(&p.YearBuilt, s) }},
+	{"property_type", func(p *Property) *string { return p.PropertyType },
+		func(p *Property, s string) error { return setString(&p.PropertyType, s) }},
+	{"status", func(p *Property) *string { return p.Status },
+		func(p *Property, s string) error { return setString(&p.Status, s) }},
+	{"hoa_fee", func(p *Property) *string { return int64Text(p.HOAFee) },
+		func(p *Property, s string) error { return setInt64(&p.HOAFee, s) }},
+	{"annual_tax", func(p *Property) *string { return int64Text(p.AnnualTax) },
+		func(p *Property, s string) error { return setInt64(&p.AnnualTax, s) }},
+	{"list_date", func(p *Property) *string { return p.ListDate },
+		func(p *Property, s string) error { return setDate(&p.ListDate, s) }},
+	{"last_sold_price", func(p *Property) *string { return int64Text(p.LastSoldPrice) },
+		func(p *Property, s string) error { return setInt64(&p.LastSoldPrice, s) }},
+	{"last_sold_date", func(p *Property) *string { return p.LastSoldDate },
+		func(p *Property, s string) error { return setDate(&p.LastSoldDate, s) }},
+	{"latitude", func(p *Property) *string { return float64Text(p.Latitude) },
+		func(p *Property, s string) error { return setCoordinate(&p.Latitude, s, 90) }},
+	{"longitude", func(p *Property) *string { return float64Text(p.Longitude) },
+		func(p *Property, s string) error { return setCoordinate(&p.Longitude, s, 180) }},
+	{"garage", func(p *Property) *string { return int64Text(p.Garage) },
+		func(p *Property, s string) error { return setInt64(&p.Garage, s) }},
+	{"stories", func(p *Property) *string { return int64Text(p.Stories) },
+		func(p *Property, s string) error { return setInt64(&p.Stories, s) }},
+	{"heating", func(p *Property) *string { return p.Heating },
+		func(p *Property, s string) error { return setString(&p.Heating, s) }},
+	{"cooling", func(p *Property) *string { return p.Cooling },
+		func(p *Property, s string) error { return setString(&p.Cooling, s) }},
+	{"listing_agent", func(p *Property) *string { return p.ListingAgent },
+		func(p *Property, s string) error { return setString(&p.ListingAgent, s) }},
+}
+
+// applyParsed copies parsed MLS fields onto a property.
+func applyParsed(p *Property, f parsedFields) {
+	p.Price = f.Price
+	p.Bedrooms = f.Bedrooms
+	p.Bathrooms = f.Bathrooms
+	p.Sqft = f.Sqft
+	p.LotSize = f.LotSize
+	p.YearBuilt = f.YearBuilt
+	p.PropertyType = f.PropertyType
+	p.Status = f.Status
+	p.HOAFee = f.HOAFee
+	p.AnnualTax = f.AnnualTax
+	p.ListDate = f.ListDate
+	p.LastSoldPrice = f.LastSoldPrice
+	p.LastSoldDate = f.LastSoldDate
+	p.Latitude = f.Latitude
+	p.Longitude = f.Longitude
+	p.Garage = f.Garage
+	p.Stories = f.Stories
+	p.Heating = f.Heating
+	p.Cooling = f.Cooling
+	p.ListingAgent = f.ListingAgent
+}
+
+// diffListing returns the listing fields that differ between old and updated.
+func diffListing(old, updated *Property) []FieldChange {
+	var changes []FieldChange
+	for _, lf := range listingFields {
+		before, after := lf.value(old), lf.value(updated)
+		if textEqual(before, after) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: lf.name, OldValue: before, NewValue: after})
+	}
+	return changes
+}
+
+// UpdateListing writes refreshed MLS data (including the MLS identity and
+// source, which change when a manual entry is linked) for a property and
+// records each changed field in property_snapshots, all in one transaction.
+func (r *Repository) UpdateListing(p *Property, changes []FieldChange) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	result, err := tx.Exec(
+		`UPDATE properties SET mpr_id = ?, realtor_url = ?, source = ?, price = ?, bedrooms = ?, bathrooms = ?, sqft = ?,
+		 lot_size = ?, year_built = ?, property_type = ?, status = ?,
+		 hoa_fee = ?, annual_tax = ?, list_date = ?, last_sold_price = ?, last_sold_date = ?, latitude = ?, longitude = ?,
+		 garage = ?, stories = ?, heating = ?, cooling = ?, listing_agent = ?,
+		 address_canonical = ?, raw_json = ?, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = ?`,
+		p.MprID, p.RealtorURL, string(p.Source), p.Price, p.Bedrooms, p.Bathrooms, p.Sqft,
+		p.LotSize, p.YearBuilt, p.PropertyType, p.Status,
+		p.HOAFee, p.AnnualTax, p.ListDate, p.LastSoldPrice, p.LastSoldDate, p.Latitude, p.Longitude,
+		p.Garage, p.Stories, p.Heating, p.Cooling, p.ListingAgent,
+		canonicalAddress(p), string(p.RawJSON), p.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating listing: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("property %d not found", p.ID)
+	}
+
+	for _, c := range changes {
+		if _, err = tx.Exec(
+			"INSERT INTO property_snapshots (property_id, field, old_value, new_value) VALUES (?, ?, ?, ?)",
+			p.ID, c.Field, c.OldValue, c.NewValue,
+		); err != nil {
+			return fmt.Errorf("recording %s change: %w", c.Field, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing listing update: %w", err)
+	}
+
+	return nil
+}
+
+// ListSnapshots returns the recorded field changes for a property, newest first.
+func (r *Repository) ListSnapshots(propertyID int64) (_ []*Snapshot, err error) {
+	rows, err := r.db.Query(
+		`SELECT id, property_id, field, old_value, new_value, created_at
+		 FROM property_snapshots WHERE property_id = ? ORDER BY id DESC`,
+		propertyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var snapshots []*Snapshot
+	for rows.Next() {
+		var s Snapshot
+		if err := rows.Scan(&s.ID, &s.PropertyID, &s.Field, &s.OldValue, &s.NewValue, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+func int64Text(v *int64) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatInt(*v, 10)
+	return &s
+}
+
+func float64Text(v *float64) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(*v, 'f', -1, 64)
+	return &s
+}
+
+func textEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
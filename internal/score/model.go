@@ -0,0 +1,156 @@
+// Package score rates properties against the household's weighted
+// criteria. Some criteria are computed from a listing field, such as price
+// or the distance to work; the rest, such as the kitchen, are scored by
+// hand for each house.
+package score
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Hand scores run from MinManual (poor) to MaxManual (great).
+const (
+	MinManual = 1
+	MaxManual = 5
+)
+
+// maxWeight caps a criterion's weight; weights only matter relative to
+// each other, so a small range is easier to reason about.
+const maxWeight = 10
+
+// maxNameLen caps a criterion name, in characters.
+const maxNameLen = 40
+
+// FieldDistance is the computed field holding the distance, in miles, to
+// the criterion's place.
+const FieldDistance = "distance"
+
+// Fields are the listing values a computed criterion can read.
+var Fields = []string{
+	"price", "sqft", "lot_size", "bedrooms", "bathrooms", "year_built",
+	"hoa_fee", "annual_tax", "days_on_market", "garage", FieldDistance,
+}
+
+// Criterion is one thing the household weighs when comparing houses. A
+// computed criterion scores its field linearly between Worst (nothing) and
+// Best (full marks), so lower-is-better values such as price just have
+// Best below Worst. A manual criterion has no field.
+type Criterion struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Weight    int       `json:"weight"`          // 1-10, relative importance
+	Field     string    `json:"field,omitempty"` // one of Fields; empty when scored by hand
+	Place     string    `json:"place,omitempty"` // for FieldDistance
+	Best      *float64  `json:"best,omitempty"`
+	Worst     *float64  `json:"worst,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Manual reports whether the criterion is scored by hand.
+func (c *Criterion) Manual() bool {
+	return c.Field == ""
+}
+
+// Validate tidies the criterion's name and checks it is complete.
+func (c *Criterion) Validate() error {
+	c.Name = strings.Join(strings.Fields(c.Name), " ")
+	c.Field = strings.ToLower(strings.TrimSpace(c.Field))
+	c.Place = strings.TrimSpace(c.Place)
+
+	switch {
+	case c.Name == "":
+		return fmt.Errorf("criterion name is required")
+	case strings.ContainsAny(c.Name, "=/"):
+		return fmt.Errorf("criterion name can't contain '=' or '/'")
+	case len([]rune(c.Name)) > maxNameLen:
+		return fmt.Errorf("criterion name must be at most %d characters", maxNameLen)
+	case c.Weight < 1 || c.Weight > maxWeight:
+		return fmt.Errorf("weight must be 1-%d, got %d", maxWeight, c.Weight)
+	}
+
+	if c.Manual() {
+		if c.Place != "" || c.Best != nil || c.Worst != nil {
+			return fmt.Errorf("a criterion scored by hand takes no place, best or worst value")
+		}
+		return nil
+	}
+
+	switch {
+	case !slices.Contains(Fields, c.Field):
+		return fmt.Errorf("unknown field %q (want one of %s)", c.Field, strings.Join(Fields, ", "))
+	case c.Field == FieldDistance && c.Place == "":
+		return fmt.Errorf("a distance criterion needs a place")
+	case c.Field != FieldDistance && c.Place != "":
+		return fmt.Errorf("only a distance criterion takes a place")
+	case c.Best == nil || c.Worst == nil:
+		return fmt.Errorf("a computed criterion needs best and worst values")
+	case *c.Best == *c.Worst:
+		return fmt.Errorf("best and worst values must differ")
+	}
+	return nil
+}
+
+// Part is one criterion's contribution to a property's score.
+type Part struct {
+	Criterion string   `json:"criterion"`
+	Weight    int      `json:"weight"`
+	Manual    bool     `json:"manual,omitempty"`
+	Value     *float64 `json:"value,omitempty"` // listing value or hand score
+	Score     *float64 `json:"score,omitempty"` // 0 to 1; unset while the value is unknown
+}
+
+// Score is a property's weighted total and its breakdown by criterion.
+type Score struct {
+	Total *float64 `json:"total,omitempty"` // 0-100; unset until some criterion has a value
+	Parts []Part   `json:"parts"`
+}
+
+// Compute scores a property against criteria. value returns a computed
+// criterion's listing value, or nil when the listing lacks it; hand holds
+// the property's hand scores by criterion ID. The total is the weighted
+// average of the parts that have a value, so a missing field neither helps
+// nor hurts. Compute returns nil when there are no criteria.
+func Compute(criteria []*Criterion, value func(*Criterion) *float64, hand map[int64]int) *Score {
+	if len(criteria) == 0 {
+		return nil
+	}
+
+	s := &Score{Parts: make([]Part, 0, len(criteria))}
+	var sum, weights float64
+	for _, c := range criteria {
+		part := Part{Criterion: c.Name, Weight: c.Weight, Manual: c.Manual()}
+		if c.Manual() {
+			if v, ok := hand[c.ID]; ok {
+				f := float64(v)
+				part.Value = &f
+				part.Score = ratio(f, MinManual, MaxManual)
+			}
+		} else if v := value(c); v != nil {
+			part.Value = v
+			part.Score = ratio(*v, *c.Worst, *c.Best)
+		}
+
+		if part.Score != nil {
+			sum += float64(c.Weight) * *part.Score
+			weights += float64(c.Weight)
+		}
+		s.Parts = append(s.Parts, part)
+	}
+
+	if weights > 0 {
+		total := math.Round(sum/weights*1000) / 10
+		s.Total = &total
+	}
+	return s
+}
+
+// ratio places v on the line from worst (0) to best (1), clamped.
+func ratio(v, worst, best float64) *float64 {
+	r := (v - worst) / (best - worst)
+	r = math.Max(0, math.Min(1, r))
+	return &r
+}
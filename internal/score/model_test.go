@@ -0,0 +1,78 @@
+package score
+
+import (
+	"testing"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       Criterion
+		wantErr bool
+	}{
+		{"manual", Criterion{Name: " kitchen ", Weight: 3}, false},
+		{"computed", Criterion{Name: "price", Weight: 5, Field: "Price", Best: ptr(250000), Worst: ptr(400000)}, false},
+		{"distance", Criterion{Name: "commute", Weight: 4, Field: FieldDistance, Place: "work", Best: ptr(5), Worst: ptr(30)}, false},
+		{"no name", Criterion{Weight: 3}, true},
+		{"name with equals", Criterion{Name: "a=b", Weight: 3}, true},
+		{"zero weight", Criterion{Name: "kitchen"}, true},
+		{"heavy weight", Criterion{Name: "kitchen", Weight: maxWeight + 1}, true},
+		{"manual with best", Criterion{Name: "kitchen", Weight: 3, Best: ptr(5)}, true},
+		{"unknown field", Criterion{Name: "pool", Weight: 3, Field: "pool", Best: ptr(1), Worst: ptr(0)}, true},
+		{"computed without range", Criterion{Name: "sqft", Weight: 3, Field: "sqft", Best: ptr(2500)}, true},
+		{"best equals worst", Criterion{Name: "sqft", Weight: 3, Field: "sqft", Best: ptr(2000), Worst: ptr(2000)}, true},
+		{"distance without place", Criterion{Name: "commute", Weight: 3, Field: FieldDistance, Best: ptr(5), Worst: ptr(30)}, true},
+		{"place on other field", Criterion{Name: "sqft", Weight: 3, Field: "sqft", Place: "work", Best: ptr(2500), Worst: ptr(1200)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	price := &Criterion{ID: 1, Name: "price", Weight: 2, Field: "price", Best: ptr(200000), Worst: ptr(400000)}
+	sqft := &Criterion{ID: 2, Name: "sqft", Weight: 1, Field: "sqft", Best: ptr(2500), Worst: ptr(1500)}
+	kitchen := &Criterion{ID: 3, Name: "kitchen", Weight: 1}
+	criteria := []*Criterion{price, sqft, kitchen}
+
+	values := map[string]*float64{"price": ptr(300000), "sqft": ptr(3000)}
+	value := func(c *Criterion) *float64 { return values[c.Field] }
+
+	// price 0.5 x2, sqft clamped to 1 x1, kitchen (5-1)/4 = 1 x1: 3/4.
+	s := Compute(criteria, value, map[int64]int{3: 5})
+	if s.Total == nil || *s.Total != 75 {
+		t.Fatalf("total = %v, want 75", s.Total)
+	}
+	if len(s.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(s.Parts))
+	}
+	if p := s.Parts[1]; p.Score == nil || *p.Score != 1 {
+		t.Errorf("sqft above best scored %v, want 1", p.Score)
+	}
+	if !s.Parts[2].Manual {
+		t.Error("kitchen part not marked manual")
+	}
+
+	// Without a hand score the kitchen is left out: price 0.5 x2, sqft 1 x1.
+	s = Compute(criteria, value, nil)
+	if s.Total == nil || *s.Total != 66.7 {
+		t.Errorf("total without kitchen = %v, want 66.7", s.Total)
+	}
+	if s.Parts[2].Score != nil {
+		t.Errorf("unscored kitchen has score %v", *s.Parts[2].Score)
+	}
+
+	if s := Compute(criteria, func(*Criterion) *float64 { return nil }, nil); s.Total != nil {
+		t.Errorf("total with nothing known = %v, want unset", *s.Total)
+	}
+	if s := Compute(nil, value, nil); s != nil {
+		t.Errorf("Compute with no criteria = %+v, want nil", s)
+	}
+}
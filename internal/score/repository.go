@@ -0,0 +1,263 @@
+package score
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/place"
+)
+
+// ErrNotFound is returned when a named criterion doesn't exist.
+var ErrNotFound = errors.New("criterion not found")
+
+// Repository provides CRUD operations for criteria and the hand scores
+// given to properties. Criterion names are unique, ignoring case.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a score repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const criterionColumns = "id, name, weight, field, place, best, worst, created_at"
+
+// Add saves a new criterion. A distance criterion's place must exist; an
+// unknown one returns an error wrapping place.ErrNotFound.
+func (r *Repository) Add(c *Criterion) (*Criterion, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.GetByName(c.Name); err == nil {
+		return nil, fmt.Errorf("criterion %q already exists", c.Name)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if c.Field == FieldDistance {
+		pl, err := place.NewRepository(r.db).GetByName(c.Place)
+		if err != nil {
+			return nil, err
+		}
+		c.Place = pl.Name
+	}
+
+	_, err := r.db.Exec(
+		"INSERT INTO score_criteria (name, weight, field, place, best, worst) VALUES (?, ?, ?, ?, ?, ?)",
+		c.Name, c.Weight, c.Field, c.Place, c.Best, c.Worst,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting criterion: %w", err)
+	}
+	return r.GetByName(c.Name)
+}
+
+// GetByName returns the criterion with the given name, ignoring case.
+func (r *Repository) GetByName(name string) (*Criterion, error) {
+	c, err := scanCriterion(r.db.QueryRow(
+		"SELECT "+criterionColumns+" FROM score_criteria WHERE name = ? COLLATE NOCASE",
+		strings.Join(strings.Fields(name), " "),
+	))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting criterion: %w", err)
+	}
+	return c, nil
+}
+
+// List returns every criterion, heaviest first, then in the order added.
+func (r *Repository) List() (criteria []*Criterion, err error) {
+	rows, err := r.db.Query("SELECT " + criterionColumns + " FROM score_criteria ORDER BY weight DESC, id")
+	if err != nil {
+		return nil, fmt.Errorf("listing criteria: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		c, err := scanCriterion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning criterion: %w", err)
+		}
+		criteria = append(criteria, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating criteria: %w", err)
+	}
+
+	return criteria, nil
+}
+
+// Update holds the criterion settings to change; nil fields are kept.
+type Update struct {
+	Weight *int     `json:"weight"`
+	Best   *float64 `json:"best"`
+	Worst  *float64 `json:"worst"`
+}
+
+// Update changes a criterion's weight or, for a computed one, its best
+// and worst values.
+func (r *Repository) Update(name string, u Update) (*Criterion, error) {
+	c, err := r.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if u.Weight != nil {
+		c.Weight = *u.Weight
+	}
+	if u.Best != nil {
+		c.Best = u.Best
+	}
+	if u.Worst != nil {
+		c.Worst = u.Worst
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.Exec("UPDATE score_criteria SET weight = ?, best = ?, worst = ? WHERE id = ?",
+		c.Weight, c.Best, c.Worst, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("updating criterion: %w", err)
+	}
+	return c, nil
+}
+
+// Delete removes a criterion and every hand score given for it.
+func (r *Repository) Delete(name string) error {
+	result, err := r.db.Exec("DELETE FROM score_criteria WHERE name = ? COLLATE NOCASE",
+		strings.Join(strings.Fields(name), " "))
+	if err != nil {
+		return fmt.Errorf("deleting criterion: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	return nil
+}
+
+// SetScores records hand scores for a property, keyed by criterion name.
+// A score of 0 clears one. Every name must be a manual criterion; nothing
+// is saved unless all of them are.
+func (r *Repository) SetScores(propertyID int64, scores map[string]int) (err error) {
+	criteria := make(map[string]*Criterion, len(scores))
+	for name, value := range scores {
+		c, err := r.GetByName(name)
+		if err != nil {
+			return err
+		}
+		if !c.Manual() {
+			return fmt.Errorf("criterion %q is computed from %s and can't be scored by hand", c.Name, c.Field)
+		}
+		if value != 0 && (value < MinManual || value > MaxManual) {
+			return fmt.Errorf("score for %q must be %d-%d, got %d", c.Name, MinManual, MaxManual, value)
+		}
+		criteria[name] = c
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	for name, value := range scores {
+		c := criteria[name]
+		if value == 0 {
+			_, err = tx.Exec("DELETE FROM property_scores WHERE property_id = ? AND criterion_id = ?", propertyID, c.ID)
+		} else {
+			_, err = tx.Exec(
+				`INSERT INTO property_scores (property_id, criterion_id, score) VALUES (?, ?, ?)
+				 ON CONFLICT (property_id, criterion_id) DO UPDATE SET score = excluded.score, updated_at = CURRENT_TIMESTAMP`,
+				propertyID, c.ID, value)
+		}
+		if err != nil {
+			return fmt.Errorf("scoring property %d: %w", propertyID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing scores: %w", err)
+	}
+	return nil
+}
+
+// ForProperties returns the hand scores of the given properties, keyed by
+// property ID and then criterion ID.
+func (r *Repository) ForProperties(ids ...int64) (scores map[int64]map[int64]int, err error) {
+	scores = make(map[int64]map[int64]int)
+	if len(ids) == 0 {
+		return scores, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.Query(fmt.Sprintf(
+		"SELECT property_id, criterion_id, score FROM property_scores WHERE property_id IN (%s)",
+		placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing property scores: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var propertyID, criterionID int64
+		var value int
+		if err := rows.Scan(&propertyID, &criterionID, &value); err != nil {
+			return nil, fmt.Errorf("scanning property score: %w", err)
+		}
+		if scores[propertyID] == nil {
+			scores[propertyID] = make(map[int64]int)
+		}
+		scores[propertyID][criterionID] = value
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating property scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+func scanCriterion(row interface{ Scan(...interface{}) error }) (*Criterion, error) {
+	var c Criterion
+	var best, worst sql.NullFloat64
+	if err := row.Scan(&c.ID, &c.Name, &c.Weight, &c.Field, &c.Place, &best, &worst, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	if best.Valid {
+		c.Best = &best.Float64
+	}
+	if worst.Valid {
+		c.Worst = &worst.Float64
+	}
+	return &c, nil
+}
@@ -0,0 +1,130 @@
+package score
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/place"
+)
+
+func testRepo(t *testing.T) (*sql.DB, *Repository) {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return d, NewRepository(d)
+}
+
+func TestAddUpdateDelete(t *testing.T) {
+	d, repo := testRepo(t)
+
+	if _, err := repo.Add(&Criterion{Name: "kitchen", Weight: 2}); err != nil {
+		t.Fatalf("add kitchen: %v", err)
+	}
+	if _, err := repo.Add(&Criterion{Name: "Kitchen", Weight: 3}); err == nil {
+		t.Error("expected error for duplicate name")
+	}
+	_, err := repo.Add(&Criterion{Name: "commute", Weight: 4, Field: FieldDistance, Place: "work", Best: ptr(5), Worst: ptr(30)})
+	if !errors.Is(err, place.ErrNotFound) {
+		t.Errorf("distance to unknown place error = %v, want place.ErrNotFound", err)
+	}
+	if _, err := place.NewRepository(d).Add("Work", 35.4676, -97.5164); err != nil {
+		t.Fatalf("add place: %v", err)
+	}
+	c, err := repo.Add(&Criterion{Name: "commute", Weight: 4, Field: FieldDistance, Place: "work", Best: ptr(5), Worst: ptr(30)})
+	if err != nil {
+		t.Fatalf("add commute: %v", err)
+	}
+	if c.Place != "Work" || *c.Best != 5 {
+		t.Errorf("commute = %+v, want place Work and best 5", c)
+	}
+
+	weight := 5
+	if c, err = repo.Update("KITCHEN", Update{Weight: &weight}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if c.Weight != 5 {
+		t.Errorf("weight = %d, want 5", c.Weight)
+	}
+	if _, err := repo.Update("kitchen", Update{Best: ptr(5)}); err == nil {
+		t.Error("expected error setting best on a manual criterion")
+	}
+
+	criteria, err := repo.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(criteria) != 2 || criteria[0].Name != "kitchen" {
+		t.Errorf("list = %+v, want kitchen (weight 5) first", criteria)
+	}
+
+	if err := repo.Delete("kitchen"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Delete("kitchen"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetScores(t *testing.T) {
+	d, repo := testRepo(t)
+	res, err := d.Exec("INSERT INTO properties (address, mpr_id, realtor_url, raw_json) VALUES ('1 Main St', 'M1', '', '{}')")
+	if err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("last insert id: %v", err)
+	}
+
+	kitchen, err := repo.Add(&Criterion{Name: "kitchen", Weight: 2})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	yard, err := repo.Add(&Criterion{Name: "yard", Weight: 1})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := repo.Add(&Criterion{Name: "price", Weight: 3, Field: "price", Best: ptr(200000), Worst: ptr(400000)}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if err := repo.SetScores(id, map[string]int{"kitchen": 4, "yard": 2}); err != nil {
+		t.Fatalf("set scores: %v", err)
+	}
+	if err := repo.SetScores(id, map[string]int{"kitchen": 5, "yard": 0}); err != nil {
+		t.Fatalf("update scores: %v", err)
+	}
+	for _, bad := range []map[string]int{{"kitchen": 6}, {"price": 3}, {"nope": 3}} {
+		if err := repo.SetScores(id, bad); err == nil {
+			t.Errorf("SetScores(%v): expected error", bad)
+		}
+	}
+
+	scores, err := repo.ForProperties(id, 9999)
+	if err != nil {
+		t.Fatalf("for properties: %v", err)
+	}
+	if got := scores[id]; len(got) != 1 || got[kitchen.ID] != 5 {
+		t.Errorf("scores = %v, want only kitchen %d = 5", got, kitchen.ID)
+	}
+	if _, ok := scores[id][yard.ID]; ok {
+		t.Error("cleared yard score is still set")
+	}
+
+	if err := repo.Delete("kitchen"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if scores, err = repo.ForProperties(id); err != nil || len(scores[id]) != 0 {
+		t.Errorf("after deleting the criterion, scores = %v (err %v), want none", scores[id], err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package tag provides free-form labels on properties, such as "needs
+// roof" or "top pick", shared by the household.
+package tag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxNameLen caps a tag name, in characters, so chips stay readable.
+const maxNameLen = 40
+
+// Tag is a label and how many properties carry it.
+type Tag struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Clean trims a tag name and collapses runs of spaces, then checks it.
+// Names can't contain ',' or '/', which separate tags in filters and URLs.
+func Clean(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("tag name is required")
+	}
+	if strings.ContainsAny(name, ",/") {
+		return "", fmt.Errorf("tag name can't contain ',' or '/'")
+	}
+	if len([]rune(name)) > maxNameLen {
+		return "", fmt.Errorf("tag name must be at most %d characters", maxNameLen)
+	}
+	return name, nil
+}
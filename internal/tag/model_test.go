@@ -0,0 +1,33 @@
+package tag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"  needs   roof ", "needs roof", false},
+		{"Top Pick", "Top Pick", false},
+		{"", "", true},
+		{"   ", "", true},
+		{"a,b", "", true},
+		{"yes/no", "", true},
+		{strings.Repeat("x", maxNameLen), strings.Repeat("x", maxNameLen), false},
+		{strings.Repeat("x", maxNameLen+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := Clean(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Clean(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package tag
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned when a named tag doesn't exist.
+var ErrNotFound = errors.New("tag not found")
+
+// Repository provides CRUD operations for tags and the properties they
+// label. Names are unique, ignoring case; the first spelling is kept.
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a tag repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create adds a tag that no property carries yet.
+func (r *Repository) Create(name string) (*Tag, error) {
+	name, err := Clean(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.GetByName(name); err == nil {
+		return nil, fmt.Errorf("tag %q already exists", name)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec("INSERT INTO tags (name) VALUES (?)", name); err != nil {
+		return nil, fmt.Errorf("inserting tag: %w", err)
+	}
+	return r.GetByName(name)
+}
+
+// GetByName returns the tag with the given name, ignoring case.
+func (r *Repository) GetByName(name string) (*Tag, error) {
+	var t Tag
+	err := r.db.QueryRow(
+		`SELECT t.id, t.name, COUNT(pt.property_id), t.created_at
+		 FROM tags t LEFT JOIN property_tags pt ON pt.tag_id = t.id
+		 WHERE t.name = ? COLLATE NOCASE GROUP BY t.id`,
+		strings.Join(strings.Fields(name), " "),
+	).Scan(&t.ID, &t.Name, &t.Count, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting tag: %w", err)
+	}
+	return &t, nil
+}
+
+// List returns every tag with its property count, by name.
+func (r *Repository) List() (_ []*Tag, err error) {
+	rows, err := r.db.Query(
+		`SELECT t.id, t.name, COUNT(pt.property_id), t.created_at
+		 FROM tags t LEFT JOIN property_tags pt ON pt.tag_id = t.id
+		 GROUP BY t.id ORDER BY t.name COLLATE NOCASE`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	var tags []*Tag
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Count, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning tag: %w", err)
+		}
+		tags = append(tags, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Rename changes a tag's name on every property that carries it. Only
+// the case may change if the new name matches another tag's.
+func (r *Repository) Rename(name, newName string) (*Tag, error) {
+	newName, err := Clean(newName)
+	if err != nil {
+		return nil, err
+	}
+	t, err := r.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if other, err := r.GetByName(newName); err == nil && other.ID != t.ID {
+		return nil, fmt.Errorf("tag %q already exists", other.Name)
+	} else if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec("UPDATE tags SET name = ? WHERE id = ?", newName, t.ID); err != nil {
+		return nil, fmt.Errorf("renaming tag: %w", err)
+	}
+	return r.GetByName(newName)
+}
+
+// Delete removes a tag from every property and forgets it.
+func (r *Repository) Delete(name string) error {
+	result, err := r.db.Exec("DELETE FROM tags WHERE name = ? COLLATE NOCASE", strings.Join(strings.Fields(name), " "))
+	if err != nil {
+		return fmt.Errorf("deleting tag: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	return nil
+}
+
+// Add puts tags on a property, creating any that don't exist yet. Tags
+// the property already carries are left alone.
+func (r *Repository) Add(propertyID int64, names ...string) (err error) {
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		name, err := Clean(name)
+		if err != nil {
+			return err
+		}
+		cleaned = append(cleaned, name)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (also failed to rollback: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	for _, name := range cleaned {
+		if _, err = tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", name); err != nil {
+			return fmt.Errorf("creating tag: %w", err)
+		}
+		if _, err = tx.Exec(
+			`INSERT OR IGNORE INTO property_tags (property_id, tag_id)
+			 SELECT ?, id FROM tags WHERE name = ? COLLATE NOCASE`,
+			propertyID, name,
+		); err != nil {
+			return fmt.Errorf("tagging property %d: %w", propertyID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing tags: %w", err)
+	}
+	return nil
+}
+
+// Remove takes a tag off a property. The tag itself is kept for reuse.
+func (r *Repository) Remove(propertyID int64, name string) error {
+	result, err := r.db.Exec(
+		`DELETE FROM property_tags WHERE property_id = ?
+		 AND tag_id = (SELECT id FROM tags WHERE name = ? COLLATE NOCASE)`,
+		propertyID, strings.Join(strings.Fields(name), " "),
+	)
+	if err != nil {
+		return fmt.Errorf("untagging property %d: %w", propertyID, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: property %d has no tag %q", ErrNotFound, propertyID, name)
+	}
+
+	return nil
+}
+
+// ForProperties returns the tag names on each of the given properties,
+// by name. Properties without tags are absent from the map.
+func (r *Repository) ForProperties(ids ...int64) (tags map[int64][]string, err error) {
+	tags = make(map[int64][]string)
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.Query(fmt.Sprintf(
+		`SELECT pt.property_id, t.name FROM property_tags pt JOIN tags t ON t.id = pt.tag_id
+		 WHERE pt.property_id IN (%s) ORDER BY t.name COLLATE NOCASE`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing property tags: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("closing rows: %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("scanning property tag: %w", err)
+		}
+		tags[id] = append(tags[id], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating property tags: %w", err)
+	}
+
+	return tags, nil
+}
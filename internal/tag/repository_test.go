@@ -0,0 +1,150 @@
+package tag
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/db"
+)
+
+func testRepo(t *testing.T) (*sql.DB, *Repository) {
+	t.Helper()
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("close db: %v", err)
+		}
+	})
+	return d, NewRepository(d)
+}
+
+// insertProperty adds a bare property row and returns its ID.
+func insertProperty(t *testing.T, d *sql.DB, mprID string) int64 {
+	t.Helper()
+	res, err := d.Exec(
+		"INSERT INTO properties (address, mpr_id, realtor_url, raw_json) VALUES (?, ?, '', '{}')",
+		mprID+" Main St", mprID,
+	)
+	if err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("last insert id: %v", err)
+	}
+	return id
+}
+
+func TestAddRemoveForProperties(t *testing.T) {
+	d, repo := testRepo(t)
+	a := insertProperty(t, d, "M1")
+	b := insertProperty(t, d, "M2")
+
+	if err := repo.Add(a, "needs roof", " Top  Pick "); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	// Case-insensitive: reuses the existing tags and doesn't double up.
+	if err := repo.Add(a, "TOP PICK"); err != nil {
+		t.Fatalf("add again: %v", err)
+	}
+	if err := repo.Add(b, "top pick"); err != nil {
+		t.Fatalf("add to b: %v", err)
+	}
+	if err := repo.Add(b, "ok", "bad/tag"); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+
+	got, err := repo.ForProperties(a, b)
+	if err != nil {
+		t.Fatalf("for properties: %v", err)
+	}
+	want := map[int64][]string{a: {"needs roof", "Top Pick"}, b: {"Top Pick"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+
+	tags, err := repo.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "needs roof" || tags[0].Count != 1 || tags[1].Count != 2 {
+		t.Errorf("list = %+v %+v", tags[0], tags[1])
+	}
+
+	if err := repo.Remove(a, "top pick"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := repo.Remove(a, "top pick"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second remove error = %v, want ErrNotFound", err)
+	}
+	top, err := repo.GetByName("top pick")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if top.Count != 1 {
+		t.Errorf("top pick count = %d, want 1", top.Count)
+	}
+
+	// Deleting the property drops its tags but keeps the tag itself.
+	if _, err := d.Exec("DELETE FROM properties WHERE id = ?", b); err != nil {
+		t.Fatalf("delete property: %v", err)
+	}
+	if top, err = repo.GetByName("top pick"); err != nil || top.Count != 0 {
+		t.Errorf("after property delete: %+v, %v", top, err)
+	}
+}
+
+func TestCreateRenameDelete(t *testing.T) {
+	d, repo := testRepo(t)
+	id := insertProperty(t, d, "M1")
+
+	if _, err := repo.Create("near school"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := repo.Create("Near School"); err == nil {
+		t.Error("expected error creating a duplicate tag")
+	}
+	if err := repo.Add(id, "near school"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := repo.Create("big yard"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	renamed, err := repo.Rename("near school", "walk to school")
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if renamed.Name != "walk to school" || renamed.Count != 1 {
+		t.Errorf("renamed = %+v", renamed)
+	}
+	if _, err := repo.Rename("walk to school", "Big Yard"); err == nil {
+		t.Error("expected error renaming onto another tag")
+	}
+	if _, err := repo.Rename("walk to school", "Walk To School"); err != nil {
+		t.Errorf("changing case only: %v", err)
+	}
+	if _, err := repo.Rename("nope", "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("rename missing error = %v, want ErrNotFound", err)
+	}
+
+	if err := repo.Delete("walk to school"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	got, err := repo.ForProperties(id)
+	if err != nil {
+		t.Fatalf("for properties: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("tags after delete = %v, want none", got)
+	}
+	if err := repo.Delete("walk to school"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete error = %v, want ErrNotFound", err)
+	}
+}
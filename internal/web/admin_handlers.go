@@ -0,0 +1,99 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+)
+
+// requireAdmin rejects API requests from anyone but the admin. When auth
+// is disabled (no admin email configured) every request is allowed.
+func (s *Server) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.authCfg.AdminEmail == "" {
+		return true
+	}
+	if !s.users.IsAdmin(auth.UserEmailFromContext(r)) {
+		apiError(w, "admin access required", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// handleAPIReparse handles POST /api/admin/reparse. It re-derives listing
+// columns from stored raw_json; {"dry_run": true} reports without saving.
+func (s *Server) handleAPIReparse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	// The body is optional; an empty body reparses for real.
+	var req struct {
+		DryRun bool `json:"dry_run"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.propRepo.Reparse(req.DryRun)
+	if err != nil {
+		apiError(w, fmt.Sprintf("reparsing properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("properties reparsed", "checked", report.Checked, "changed", len(report.Changed), "dry_run", report.DryRun, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, report, http.StatusOK)
+}
+
+// handleAPIUsage handles /api/admin/usage. GET reports listing API calls
+// by day, month and user against the monthly budget; PUT
+// {"monthly_budget": n} sets the budget of paid calls (0 removes it).
+func (s *Server) handleAPIUsage(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req struct {
+			MonthlyBudget *int `json:"monthly_budget"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apiError(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if req.MonthlyBudget == nil {
+			apiError(w, "monthly_budget is required", http.StatusBadRequest)
+			return
+		}
+		if *req.MonthlyBudget < 0 {
+			apiError(w, "monthly_budget must be 0 or more", http.StatusBadRequest)
+			return
+		}
+		user := auth.UserEmailFromContext(r)
+		if err := s.mlsUsage.SetBudget(*req.MonthlyBudget, user); err != nil {
+			apiError(w, fmt.Sprintf("setting budget: %v", err), http.StatusInternalServerError)
+			return
+		}
+		slog.Info("rapidapi budget set", "monthly_calls", *req.MonthlyBudget, "user", user)
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := s.mlsUsage.Report()
+	if err != nil {
+		apiError(w, fmt.Sprintf("reporting usage: %v", err), http.StatusInternalServerError)
+		return
+	}
+	apiJSON(w, report, http.StatusOK)
+}
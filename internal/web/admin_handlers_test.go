@@ -0,0 +1,149 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPIReparse(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+
+	if _, err := property.NewRepository(d).Insert(&property.Property{
+		Address:    "1 Stale St",
+		MprID:      "M-STALE",
+		RealtorURL: "https://realtor.com/stale",
+		RawJSON:    json.RawMessage(`{"list_price": 300000}`),
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	w := apiRequest(t, srv, "POST", "/api/admin/reparse", token, map[string]bool{"dry_run": true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var report property.ReparseReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !report.DryRun || report.Checked != 1 || len(report.Changed) != 1 {
+		t.Errorf("report = %+v, want dry run with 1 of 1 changed", report)
+	}
+
+	// No body means a real run.
+	w = apiRequest(t, srv, "POST", "/api/admin/reparse", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	report = property.ReparseReport{}
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.DryRun || len(report.Changed) != 1 {
+		t.Errorf("report = %+v, want 1 changed", report)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/admin/reparse", token, nil)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAPIReparseRequiresAdmin(t *testing.T) {
+	srv, _, _ := testAPIServerWithDB(t)
+
+	userKey, _, err := srv.apiKeys.Create("user", "user@example.com")
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	w := apiRequest(t, srv, "POST", "/api/admin/reparse", userKey, nil)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestAPIUsageBudget(t *testing.T) {
+	suggest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"autocomplete": [{"mpr_id": "M1"}]}`) //nolint:errcheck // test server
+	}))
+	defer suggest.Close()
+	hulk := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"home": {"href": "/detail/M1"}}}`) //nolint:errcheck // test server
+	}))
+	defer hulk.Close()
+	rapid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"list_price": 250000}`) //nolint:errcheck // test server
+	}))
+	defer rapid.Close()
+
+	mlsClient, err := mls.NewClient("test-key")
+	if err != nil {
+		t.Fatalf("new mls client: %v", err)
+	}
+	mls.SetTestURLs(mlsClient, suggest.URL, hulk.URL, rapid.URL)
+	srv, d, token := testAPIServerWithProvider(t, mlsClient)
+	mlsClient.SetUsage(mls.NewUsage(d))
+
+	w := apiRequest(t, srv, "PUT", "/api/admin/usage", token, map[string]int{"monthly_budget": -1})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("negative budget status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = apiRequest(t, srv, "PUT", "/api/admin/usage", token, map[string]int{"monthly_budget": 1})
+	if w.Code != http.StatusOK {
+		t.Fatalf("set budget status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = apiRequest(t, srv, "POST", "/api/properties", token, map[string]string{"address": "1 Main St"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("first add status = %d: %s", w.Code, w.Body.String())
+	}
+
+	// The budget is spent: the next paid call is refused, not made.
+	w = apiRequest(t, srv, "POST", "/api/properties", token, map[string]string{"address": "2 Main St"})
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("second add status = %d, want %d: %s", w.Code, http.StatusTooManyRequests, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "budget") {
+		t.Errorf("error = %s, want it to mention the budget", w.Body.String())
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/admin/usage", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("usage status = %d: %s", w.Code, w.Body.String())
+	}
+	var report mls.UsageReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.MonthlyBudget != 1 || report.PaidThisMonth != 1 || report.Month != time.Now().UTC().Format("2006-01") {
+		t.Errorf("report = %+v, want 1 of 1 paid calls this month", report)
+	}
+	// Two free lookups for each add, and one paid call.
+	if len(report.Users) != 1 || report.Users[0].User != "admin@example.com" || report.Users[0].Calls != 5 {
+		t.Errorf("users = %+v, want 5 calls by admin@example.com", report.Users)
+	}
+}
+
+func TestAPIUsageRequiresAdmin(t *testing.T) {
+	srv, _, _ := testAPIServerWithDB(t)
+
+	userKey, _, err := srv.apiKeys.Create("user", "user@example.com")
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	for _, method := range []string{"GET", "PUT"} {
+		w := apiRequest(t, srv, method, "/api/admin/usage", userKey, map[string]int{"monthly_budget": 0})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s status = %d, want %d", method, w.Code, http.StatusForbidden)
+		}
+	}
+}
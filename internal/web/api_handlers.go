@@ -1,15 +1,23 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/tag"
 	"github.com/evcraddock/house-finder/internal/visit"
 )
 
@@ -23,6 +31,43 @@ func apiError(w http.ResponseWriter, msg string, code int) {
 	}
 }
 
+// apiLookupError writes a failed listing lookup with a status that says
+// why: 404 when the listing doesn't exist, 429 when the listing API's
+// quota is spent (with Retry-After if it gave one), 502 when it is down
+// and 504 when it was too slow.
+func apiLookupError(w http.ResponseWriter, action string, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, mls.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, mls.ErrBudgetExceeded):
+		code = http.StatusTooManyRequests
+	case errors.Is(err, mls.ErrQuotaExceeded):
+		code = http.StatusTooManyRequests
+		var se *mls.StatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(se.RetryAfter.Seconds()))))
+		}
+	case errors.Is(err, mls.ErrUnavailable):
+		code = http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		code = http.StatusGatewayTimeout
+	}
+	apiError(w, fmt.Sprintf("%s: %v", action, err), code)
+}
+
+// logLookupError logs a failed lookup, quietly when it was only the
+// caller hanging up or an address with no listing.
+func logLookupError(msg string, err error, args ...any) {
+	args = append(args, "err", err)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, mls.ErrNotFound):
+		slog.Info(msg, args...)
+	default:
+		slog.Error(msg, args...)
+	}
+}
+
 // apiJSON writes a JSON response with the given status code.
 func apiJSON(w http.ResponseWriter, data interface{}, code int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -50,6 +95,28 @@ func (s *Server) handleAPIProperties(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// /api/properties/batch
+	if path == "batch" {
+		if r.Method != http.MethodPost {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiImportProperties(w, r)
+		return
+	}
+
+	// /api/properties/{id}/tags[/{name}], matched first since a tag name
+	// may end like another route
+	if idStr, rest, ok := strings.Cut(path, "/tags"); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		s.apiPropertyTags(w, r, id, strings.TrimPrefix(rest, "/"))
+		return
+	}
+
 	// /api/properties/{id}/comments
 	if strings.HasSuffix(path, "/comments") {
 		idStr := strings.TrimSuffix(path, "/comments")
@@ -120,7 +187,144 @@ func (s *Server) handleAPIProperties(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// /api/properties/{id} — show or remove
+	// /api/properties/{id}/refresh
+	if strings.HasSuffix(path, "/refresh") {
+		idStr := strings.TrimSuffix(path, "/refresh")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodPost {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiRefreshProperty(w, r, id)
+		return
+	}
+
+	// /api/properties/{id}/link
+	if strings.HasSuffix(path, "/link") {
+		idStr := strings.TrimSuffix(path, "/link")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodPost {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiLinkProperty(w, r, id)
+		return
+	}
+
+	// /api/properties/{id}/photos
+	if strings.HasSuffix(path, "/photos") {
+		idStr := strings.TrimSuffix(path, "/photos")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodGet {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiListPhotos(w, id)
+		return
+	}
+
+	// /api/properties/{id}/merge
+	if strings.HasSuffix(path, "/merge") {
+		idStr := strings.TrimSuffix(path, "/merge")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodPost {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiMergeProperty(w, r, id)
+		return
+	}
+
+	// /api/properties/{id}/cost
+	if strings.HasSuffix(path, "/cost") {
+		idStr := strings.TrimSuffix(path, "/cost")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodGet {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiPropertyCost(w, r, id)
+		return
+	}
+
+	// /api/properties/{id}/score
+	if strings.HasSuffix(path, "/score") {
+		idStr := strings.TrimSuffix(path, "/score")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiPropertyScore(w, id)
+		case http.MethodPut:
+			s.apiSetPropertyScores(w, r, id)
+		default:
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// /api/properties/{id}/archive and /restore
+	for _, action := range []string{"archive", "restore"} {
+		if !strings.HasSuffix(path, "/"+action) {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(path, "/"+action), 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodPost {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if action == "archive" {
+			s.apiArchiveProperty(w, r, id)
+		} else {
+			s.apiRestoreProperty(w, r, id)
+		}
+		return
+	}
+
+	// /api/properties/{id}/history
+	if strings.HasSuffix(path, "/history") {
+		idStr := strings.TrimSuffix(path, "/history")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property ID", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodGet {
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.apiListHistory(w, id)
+		return
+	}
+
+	// /api/properties/{id} — show, edit, or remove
 	id, err := strconv.ParseInt(path, 10, 64)
 	if err != nil {
 		apiError(w, "invalid property ID", http.StatusBadRequest)
@@ -129,6 +333,8 @@ func (s *Server) handleAPIProperties(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		s.apiGetProperty(w, id)
+	case http.MethodPatch:
+		s.apiEditProperty(w, r, id)
 	case http.MethodDelete:
 		s.apiDeleteProperty(w, r, id)
 	default:
@@ -136,9 +342,17 @@ func (s *Server) handleAPIProperties(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// apiListProperties returns all properties as JSON.
+// apiListProperties returns properties as JSON: active ones unless the
+// state parameter asks for archived, deleted (the trash) or all.
 func (s *Server) apiListProperties(w http.ResponseWriter, r *http.Request) {
 	opts := property.ListOptions{}
+	if st := r.URL.Query().Get("state"); st != "" {
+		if !property.ValidState(st) {
+			apiError(w, "state must be active, archived, deleted, or all", http.StatusBadRequest)
+			return
+		}
+		opts.State = property.State(st)
+	}
 	if minStr := r.URL.Query().Get("min_rating"); minStr != "" {
 		min, err := strconv.Atoi(minStr)
 		if err != nil || min < 1 || min > 4 {
@@ -147,6 +361,14 @@ func (s *Server) apiListProperties(w http.ResponseWriter, r *http.Request) {
 		}
 		opts.MinRating = &min
 	}
+	switch r.URL.Query().Get("rating_by") {
+	case "", "household":
+	case "me":
+		opts.RatedBy = auth.UserEmailFromContext(r)
+	default:
+		apiError(w, "rating_by must be me or household", http.StatusBadRequest)
+		return
+	}
 	if vs := r.URL.Query().Get("visit_status"); vs != "" {
 		if !property.ValidVisitStatus(vs) {
 			apiError(w, "visit_status must be not_visited, want_to_visit, or visited", http.StatusBadRequest)
@@ -154,43 +376,176 @@ func (s *Server) apiListProperties(w http.ResponseWriter, r *http.Request) {
 		}
 		opts.VisitStatus = property.VisitStatus(vs)
 	}
+	for _, md := range r.URL.Query()["max_distance"] {
+		limit, err := place.ParseLimit(md)
+		if err != nil {
+			apiError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.MaxDistance = append(opts.MaxDistance, limit)
+	}
+	opts.Tags = r.URL.Query()["tag"]
+	if err := parseListFilters(r.URL.Query(), &opts); err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var maxMonthly int64
+	if mm := r.URL.Query().Get("max_monthly"); mm != "" {
+		v, err := strconv.ParseInt(mm, 10, 64)
+		if err != nil || v < 1 {
+			apiError(w, "max_monthly must be a positive whole number of dollars", http.StatusBadRequest)
+			return
+		}
+		maxMonthly = v
+	}
 
 	props, err := s.propRepo.List(opts)
+	if errors.Is(err, place.ErrNotFound) || errors.Is(err, tag.ErrNotFound) {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		apiError(w, fmt.Sprintf("listing properties: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	if maxMonthly > 0 {
+		profile, err := s.financeRepo.Get(auth.UserEmailFromContext(r))
+		if err != nil {
+			apiError(w, fmt.Sprintf("loading financing profile: %v", err), http.StatusInternalServerError)
+			return
+		}
+		props = withinMonthly(props, profile, maxMonthly)
+	}
+
 	apiJSON(w, props, http.StatusOK)
 }
 
-// apiAddProperty adds a property by address (does API lookup).
-func (s *Server) apiAddProperty(w http.ResponseWriter, r *http.Request) {
-	if s.propService == nil {
-		apiError(w, "property add not available (RAPIDAPI_KEY not configured)", http.StatusServiceUnavailable)
-		return
+// listRanges maps the min_/max_ query parameters to the range each sets.
+var listRanges = []struct {
+	param string
+	rng   func(*property.ListOptions) *property.Range
+}{
+	{"price", func(o *property.ListOptions) *property.Range { return &o.Price }},
+	{"beds", func(o *property.ListOptions) *property.Range { return &o.Beds }},
+	{"baths", func(o *property.ListOptions) *property.Range { return &o.Baths }},
+	{"sqft", func(o *property.ListOptions) *property.Range { return &o.Sqft }},
+	{"year", func(o *property.ListOptions) *property.Range { return &o.YearBuilt }},
+}
+
+// parseListFilters reads the listing-field filters and the sort shared by
+// the API and the web list: min_/max_ price, beds, baths, sqft and year;
+// repeatable property_type, listing_status, city and zip; and sort. Blank
+// values are ignored, as empty form fields send them.
+func parseListFilters(q url.Values, opts *property.ListOptions) error {
+	for _, lr := range listRanges {
+		for _, end := range []string{"min", "max"} {
+			name := end + "_" + lr.param
+			raw := strings.TrimSpace(q.Get(name))
+			if raw == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v < 0 || math.IsInf(v, 0) {
+				return fmt.Errorf("%s must be a number, 0 or more", name)
+			}
+			if end == "min" {
+				lr.rng(opts).Min = &v
+			} else {
+				lr.rng(opts).Max = &v
+			}
+		}
+	}
+
+	values := func(name string) []string {
+		var out []string
+		for _, v := range q[name] {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+		return out
 	}
+	opts.Types = values("property_type")
+	opts.Statuses = values("listing_status")
+	opts.Cities = values("city")
+	opts.Zips = values("zip")
 
+	opts.Sort = strings.TrimSpace(q.Get("sort"))
+	if _, err := property.ParseSort(opts.Sort); err != nil {
+		return err
+	}
+	return nil
+}
+
+// apiAddProperty adds a property by address (does API lookup), or stores
+// a manual entry without any lookup when "manual" is set. A house that
+// looks like one already tracked is refused with 409 unless "force" is set.
+func (s *Server) apiAddProperty(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Address string `json:"address"`
+		property.ManualInput
+		NoCache bool `json:"no_cache"`
+		Manual  bool `json:"manual"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		apiError(w, "invalid JSON body", http.StatusBadRequest)
 		return
 	}
+
+	if req.Manual {
+		s.apiAddManualProperty(w, r, req.ManualInput)
+		return
+	}
+
+	if s.propService == nil {
+		apiError(w, "property add not available (no listing provider configured)", http.StatusServiceUnavailable)
+		return
+	}
 	if strings.TrimSpace(req.Address) == "" {
 		apiError(w, "address is required", http.StatusBadRequest)
 		return
 	}
 
-	p, err := s.propService.Add(strings.TrimSpace(req.Address))
+	p, err := s.propService.Add(r.Context(), strings.TrimSpace(req.Address), property.AddOptions{
+		FetchOptions: property.FetchOptions{NoCache: req.NoCache},
+		Force:        req.Force,
+	})
+	var dupErr *property.DuplicateError
+	if errors.As(err, &dupErr) {
+		apiDuplicateError(w, dupErr)
+		return
+	}
 	if err != nil {
-		slog.Error("property add failed", "address", req.Address, "err", err)
-		apiError(w, fmt.Sprintf("adding property: %v", err), http.StatusInternalServerError)
+		logLookupError("property add failed", err, "address", req.Address)
+		apiLookupError(w, "adding property", err)
 		return
 	}
 
 	slog.Info("property added", "id", p.ID, "address", p.Address, "user", auth.UserEmailFromContext(r))
+	s.prefetchPhotos(p)
+	apiJSON(w, p, http.StatusCreated)
+}
+
+// apiAddManualProperty stores a property entered by hand. It needs no
+// listing provider, so it works even when the MLS doesn't have the house.
+func (s *Server) apiAddManualProperty(w http.ResponseWriter, r *http.Request, in property.ManualInput) {
+	if err := in.Validate(); err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p, err := s.propRepo.InsertManual(in)
+	var dupErr *property.DuplicateError
+	if errors.As(err, &dupErr) {
+		apiDuplicateError(w, dupErr)
+		return
+	}
+	if err != nil {
+		apiError(w, fmt.Sprintf("adding property: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("manual property added", "id", p.ID, "address", p.Address, "user", auth.UserEmailFromContext(r))
 	apiJSON(w, p, http.StatusCreated)
 }
 
@@ -214,26 +569,158 @@ func (s *Server) apiGetProperty(w http.ResponseWriter, id int64) {
 		return
 	}
 
+	history, err := s.propRepo.ListSnapshots(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	type response struct {
 		Property *property.Property `json:"property"`
 		Comments interface{}        `json:"comments"`
 		Visits   interface{}        `json:"visits"`
+		History  interface{}        `json:"history"`
 	}
 
-	apiJSON(w, response{Property: p, Comments: comments, Visits: visits}, http.StatusOK)
+	apiJSON(w, response{Property: p, Comments: comments, Visits: visits, History: history}, http.StatusOK)
 }
 
-// apiDeleteProperty removes a property and its comments.
-func (s *Server) apiDeleteProperty(w http.ResponseWriter, r *http.Request, id int64) {
-	if err := s.propRepo.Delete(id); err != nil {
-		apiError(w, fmt.Sprintf("deleting property: %v", err), http.StatusInternalServerError)
+// apiRefreshProperty re-fetches a property from the MLS and records changes.
+func (s *Server) apiRefreshProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	if s.watcher == nil {
+		apiError(w, "property refresh not available (listing provider does not support refresh)", http.StatusServiceUnavailable)
+		return
+	}
+
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+	if p.IsManual() {
+		apiError(w, "property was entered manually; link it to an MLS listing first", http.StatusConflict)
+		return
+	}
+
+	// The body is optional; {"no_cache": true} forces a fresh fetch.
+	var req struct {
+		NoCache bool `json:"no_cache"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.propService.Refresh(r.Context(), id, property.FetchOptions{NoCache: req.NoCache})
+	if err != nil {
+		logLookupError("property refresh failed", err, "id", id)
+		apiLookupError(w, "refreshing property", err)
 		return
 	}
-	slog.Info("property deleted", "id", id, "user", auth.UserEmailFromContext(r))
-	apiJSON(w, map[string]interface{}{"id": id, "removed": true}, http.StatusOK)
+
+	events, err := s.watcher.Record(res)
+	if err != nil {
+		apiError(w, fmt.Sprintf("recording events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("property refreshed", "id", id, "changes", len(res.Changes), "events", len(events), "user", auth.UserEmailFromContext(r))
+	s.prefetchPhotos(res.Property)
+	apiJSON(w, res, http.StatusOK)
+}
+
+// apiLinkProperty attaches a manual entry to a real MLS listing.
+func (s *Server) apiLinkProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	if s.propService == nil {
+		apiError(w, "property link not available (no listing provider configured)", http.StatusServiceUnavailable)
+		return
+	}
+
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+	if !p.IsManual() {
+		apiError(w, "property is already linked to an MLS listing", http.StatusConflict)
+		return
+	}
+
+	var req struct {
+		Address string `json:"address"`
+		NoCache bool   `json:"no_cache"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Address) == "" {
+		apiError(w, "address is required", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.propService.Link(r.Context(), id, strings.TrimSpace(req.Address), property.FetchOptions{NoCache: req.NoCache})
+	if err != nil {
+		logLookupError("property link failed", err, "id", id)
+		apiLookupError(w, "linking property", err)
+		return
+	}
+
+	slog.Info("property linked", "id", id, "mpr_id", res.Property.MprID, "user", auth.UserEmailFromContext(r))
+	s.prefetchPhotos(res.Property)
+	apiJSON(w, res, http.StatusOK)
 }
 
-// apiRateProperty sets a rating on a property.
+// apiListHistory returns recorded listing changes for a property.
+func (s *Server) apiListHistory(w http.ResponseWriter, id int64) {
+	history, err := s.propRepo.ListSnapshots(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if history == nil {
+		history = make([]*property.Snapshot, 0)
+	}
+
+	apiJSON(w, history, http.StatusOK)
+}
+
+// apiEditProperty stores hand corrections for listing fields. Each field
+// in the body overrides the MLS value; null removes the override.
+func (s *Server) apiEditProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, err := s.propRepo.GetByID(id); err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+
+	var edit property.Edit
+	if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+		apiError(w, fmt.Sprintf("invalid JSON body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := edit.Validate(); err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.propRepo.ApplyEdit(id, edit); err != nil {
+		apiError(w, fmt.Sprintf("editing property: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading property: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("property edited", "id", id, "fields", len(edit), "user", auth.UserEmailFromContext(r))
+	apiJSON(w, p, http.StatusOK)
+}
+
+// apiRateProperty sets the caller's rating on a property and responds
+// with the household's ratings.
 func (s *Server) apiRateProperty(w http.ResponseWriter, r *http.Request, id int64) {
 	var req struct {
 		Rating int `json:"rating"`
@@ -247,12 +734,19 @@ func (s *Server) apiRateProperty(w http.ResponseWriter, r *http.Request, id int6
 		return
 	}
 
-	if err := s.propRepo.UpdateRating(id, req.Rating); err != nil {
+	user := auth.UserEmailFromContext(r)
+	if err := s.propRepo.UpdateRating(id, user, req.Rating); err != nil {
 		apiError(w, fmt.Sprintf("updating rating: %v", err), http.StatusInternalServerError)
 		return
 	}
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading property: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-	apiJSON(w, map[string]interface{}{"id": id, "rating": req.Rating}, http.StatusOK)
+	slog.Info("property rated", "id", id, "rating", req.Rating, "user", user)
+	apiJSON(w, map[string]interface{}{"id": id, "rating": req.Rating, "ratings": p.Ratings}, http.StatusOK)
 }
 
 // apiSetVisitStatus sets the visit status on a property.
@@ -9,15 +9,24 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/evcraddock/house-finder/internal/alert"
 	"github.com/evcraddock/house-finder/internal/auth"
 	"github.com/evcraddock/house-finder/internal/comment"
 	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/mls"
 	"github.com/evcraddock/house-finder/internal/property"
 )
 
 // testAPIServerWithDB creates a test server and returns the server, db, and a valid bearer token.
 func testAPIServerWithDB(t *testing.T) (*Server, *sql.DB, string) {
+	t.Helper()
+	return testAPIServerWithProvider(t, nil)
+}
+
+// testAPIServerWithProvider is testAPIServerWithDB with a listing provider configured.
+func testAPIServerWithProvider(t *testing.T, provider mls.Provider) (*Server, *sql.DB, string) {
 	t.Helper()
 	path := filepath.Join(t.TempDir(), "test.db")
 	d, err := db.Open(path)
@@ -35,7 +44,7 @@ func testAPIServerWithDB(t *testing.T) (*Server, *sql.DB, string) {
 		DevMode:    true,
 		BaseURL:    "http://localhost:8080",
 	}
-	srv, err := NewServer(d, cfg)
+	srv, err := NewServer(d, cfg, provider)
 	if err != nil {
 		t.Fatalf("new server: %v", err)
 	}
@@ -178,10 +187,24 @@ func TestAPIDeleteProperty(t *testing.T) {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	// Verify deleted
+	// Moved to the trash, not gone
 	w2 := apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d", id), token, nil)
-	if w2.Code != http.StatusNotFound {
-		t.Errorf("after delete: status = %d, want %d", w2.Code, http.StatusNotFound)
+	if w2.Code != http.StatusOK {
+		t.Fatalf("after delete: status = %d, want %d", w2.Code, http.StatusOK)
+	}
+	var resp struct {
+		Property *property.Property `json:"property"`
+	}
+	if err := json.NewDecoder(w2.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Property.State != property.StateDeleted {
+		t.Errorf("state = %q, want %q", resp.Property.State, property.StateDeleted)
+	}
+
+	w3 := apiRequest(t, srv, "DELETE", fmt.Sprintf("/api/properties/%d", id), token, nil)
+	if w3.Code != http.StatusConflict {
+		t.Errorf("second delete: status = %d, want %d", w3.Code, http.StatusConflict)
 	}
 }
 
@@ -206,6 +229,9 @@ func TestAPIRateProperty(t *testing.T) {
 	if resp.Property.Rating == nil || *resp.Property.Rating != 3 {
 		t.Error("expected rating 3")
 	}
+	if r := resp.Property.RatingBy("admin@example.com"); r == nil || *r != 3 {
+		t.Errorf("caller's rating = %v, want 3", r)
+	}
 }
 
 func TestAPIRatePropertyInvalid(t *testing.T) {
@@ -283,6 +309,99 @@ func TestAPIAddPropertyWithoutMLSClient(t *testing.T) {
 	}
 }
 
+func TestAPIAddPropertyWithFileProvider(t *testing.T) {
+	provider, err := mls.NewFileProvider(filepath.Join("..", "mls", "testdata"))
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, _, token := testAPIServerWithProvider(t, provider)
+
+	body := map[string]string{"address": "123 Main St, Yukon, OK 73099"}
+	w := apiRequest(t, srv, "POST", "/api/properties", token, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var p property.Property
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.MprID != "M1234567890" {
+		t.Errorf("mpr_id = %q, want M1234567890", p.MprID)
+	}
+	if p.Price == nil || *p.Price != 250000 {
+		t.Errorf("price = %v, want 250000", p.Price)
+	}
+	if p.HOAFee == nil || *p.HOAFee != 45 {
+		t.Errorf("hoa_fee = %v, want 45", p.HOAFee)
+	}
+	if p.ListDate == nil || *p.ListDate != "2024-03-01" {
+		t.Errorf("list_date = %v, want 2024-03-01", p.ListDate)
+	}
+	if p.DaysOnMarket == nil {
+		t.Error("days_on_market = nil, want derived from list_date")
+	}
+	if p.Latitude == nil || p.Longitude == nil {
+		t.Error("expected coordinates")
+	}
+
+	w = apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/refresh", p.ID), token, nil)
+	if w.Code != http.StatusOK {
+		t.Errorf("refresh status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestAPIAddPropertyLookupErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		suggest        string
+		rapidStatus    int
+		retryAfter     string
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{"no such address", `{"autocomplete": []}`, http.StatusOK, "", http.StatusNotFound, ""},
+		{"quota exceeded", `{"autocomplete": [{"mpr_id": "M1"}]}`, http.StatusTooManyRequests, "3600", http.StatusTooManyRequests, "3600"},
+		{"upstream down", `{"autocomplete": [{"mpr_id": "M1"}]}`, http.StatusServiceUnavailable, "", http.StatusBadGateway, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.suggest) //nolint:errcheck // test server
+			}))
+			defer suggest.Close()
+			hulk := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"data": {"home": {"href": "/detail/M1"}}}`) //nolint:errcheck // test server
+			}))
+			defer hulk.Close()
+			rapid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.rapidStatus)
+			}))
+			defer rapid.Close()
+
+			mlsClient, err := mls.NewClient("test-key")
+			if err != nil {
+				t.Fatalf("new mls client: %v", err)
+			}
+			mls.SetTestURLs(mlsClient, suggest.URL, hulk.URL, rapid.URL)
+			mls.SetTestBackoff(mlsClient, time.Millisecond)
+			srv, _, token := testAPIServerWithProvider(t, mlsClient)
+
+			w := apiRequest(t, srv, "POST", "/api/properties", token, map[string]string{"address": "1 Main St"})
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
 func TestAPIAddPropertyEmptyAddress(t *testing.T) {
 	srv, _, token := testAPIServerWithDB(t)
 
@@ -301,7 +420,7 @@ func TestAPIListPropertiesWithMinRating(t *testing.T) {
 
 	// Rate it
 	repo := property.NewRepository(d)
-	if err := repo.UpdateRating(id, 3); err != nil {
+	if err := repo.UpdateRating(id, "admin@example.com", 3); err != nil {
 		t.Fatalf("update rating: %v", err)
 	}
 
@@ -321,6 +440,32 @@ func TestAPIListPropertiesWithMinRating(t *testing.T) {
 	if len(props) != 1 {
 		t.Errorf("got %d properties with min_rating=3, want 1", len(props))
 	}
+
+	// A partner's low rating pulls the household average under 3, but
+	// not the caller's own rating.
+	if err := repo.UpdateRating(id, "partner@example.com", 1); err != nil {
+		t.Fatalf("update rating: %v", err)
+	}
+	for query, want := range map[string]int{
+		"min_rating=3":                     0,
+		"min_rating=3&rating_by=household": 0,
+		"min_rating=3&rating_by=me":        1,
+	} {
+		w := apiRequest(t, srv, "GET", "/api/properties?"+query, token, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d", query, w.Code, http.StatusOK)
+		}
+		var props []*property.Property
+		if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(props) != want {
+			t.Errorf("got %d properties with %s, want %d", len(props), query, want)
+		}
+	}
+	if w := apiRequest(t, srv, "GET", "/api/properties?rating_by=you", token, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("rating_by=you: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
 }
 
 func TestAPIListPropertiesWithVisitStatus(t *testing.T) {
@@ -367,6 +512,60 @@ func TestAPIListPropertiesWithVisitStatus(t *testing.T) {
 	}
 }
 
+func TestAPIListPropertiesWithListingFilters(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	cheap := insertAPITestProperty(t, d)
+	big := insertAPITestProperty(t, d)
+	insertAPITestProperty(t, d) // no listing details
+	for _, stmt := range []string{
+		fmt.Sprintf("UPDATE properties SET price = 180000, bedrooms = 2, property_type = 'condo', status = 'for_sale' WHERE id = %d", cheap),
+		fmt.Sprintf("UPDATE properties SET price = 420000, bedrooms = 4, property_type = 'single_family', status = 'pending' WHERE id = %d", big),
+	} {
+		if _, err := d.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{"price range", "min_price=100000&max_price=200000", []int64{cheap}},
+		{"beds", "min_beds=3", []int64{big}},
+		{"any of types", "property_type=condo&property_type=single_family&sort=price:desc", []int64{big, cheap}},
+		{"listing status", "listing_status=PENDING", []int64{big}},
+		{"sort by price", "sort=price", []int64{cheap, big, big + 1}},
+		{"blank values ignored", "min_price=&property_type=&sort=bedrooms", []int64{big, cheap, big + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "GET", "/api/properties?"+tt.query, token, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+			}
+			var props []*property.Property
+			if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var got []int64
+			for _, p := range props {
+				got = append(got, p.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, query := range []string{"min_price=cheap", "max_beds=-1", "sort=pool", "sort=price:up"} {
+		w := apiRequest(t, srv, "GET", "/api/properties?"+query, token, nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestAPIAddVisitSetsVisited(t *testing.T) {
 	srv, d, token := testAPIServerWithDB(t)
 	id := insertAPITestProperty(t, d)
@@ -449,3 +648,220 @@ func TestAPIMethodNotAllowed(t *testing.T) {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
 	}
 }
+
+func TestAPIRefreshPropertyWithoutMLSClient(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+
+	w := apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/refresh", id), token, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d (no MLS client)", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAPIRefreshProperty(t *testing.T) {
+	rapidServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := fmt.Fprint(w, `{"list_price": 199000, "prop_status": "pending"}`); err != nil {
+			t.Errorf("write response: %v", err)
+		}
+	}))
+	defer rapidServer.Close()
+
+	mlsClient, err := mls.NewClient("test-key")
+	if err != nil {
+		t.Fatalf("new mls client: %v", err)
+	}
+	mls.SetTestURLs(mlsClient, "", "", rapidServer.URL)
+
+	srv, d, token := testAPIServerWithProvider(t, mlsClient)
+	id := insertAPITestProperty(t, d)
+
+	w := apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/refresh", id), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var res property.RefreshResult
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(res.Changes) != 2 {
+		t.Errorf("got %d changes, want 2", len(res.Changes))
+	}
+
+	w = apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/history", id), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("history status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var history []*property.Snapshot
+	if err := json.NewDecoder(w.Body).Decode(&history); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("got %d history entries, want 2", len(history))
+	}
+
+	events, err := srv.eventRepo.List(id, 0)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != alert.KindPending {
+		t.Errorf("events = %+v, want one pending event", events)
+	}
+
+	w = apiRequest(t, srv, "POST", "/api/properties/9999/refresh", token, nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing property status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAPIListEvents(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+
+	if err := srv.eventRepo.Add(&alert.Event{PropertyID: id, Kind: alert.KindPending, Message: "Went pending"}); err != nil {
+		t.Fatalf("add event: %v", err)
+	}
+
+	w := apiRequest(t, srv, "GET", fmt.Sprintf("/api/events?property_id=%d", id), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var events []*alert.Event
+	if err := json.NewDecoder(w.Body).Decode(&events); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != alert.KindPending {
+		t.Errorf("events = %+v", events)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/events?limit=0", token, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAPIAddManualProperty(t *testing.T) {
+	// Manual entries need no listing provider.
+	srv, _, token := testAPIServerWithDB(t)
+
+	body := map[string]interface{}{"manual": true, "address": "789 Elm St, Yukon, OK 73099", "price": 240000, "bedrooms": 3}
+	w := apiRequest(t, srv, "POST", "/api/properties", token, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var p property.Property
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.Source != property.SourceManual {
+		t.Errorf("source = %q, want %q", p.Source, property.SourceManual)
+	}
+	if p.Price == nil || *p.Price != 240000 {
+		t.Errorf("price = %v, want 240000", p.Price)
+	}
+
+	w = apiRequest(t, srv, "POST", "/api/properties", token, map[string]interface{}{"manual": true, "address": ""})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("empty address status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAPILinkProperty(t *testing.T) {
+	provider, err := mls.NewFileProvider(filepath.Join("..", "mls", "testdata"))
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, d, token := testAPIServerWithProvider(t, provider)
+
+	manual, err := property.NewRepository(d).InsertManual(property.ManualInput{Address: "123 Main St"})
+	if err != nil {
+		t.Fatalf("insert manual: %v", err)
+	}
+
+	refreshPath := fmt.Sprintf("/api/properties/%d/refresh", manual.ID)
+	linkPath := fmt.Sprintf("/api/properties/%d/link", manual.ID)
+
+	w := apiRequest(t, srv, "POST", refreshPath, token, nil)
+	if w.Code != http.StatusConflict {
+		t.Errorf("refresh manual status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	w = apiRequest(t, srv, "POST", linkPath, token, map[string]string{"address": "M1234567890"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("link status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var res property.RefreshResult
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if res.Property.MprID != "M1234567890" || res.Property.Source != property.SourceMLS {
+		t.Errorf("mpr_id = %q, source = %q after link", res.Property.MprID, res.Property.Source)
+	}
+
+	w = apiRequest(t, srv, "POST", refreshPath, token, nil)
+	if w.Code != http.StatusOK {
+		t.Errorf("refresh linked status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = apiRequest(t, srv, "POST", linkPath, token, map[string]string{"address": "M2222222222"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("relink status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestAPIEditProperty(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+	path := fmt.Sprintf("/api/properties/%d", id)
+
+	w := apiRequest(t, srv, "PATCH", path, token, map[string]interface{}{"bedrooms": 4, "lot_size": "0.25"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var p property.Property
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.Bedrooms == nil || *p.Bedrooms != 4 {
+		t.Errorf("bedrooms = %v, want 4", p.Bedrooms)
+	}
+	if !p.IsOverridden("bedrooms") || !p.IsOverridden("lot_size") {
+		t.Errorf("overrides = %v", p.Overrides)
+	}
+
+	w = apiRequest(t, srv, "PATCH", path, token, map[string]interface{}{"bedrooms": nil})
+	if w.Code != http.StatusOK {
+		t.Fatalf("clear status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	p = property.Property{}
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.Bedrooms != nil || p.IsOverridden("bedrooms") {
+		t.Errorf("bedrooms = %v after clearing override", p.Bedrooms)
+	}
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"unknown field", map[string]interface{}{"pool": 1}},
+		{"bad value", map[string]interface{}{"sqft": "big"}},
+		{"empty", map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "PATCH", path, token, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	w = apiRequest(t, srv, "PATCH", "/api/properties/9999", token, map[string]interface{}{"sqft": 1})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing property status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
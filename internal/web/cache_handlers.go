@@ -0,0 +1,43 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+// handleAPICache handles /api/cache.
+// GET lists cached listing responses; DELETE clears them (optional mpr_id query param).
+func (s *Server) handleAPICache(w http.ResponseWriter, r *http.Request) {
+	if s.mlsCache == nil {
+		apiError(w, "MLS cache not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := s.mlsCache.List()
+		if err != nil {
+			apiError(w, fmt.Sprintf("listing cache: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if entries == nil {
+			entries = make([]*mls.CacheEntry, 0)
+		}
+		apiJSON(w, entries, http.StatusOK)
+	case http.MethodDelete:
+		mprID := r.URL.Query().Get("mpr_id")
+		n, err := s.mlsCache.Clear(mprID)
+		if err != nil {
+			apiError(w, fmt.Sprintf("clearing cache: %v", err), http.StatusInternalServerError)
+			return
+		}
+		slog.Info("mls cache cleared", "mpr_id", mprID, "removed", n, "user", auth.UserEmailFromContext(r))
+		apiJSON(w, map[string]int64{"removed": n}, http.StatusOK)
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
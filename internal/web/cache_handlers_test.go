@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestAPICacheNotEnabled(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	w := apiRequest(t, srv, "GET", "/api/cache", token, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAPICache(t *testing.T) {
+	mlsClient, err := mls.NewClient("test-key")
+	if err != nil {
+		t.Fatalf("new mls client: %v", err)
+	}
+	srv, d, token := testAPIServerWithProvider(t, mls.NewCachedProvider(mlsClient, mls.NewCache(nil, time.Hour)))
+	if srv.mlsCache == nil {
+		t.Fatal("expected cache to be picked up from the provider")
+	}
+	// The helper opens the database, so point the server at a cache backed by it.
+	cache := mls.NewCache(d, time.Hour)
+	srv.mlsCache = cache
+
+	for _, r := range []*mls.Result{
+		{MprID: "M1", RealtorURL: "/detail/1", RawJSON: []byte(`{}`)},
+		{MprID: "M2", RealtorURL: "/detail/2", RawJSON: []byte(`{}`)},
+	} {
+		if err := cache.Put(r); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+	}
+
+	w := apiRequest(t, srv, "GET", "/api/cache", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var entries []*mls.CacheEntry
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(entries))
+	}
+
+	w = apiRequest(t, srv, "DELETE", "/api/cache?mpr_id=M1", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("clear status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp struct {
+		Removed int64 `json:"removed"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Removed != 1 {
+		t.Errorf("removed = %d, want 1", resp.Removed)
+	}
+}
@@ -0,0 +1,115 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/compare"
+	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/visit"
+)
+
+// parseCompareIDs parses the comma-separated ids parameter, dropping
+// repeats, and checks there are enough, but not too many, to compare.
+func parseCompareIDs(raw string) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid property ID: %s", field)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < compare.MinProperties || len(ids) > compare.MaxProperties {
+		return nil, fmt.Errorf("ids must list %d-%d properties, got %d", compare.MinProperties, compare.MaxProperties, len(ids))
+	}
+	return ids, nil
+}
+
+// compareProperties loads the properties to compare, in the order given.
+func (s *Server) compareProperties(ids []int64) ([]*property.Property, error) {
+	props := make([]*property.Property, len(ids))
+	for i, id := range ids {
+		p, err := s.propRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		props[i] = p
+	}
+	return props, nil
+}
+
+// comparison loads the properties' visits and comments and lays them
+// side by side.
+func (s *Server) comparison(props []*property.Property) (*compare.Comparison, error) {
+	visits := make([][]*visit.Visit, len(props))
+	comments := make([][]*comment.Comment, len(props))
+	for i, p := range props {
+		var err error
+		if visits[i], err = s.visitRepo.ListByPropertyID(p.ID); err != nil {
+			return nil, fmt.Errorf("loading visits: %w", err)
+		}
+		if comments[i], err = s.commentRepo.ListByPropertyID(p.ID); err != nil {
+			return nil, fmt.Errorf("loading comments: %w", err)
+		}
+	}
+	return compare.Build(props, visits, comments), nil
+}
+
+// handleAPICompare handles GET /api/compare?ids=1,4,7. It responds with
+// the properties, one row per field with the best and worst values
+// marked, and each property's latest comments.
+func (s *Server) handleAPICompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ids, err := parseCompareIDs(r.URL.Query().Get("ids"))
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	props, err := s.compareProperties(ids)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	c, err := s.comparison(props)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	apiJSON(w, c, http.StatusOK)
+}
+
+// handleCompare renders the side-by-side comparison page.
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseCompareIDs(r.URL.Query().Get("ids"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	props, err := s.compareProperties(ids)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	c, err := s.comparison(props)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error comparing properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, "compare.html", c)
+}
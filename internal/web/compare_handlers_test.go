@@ -0,0 +1,107 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/compare"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPICompare(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	a := insertAPITestProperty(t, d)
+	b := insertAPITestProperty(t, d)
+
+	if err := property.NewRepository(d).UpdateRating(a, "admin@example.com", 4); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	if _, err := comment.NewRepository(d).Add(b, "great light", "admin@example.com"); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+
+	// Repeats are dropped and the order given is kept.
+	w := apiRequest(t, srv, "GET", fmt.Sprintf("/api/compare?ids=%d,%d,%d", b, a, b), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var c compare.Comparison
+	if err := json.NewDecoder(w.Body).Decode(&c); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(c.Properties) != 2 || c.Properties[0].ID != b || c.Properties[1].ID != a {
+		t.Fatalf("properties = %+v, want #%d then #%d", c.Properties, b, a)
+	}
+	var rated bool
+	for _, r := range c.Rows {
+		if r.Label == "Rated by admin@example.com" {
+			rated = fmt.Sprint(r.Values) == "[ ★★★★]"
+		}
+	}
+	if !rated {
+		t.Errorf("rows = %+v, want admin's rating of #%d", c.Rows, a)
+	}
+	if len(c.Comments[0]) != 1 || c.Comments[0][0].Text != "great light" || len(c.Comments[1]) != 0 {
+		t.Errorf("comments = %+v", c.Comments)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"one property", "GET", fmt.Sprintf("/api/compare?ids=%d", a), http.StatusBadRequest},
+		{"too many", "GET", "/api/compare?ids=1,2,3,4,5,6,7", http.StatusBadRequest},
+		{"bad id", "GET", fmt.Sprintf("/api/compare?ids=%d,abc", a), http.StatusBadRequest},
+		{"missing property", "GET", fmt.Sprintf("/api/compare?ids=%d,9999", a), http.StatusNotFound},
+		{"post not allowed", "POST", fmt.Sprintf("/api/compare?ids=%d,%d", a, b), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, tt.path, token, nil)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleCompare(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "123 Main St", "M-COMPARE-1")
+	insertTestProperty(t, d, "456 Oak Ave", "M-COMPARE-2")
+	if _, err := d.Exec("UPDATE properties SET price = 300000 WHERE id = 2"); err != nil {
+		t.Fatalf("update price: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/compare?ids=1,2", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{"123 Main St", "456 Oak Ave", "Price per sqft", `class="compare-best">$250,000`, `class="compare-worst">$300,000`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in response", want)
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/compare?ids=1":    http.StatusBadRequest,
+		"/compare?ids=1,99": http.StatusNotFound,
+	} {
+		r := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// handleAPIDuplicates handles GET /api/duplicates: every group of tracked
+// properties that look like the same house.
+func (s *Server) handleAPIDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groups, err := s.propRepo.DuplicateGroups()
+	if err != nil {
+		apiError(w, fmt.Sprintf("finding duplicates: %v", err), http.StatusInternalServerError)
+		return
+	}
+	apiJSON(w, groups, http.StatusOK)
+}
+
+// apiMergeProperty folds the property named in the body into id.
+func (s *Server) apiMergeProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	var req struct {
+		From int64 `json:"from"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.From == 0 {
+		apiError(w, "from is required", http.StatusBadRequest)
+		return
+	}
+	if req.From == id {
+		apiError(w, "cannot merge a property into itself", http.StatusBadRequest)
+		return
+	}
+	for _, pid := range []int64{id, req.From} {
+		if _, err := s.propRepo.GetByID(pid); err != nil {
+			apiError(w, fmt.Sprintf("property %d not found", pid), http.StatusNotFound)
+			return
+		}
+	}
+
+	p, err := s.propRepo.Merge(id, req.From)
+	if err != nil {
+		apiError(w, fmt.Sprintf("merging properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("properties merged", "id", id, "from", req.From, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, p, http.StatusOK)
+}
+
+// apiDuplicateError writes an add refused because the house looks like
+// one already tracked: 409 with the matches under "duplicates", so the
+// caller can merge instead or add again with force.
+func apiDuplicateError(w http.ResponseWriter, err *property.DuplicateError) {
+	apiJSON(w, map[string]interface{}{
+		"error":      err.Error(),
+		"duplicates": err.Duplicates,
+	}, http.StatusConflict)
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPIDuplicatesAndMerge(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	insertAPITestProperty(t, d)
+
+	var first, second property.Property
+	w := apiRequest(t, srv, "POST", "/api/properties", token, map[string]interface{}{"manual": true, "address": "77 Sunset Boulevard, Edmond, OK"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add: %d %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&first); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// The same house again is refused with the match, until forced.
+	dup := map[string]interface{}{"manual": true, "address": "77 Sunset Blvd."}
+	w = apiRequest(t, srv, "POST", "/api/properties", token, dup)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("duplicate add: %d %s, want 409", w.Code, w.Body.String())
+	}
+	var refused struct {
+		Duplicates []property.Duplicate `json:"duplicates"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&refused); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(refused.Duplicates) != 1 || refused.Duplicates[0].ID != first.ID {
+		t.Fatalf("duplicates = %+v, want #%d", refused.Duplicates, first.ID)
+	}
+	dup["force"] = true
+	w = apiRequest(t, srv, "POST", "/api/properties", token, dup)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("forced add: %d %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&second); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	var groups []*property.DuplicateGroup
+	w = apiRequest(t, srv, "GET", "/api/duplicates", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("duplicates status = %d", w.Code)
+	}
+	if err := json.NewDecoder(w.Body).Decode(&groups); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Properties) != 2 {
+		t.Fatalf("groups = %+v", groups)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"missing from", fmt.Sprintf("/api/properties/%d/merge", first.ID), map[string]int64{}, http.StatusBadRequest},
+		{"into itself", fmt.Sprintf("/api/properties/%d/merge", first.ID), map[string]int64{"from": first.ID}, http.StatusBadRequest},
+		{"unknown duplicate", fmt.Sprintf("/api/properties/%d/merge", first.ID), map[string]int64{"from": 99999}, http.StatusNotFound},
+		{"merge", fmt.Sprintf("/api/properties/%d/merge", first.ID), map[string]int64{"from": second.ID}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "POST", tt.path, token, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/duplicates", token, nil)
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("after merge, duplicates = %s, want []", w.Body.String())
+	}
+}
+
+func TestHandleDetailShowsDuplicateWarning(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "10 Lake Drive", "M-LAKE-1")
+	insertTestProperty(t, d, "10 Lake Dr", "M-LAKE-2")
+
+	// insertTestProperty skips the repository, so fill canonical addresses
+	// the way server startup does for rows that predate them.
+	if _, err := srv.propRepo.BackfillCanonical(); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+
+	p, err := srv.propRepo.GetByMprID("M-LAKE-2")
+	if err != nil || p == nil {
+		t.Fatalf("get: %v", err)
+	}
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/property/%d", p.ID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Possible Duplicate") || !strings.Contains(w.Body.String(), "10 Lake Drive") {
+		t.Error("expected duplicate warning naming the other entry")
+	}
+}
@@ -25,6 +25,46 @@ type emailResponse struct {
 	Body    string   `json:"body"`
 }
 
+// recipients returns all authorized users plus the admin, deduplicated.
+func (s *Server) recipients() ([]string, error) {
+	allUsers, err := s.users.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	seen := make(map[string]bool)
+	var recipients []string
+	if s.authCfg.AdminEmail != "" {
+		recipients = append(recipients, s.authCfg.AdminEmail)
+		seen[s.authCfg.AdminEmail] = true
+	}
+	for _, u := range allUsers {
+		if u.Email != "" && !seen[u.Email] {
+			recipients = append(recipients, u.Email)
+			seen[u.Email] = true
+		}
+	}
+	return recipients, nil
+}
+
+// notifyHousehold emails all recipients. Used for listing alerts.
+func (s *Server) notifyHousehold(subject, body string) error {
+	if !s.smtpCfg.IsConfigured() {
+		return fmt.Errorf("SMTP not configured")
+	}
+	recipients, err := s.recipients()
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("no authorized users configured")
+	}
+	if err := email.Send(s.smtpCfg, recipients, subject, body); err != nil {
+		return err
+	}
+	slog.Info("alert email sent", "to", recipients, "subject", subject)
+	return nil
+}
+
 // handleAPIEmail handles POST /api/email.
 func (s *Server) handleAPIEmail(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -38,24 +78,11 @@ func (s *Server) handleAPIEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Find recipients — all authorized users plus admin
-	allUsers, err := s.users.List()
+	recipients, err := s.recipients()
 	if err != nil {
-		apiError(w, fmt.Sprintf("listing users: %v", err), http.StatusInternalServerError)
+		apiError(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	seen := make(map[string]bool)
-	var recipients []string
-	if s.authCfg.AdminEmail != "" {
-		recipients = append(recipients, s.authCfg.AdminEmail)
-		seen[s.authCfg.AdminEmail] = true
-	}
-	for _, u := range allUsers {
-		if u.Email != "" && !seen[u.Email] {
-			recipients = append(recipients, u.Email)
-			seen[u.Email] = true
-		}
-	}
 	if len(recipients) == 0 {
 		apiError(w, "no authorized users configured", http.StatusBadRequest)
 		return
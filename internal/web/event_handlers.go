@@ -0,0 +1,50 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/evcraddock/house-finder/internal/alert"
+)
+
+// handleAPIEvents handles GET /api/events.
+// Optional query params: property_id, limit (default 50).
+func (s *Server) handleAPIEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var propertyID int64
+	if idStr := r.URL.Query().Get("property_id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			apiError(w, "invalid property_id", http.StatusBadRequest)
+			return
+		}
+		propertyID = id
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 {
+			apiError(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	events, err := s.eventRepo.List(propertyID, limit)
+	if err != nil {
+		apiError(w, fmt.Sprintf("listing events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if events == nil {
+		events = make([]*alert.Event, 0)
+	}
+
+	apiJSON(w, events, http.StatusOK)
+}
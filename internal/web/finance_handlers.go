@@ -0,0 +1,108 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// costResponse is the response from GET /api/properties/{id}/cost.
+type costResponse struct {
+	PropertyID int64            `json:"property_id"`
+	Profile    *finance.Profile `json:"profile"`
+	Cost       finance.Cost     `json:"cost"`
+}
+
+// handleAPIFinancing handles /api/financing: GET returns the caller's
+// financing profile, PUT updates it.
+func (s *Server) handleAPIFinancing(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.apiGetFinancing(w, r)
+	case http.MethodPut:
+		s.apiSaveFinancing(w, r)
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// apiGetFinancing returns the caller's profile, or the defaults if they
+// haven't saved one.
+func (s *Server) apiGetFinancing(w http.ResponseWriter, r *http.Request) {
+	profile, err := s.financeRepo.Get(auth.UserEmailFromContext(r))
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading financing profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	apiJSON(w, profile, http.StatusOK)
+}
+
+// apiSaveFinancing updates the caller's profile. Fields missing from the
+// body keep their current values.
+func (s *Server) apiSaveFinancing(w http.ResponseWriter, r *http.Request) {
+	email := auth.UserEmailFromContext(r)
+	profile, err := s.financeRepo.Get(email)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading financing profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(profile); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	profile.Email = email
+
+	saved, err := s.financeRepo.Save(profile)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("financing profile saved", "user", email)
+	apiJSON(w, saved, http.StatusOK)
+}
+
+// apiPropertyCost returns the estimated monthly cost of a property under
+// the caller's financing profile.
+func (s *Server) apiPropertyCost(w http.ResponseWriter, r *http.Request, id int64) {
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+	if p.Price == nil {
+		apiError(w, "property has no price", http.StatusConflict)
+		return
+	}
+
+	profile, err := s.financeRepo.Get(auth.UserEmailFromContext(r))
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading financing profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	apiJSON(w, costResponse{PropertyID: p.ID, Profile: profile, Cost: monthlyCost(profile, p)}, http.StatusOK)
+}
+
+// monthlyCost estimates what p costs per month under profile. The caller
+// must check that p has a price.
+func monthlyCost(profile *finance.Profile, p *property.Property) finance.Cost {
+	return profile.Monthly(*p.Price, p.AnnualTax, p.HOAFee)
+}
+
+// withinMonthly keeps the properties whose estimated monthly cost is at
+// most max. Properties without a price can't be costed and are dropped.
+func withinMonthly(props []*property.Property, profile *finance.Profile, max int64) []*property.Property {
+	kept := props[:0]
+	for _, p := range props {
+		if p.Price != nil && monthlyCost(profile, p).Total <= max {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
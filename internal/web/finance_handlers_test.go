@@ -0,0 +1,206 @@
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// insertPricedProperty adds a $300,000 house with $3,600/yr taxes and a
+// $45/mo HOA: $1,987/mo under the default financing profile.
+func insertPricedProperty(t *testing.T, d *sql.DB) int64 {
+	t.Helper()
+	price, tax, hoa := int64(300000), int64(3600), int64(45)
+	p, err := property.NewRepository(d).Insert(&property.Property{
+		Address:    "300 Budget Ave",
+		MprID:      "M-BUDGET-300",
+		RealtorURL: "https://realtor.com/test",
+		RawJSON:    json.RawMessage(`{}`),
+		Price:      &price,
+		AnnualTax:  &tax,
+		HOAFee:     &hoa,
+	})
+	if err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	return p.ID
+}
+
+func TestAPIFinancing(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	var profile finance.Profile
+	w := apiRequest(t, srv, "GET", "/api/financing", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if profile.Email != "admin@example.com" || profile.DownPaymentPercent != 20 {
+		t.Errorf("default profile = %+v", profile)
+	}
+
+	w = apiRequest(t, srv, "PUT", "/api/financing", token, map[string]interface{}{"rate_percent": 7, "email": "someone@else.com"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("put status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if profile.RatePercent != 7 || profile.TermYears != 30 || profile.Email != "admin@example.com" {
+		t.Errorf("saved profile = %+v, want rate 7 with other fields kept", profile)
+	}
+
+	tests := []struct {
+		name string
+		body interface{}
+	}{
+		{"out of range", map[string]interface{}{"down_payment_percent": 120}},
+		{"fractional term", map[string]interface{}{"term_years": 29.5}},
+		{"not an object", "down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "PUT", "/api/financing", token, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	// Each user has their own profile.
+	otherKey, _, err := srv.apiKeys.Create("other", "other@example.com")
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	w = apiRequest(t, srv, "GET", "/api/financing", otherKey, nil)
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if profile.RatePercent != 6.5 {
+		t.Errorf("other user's rate = %g, want default 6.5", profile.RatePercent)
+	}
+}
+
+func TestAPIPropertyCost(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertPricedProperty(t, d)
+	unpriced := insertAPITestProperty(t, d)
+
+	var resp costResponse
+	w := apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/cost", id), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.PropertyID != id || resp.Cost.Total != 1987 || resp.Cost.HOA != 45 || resp.Cost.Taxes != 300 {
+		t.Errorf("cost = %+v", resp)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"no price", fmt.Sprintf("/api/properties/%d/cost", unpriced), http.StatusConflict},
+		{"unknown property", "/api/properties/99999/cost", http.StatusNotFound},
+		{"bad ID", "/api/properties/abc/cost", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "GET", tt.path, token, nil)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAPIListPropertiesWithMaxMonthly(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	insertPricedProperty(t, d)
+	insertAPITestProperty(t, d) // no price, so never within a budget
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{"no cap", "", http.StatusOK, 2},
+		{"within budget", "?max_monthly=2000", http.StatusOK, 1},
+		{"over budget", "?max_monthly=1500", http.StatusOK, 0},
+		{"not a number", "?max_monthly=lots", http.StatusBadRequest, 0},
+		{"zero", "?max_monthly=0", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "GET", "/api/properties"+tt.query, token, nil)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var props []*property.Property
+			if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(props) != tt.wantCount {
+				t.Errorf("got %d properties, want %d", len(props), tt.wantCount)
+			}
+		})
+	}
+
+	// A cheaper loan brings the house under a lower cap.
+	apiRequest(t, srv, "PUT", "/api/financing", token, map[string]interface{}{"rate_percent": 3})
+	var props []*property.Property
+	w := apiRequest(t, srv, "GET", "/api/properties?max_monthly=1500", token, nil)
+	if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(props) != 1 {
+		t.Errorf("at 3%%, got %d properties under $1,500, want 1", len(props))
+	}
+}
+
+func TestHandleDetailShowsCost(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	id := insertPricedProperty(t, d)
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/property/%d", id), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Monthly Cost") || !strings.Contains(body, "$1,987/mo") {
+		t.Error("expected monthly cost card with total")
+	}
+}
+
+func TestSettingsFinancingScriptAfterPlaces(t *testing.T) {
+	src, err := templateFS.ReadFile("templates/settings.html")
+	if err != nil {
+		t.Fatalf("read settings template: %v", err)
+	}
+	// The financing script must start after the places script ends, with
+	// its top-level loadPlaces() call; inside addPlace it would never run
+	// on page load.
+	page := string(src)
+	placesEnd := strings.Index(page, "\n    loadPlaces();\n")
+	financing := strings.Index(page, "// === Financing ===")
+	if placesEnd < 0 || financing < placesEnd {
+		t.Errorf("financing script at %d, want it after the places script ends at %d", financing, placesEnd)
+	}
+}
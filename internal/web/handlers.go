@@ -1,28 +1,63 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/evcraddock/house-finder/internal/finance"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/tag"
 )
 
 type listData struct {
 	Properties     []*property.Property
 	IsAdmin        bool
 	Tab            string // "all", "want_to_visit", or "visited"
+	Tag            string // only houses with this tag; empty = all
+	Scored         bool   // the household has scoring criteria
 	AllCnt         int
 	WantToVisitCnt int
 	VisitedCnt     int
+	Filter         url.Values   // the filter form's values
+	FilterQuery    template.URL // Filter and Tag, encoded, for the tab links
+	Filtered       bool         // any listing-field filter is set
+	Types          []string     // property types to offer in the filter form
+	Statuses       []string     // listing statuses to offer
+	Cities         []string     // cities to offer
+	SortOptions    []sortOption
+}
+
+// sortOption is a choice in the list page's sort menu.
+type sortOption struct {
+	Value string // as for ?sort=
+	Label string
+}
+
+// listFilterParams are the list page's filter form fields, kept in the
+// tab links so switching tabs keeps the filters.
+var listFilterParams = []string{
+	"min_price", "max_price", "min_beds", "max_beds", "min_baths", "max_baths",
+	"min_sqft", "max_sqft", "min_year", "max_year",
+	"property_type", "listing_status", "city", "zip", "sort",
 }
 
 type detailData struct {
 	Property *property.Property
 	Comments interface{}
 	Visits   interface{}
+	Photos   []photoLink
 	IsAdmin  bool
+	User     string // the viewer's email, for picking out their rating
+
+	Financing  *finance.Profile
+	Cost       *finance.Cost // nil when the property has no price
+	Duplicates []property.Duplicate
 }
 
 // handleList renders the property list page.
@@ -40,22 +75,55 @@ func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
 		tab = "all"
 	}
 
-	// Get all properties and filtered lists
-	allProps, err := s.propRepo.List(property.ListOptions{})
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error loading properties: %v", err), http.StatusInternalServerError)
+	var base property.ListOptions
+	tagName := strings.TrimSpace(r.URL.Query().Get("tag"))
+	if tagName != "" {
+		if _, err := s.tagRepo.GetByName(tagName); err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, tag.ErrNotFound) {
+				code = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+		base.Tags = []string{tagName}
+	}
+	if err := parseListFilters(r.URL.Query(), &base); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	wantToVisitProps, err := s.propRepo.List(property.ListOptions{VisitStatus: property.VisitStatusWantToVisit})
+
+	// Load the active houses once; the filter menus offer the values they
+	// actually have, and each tab is a slice of them.
+	everything, err := s.propRepo.List(property.ListOptions{})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error loading properties: %v", err), http.StatusInternalServerError)
 		return
 	}
-	visitedProps, err := s.propRepo.List(property.ListOptions{VisitStatus: property.VisitStatusVisited})
+	var types, statuses, cities []string
+	scored := false
+	for _, p := range everything {
+		types = appendChoice(types, p.PropertyType)
+		statuses = appendChoice(statuses, p.Status)
+		city := p.City()
+		cities = appendChoice(cities, &city)
+		scored = scored || p.Score != nil
+	}
+
+	allProps, err := property.Filter(everything, base)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error loading properties: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	var wantToVisitProps, visitedProps []*property.Property
+	for _, p := range allProps {
+		switch p.VisitStatus {
+		case property.VisitStatusWantToVisit:
+			wantToVisitProps = append(wantToVisitProps, p)
+		case property.VisitStatusVisited:
+			visitedProps = append(visitedProps, p)
+		}
+	}
 
 	var props []*property.Property
 	switch tab {
@@ -67,18 +135,69 @@ func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
 		props = allProps
 	}
 
+	filter := url.Values{}
+	for _, name := range listFilterParams {
+		if v := strings.TrimSpace(r.URL.Query().Get(name)); v != "" {
+			filter.Set(name, v)
+		}
+	}
+	filtered := len(filter) > 0
+	if filter.Has("sort") {
+		filtered = len(filter) > 1
+	}
+	query := url.Values{}
+	for k, v := range filter {
+		query[k] = v
+	}
+	if tagName != "" {
+		query.Set("tag", tagName)
+	}
+
 	email, sessionErr := s.sessions.Validate(r)
 	isAdmin := sessionErr == nil && s.users.IsAdmin(email)
 	s.render(w, "list.html", listData{
 		Properties:     props,
 		IsAdmin:        isAdmin,
 		Tab:            tab,
+		Tag:            tagName,
+		Scored:         scored,
 		AllCnt:         len(allProps),
 		WantToVisitCnt: len(wantToVisitProps),
 		VisitedCnt:     len(visitedProps),
+		Filter:         filter,
+		FilterQuery:    template.URL(query.Encode()),
+		Filtered:       filtered,
+		Types:          types,
+		Statuses:       statuses,
+		Cities:         cities,
+		SortOptions:    sortOptions(),
 	})
 }
 
+// appendChoice adds v to a sorted list of distinct filter choices.
+func appendChoice(choices []string, v *string) []string {
+	if v == nil || *v == "" {
+		return choices
+	}
+	i, found := slices.BinarySearch(choices, *v)
+	if found {
+		return choices
+	}
+	return slices.Insert(choices, i, *v)
+}
+
+// sortOptions lists every sort key both ways.
+func sortOptions() []sortOption {
+	var opts []sortOption
+	for _, key := range property.SortKeys() {
+		label := strings.ReplaceAll(key, "_", " ")
+		opts = append(opts,
+			sortOption{Value: key + ":asc", Label: label + " ↑"},
+			sortOption{Value: key + ":desc", Label: label + " ↓"})
+	}
+	return opts
+}
+
 // handleDetail renders the property detail page.
 func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
 	id, err := parsePropertyID(r.URL.Path, "")
@@ -101,7 +220,34 @@ func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
 
 	detailEmail, detailSessionErr := s.sessions.Validate(r)
 	detailIsAdmin := detailSessionErr == nil && s.users.IsAdmin(detailEmail)
-	s.render(w, "detail.html", detailData{Property: prop, Comments: comments, IsAdmin: detailIsAdmin})
+
+	financing, err := s.financeRepo.Get(detailEmail)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error loading financing profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	var cost *finance.Cost
+	if prop.Price != nil {
+		c := monthlyCost(financing, prop)
+		cost = &c
+	}
+
+	dups, err := s.propRepo.DuplicatesOf(prop)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error checking duplicates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.render(w, "detail.html", detailData{
+		Property:   prop,
+		Comments:   comments,
+		Photos:     s.photoLinks(prop),
+		IsAdmin:    detailIsAdmin,
+		User:       detailEmail,
+		Financing:  financing,
+		Cost:       cost,
+		Duplicates: dups,
+	})
 }
 
 // handleCommentPost adds a comment via HTMX or form POST.
@@ -180,7 +326,11 @@ func (s *Server) handleRatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.propRepo.UpdateRating(id, rating); err != nil {
+	rater, sessionErr := s.sessions.Validate(r)
+	if sessionErr != nil {
+		rater = ""
+	}
+	if err := s.propRepo.UpdateRating(id, rater, rating); err != nil {
 		http.Error(w, fmt.Sprintf("Error updating rating: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -192,7 +342,7 @@ func (s *Server) handleRatePost(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Error loading property", http.StatusInternalServerError)
 			return
 		}
-		s.renderPartial(w, "rating-partial", detailData{Property: prop})
+		s.renderPartial(w, "rating-partial", detailData{Property: prop, User: rater})
 		return
 	}
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/evcraddock/house-finder/internal/auth"
 	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/property"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -58,7 +59,7 @@ func TestHandleListHasAddForm(t *testing.T) {
 	if !strings.Contains(body, "add-property-form") {
 		t.Error("expected add property form")
 	}
-	if !strings.Contains(body, "Enter address or MLS ID") {
+	if !strings.Contains(body, "Enter address, realtor.com URL, or MLS ID") {
 		t.Error("expected address input placeholder")
 	}
 }
@@ -79,6 +80,63 @@ func TestHandleListWithProperties(t *testing.T) {
 	}
 }
 
+func TestHandleListFilters(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "123 Main St, Edmond, OK 73034", "M-FILTER-1")
+	insertTestProperty(t, d, "456 Oak Ave, Norman, OK 73069", "M-FILTER-2")
+	if _, err := d.Exec("UPDATE properties SET price = 400000 WHERE id = 2"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/?tab=visited&max_price=300000&sort=price:asc", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"No properties match these filters.",
+		`href="/?tab=all&max_price=300000&amp;sort=price%3Aasc" class="tab">All (1)</a>`,
+		`name="max_price" value="300000"`,
+		`<option value="price:asc" selected>`,
+		`<option value="Norman">Norman</option>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in response", want)
+		}
+	}
+
+	r = httptest.NewRequest("GET", "/?city=norman", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	body = w.Body.String()
+	if !strings.Contains(body, "456 Oak Ave") || strings.Contains(body, "123 Main St") {
+		t.Error("expected only the Norman house")
+	}
+
+	// Tabs and their counts are slices of the same filtered houses.
+	if _, err := d.Exec("UPDATE properties SET visit_status = 'visited' WHERE id = 2"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	r = httptest.NewRequest("GET", "/?tab=visited&city=norman", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	body = w.Body.String()
+	for _, want := range []string{"456 Oak Ave", ">All (1)</a>", ">Want to Visit (0)</a>", ">Visited (1)</a>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in visited tab", want)
+		}
+	}
+
+	r = httptest.NewRequest("GET", "/?min_beds=many", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad filter status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleDetail(t *testing.T) {
 	srv, d := testServerWithDB(t)
 	insertTestProperty(t, d, "456 Oak Ave", "M-DETAIL-1")
@@ -97,6 +155,56 @@ func TestHandleDetail(t *testing.T) {
 	if !strings.Contains(body, "Add Comment") {
 		t.Error("expected comment form")
 	}
+	if !strings.Contains(body, "Edit details") {
+		t.Error("expected edit form")
+	}
+}
+
+func TestHandleDetailShowsMemberRatings(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "456 Oak Ave", "M-RATINGS-1")
+
+	repo := property.NewRepository(d)
+	for email, rating := range map[string]int{"ann@example.com": 4, "ben@example.com": 2} {
+		if err := repo.UpdateRating(1, email, rating); err != nil {
+			t.Fatalf("rate: %v", err)
+		}
+	}
+
+	for _, path := range []string{"/property/1", "/"} {
+		r := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		for _, want := range []string{"ann", "ben", "3.0", "split"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: expected %q in response", path, want)
+			}
+		}
+	}
+}
+
+func TestHandleDetailMarksEditedFields(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "456 Oak Ave", "M-EDITED-1")
+
+	sqft := "1850"
+	if err := property.NewRepository(d).ApplyEdit(1, property.Edit{"sqft": &sqft}); err != nil {
+		t.Fatalf("apply edit: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/property/1", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	if !strings.Contains(body, "1,850") {
+		t.Error("expected overridden sqft in response")
+	}
+	if !strings.Contains(body, "edited-badge") {
+		t.Error("expected edited marker")
+	}
 }
 
 func TestHandleDetailNotFound(t *testing.T) {
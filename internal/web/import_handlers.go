@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// maxImportRows caps one batch request. The CLI splits longer lists so
+// each request finishes well within client timeouts.
+const maxImportRows = 100
+
+// importResponse is the per-row report for POST /api/properties/batch.
+type importResponse struct {
+	Added   int                     `json:"added"`
+	Skipped int                     `json:"skipped"`
+	Failed  int                     `json:"failed"`
+	Results []property.ImportResult `json:"results"`
+}
+
+// apiImportProperties adds many properties by address in one request.
+// Rows are looked up with bounded concurrency; houses already tracked are
+// skipped, and each row's outcome is reported rather than failing the batch.
+func (s *Server) apiImportProperties(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rows    []property.ImportRow `json:"rows"`
+		NoCache bool                 `json:"no_cache"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	if s.propService == nil {
+		apiError(w, "property add not available (no listing provider configured)", http.StatusServiceUnavailable)
+		return
+	}
+	if len(req.Rows) == 0 {
+		apiError(w, "rows is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Rows) > maxImportRows {
+		apiError(w, fmt.Sprintf("at most %d rows per batch, got %d", maxImportRows, len(req.Rows)), http.StatusBadRequest)
+		return
+	}
+
+	user := auth.UserEmailFromContext(r)
+	opts := s.importOpts
+	opts.NoCache = req.NoCache
+	opts.Rater = user
+	results, err := s.propService.Import(r.Context(), req.Rows, opts)
+	if err != nil {
+		apiError(w, fmt.Sprintf("importing properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resp := importResponse{Results: results}
+	for i := range results {
+		res := &results[i]
+		switch res.Status {
+		case property.ImportAdded:
+			resp.Added++
+			slog.Info("property added", "id", res.Property.ID, "address", res.Property.Address, "user", user, "batch_row", res.Row)
+			s.prefetchPhotos(res.Property)
+			if text := strings.TrimSpace(req.Rows[i].Comment); text != "" && res.Error == "" {
+				if _, err := s.commentRepo.Add(res.Property.ID, text, user); err != nil {
+					res.Error = fmt.Sprintf("adding comment: %v", err)
+				}
+			}
+		case property.ImportSkipped:
+			resp.Skipped++
+		default:
+			resp.Failed++
+		}
+	}
+
+	slog.Info("batch import finished", "added", resp.Added, "skipped", resp.Skipped, "failed", resp.Failed, "user", user)
+	apiJSON(w, resp, http.StatusOK)
+}
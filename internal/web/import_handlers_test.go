@@ -0,0 +1,102 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPIImportProperties(t *testing.T) {
+	provider, err := mls.NewFileProvider(filepath.Join("..", "mls", "testdata"))
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, _, token := testAPIServerWithProvider(t, provider)
+	srv.importOpts = property.ImportOptions{Interval: time.Millisecond}
+
+	body := map[string]interface{}{
+		"rows": []map[string]interface{}{
+			{"address": "123 Main St, Yukon, OK 73099", "rating": 4, "comment": "from the realtor's list"},
+			{"address": "456 Oak Ave, Mustang, OK 73064", "visit_status": "want_to_visit"},
+			{"address": "123 Main Street, Yukon, OK"},
+			{"address": "1 Nowhere Rd"},
+		},
+	}
+	w := apiRequest(t, srv, "POST", "/api/properties/batch", token, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp importResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Added != 2 || resp.Skipped != 1 || resp.Failed != 1 {
+		t.Fatalf("added/skipped/failed = %d/%d/%d, want 2/1/1: %+v", resp.Added, resp.Skipped, resp.Failed, resp.Results)
+	}
+
+	first := resp.Results[0].Property
+	w = apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/comments", first.ID), token, nil)
+	if !strings.Contains(w.Body.String(), "from the realtor's list") {
+		t.Errorf("comments = %s, want the imported comment", w.Body.String())
+	}
+	if first.Rating == nil || *first.Rating != 4 {
+		t.Errorf("rating = %v, want 4", first.Rating)
+	}
+
+	// Importing the same list again adds nothing.
+	w = apiRequest(t, srv, "POST", "/api/properties/batch", token, body)
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Added != 0 || resp.Skipped != 3 {
+		t.Errorf("second import added/skipped = %d/%d, want 0/3", resp.Added, resp.Skipped)
+	}
+}
+
+func TestAPIImportPropertiesErrors(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	w := apiRequest(t, srv, "POST", "/api/properties/batch", token, map[string]interface{}{
+		"rows": []map[string]string{{"address": "1 Main St"}},
+	})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without provider, status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	provider, err := mls.NewFileProvider(filepath.Join("..", "mls", "testdata"))
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, _, token = testAPIServerWithProvider(t, provider)
+
+	tooMany := make([]map[string]string, maxImportRows+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]string{"address": fmt.Sprintf("%d Main St", i)}
+	}
+	tests := []struct {
+		name       string
+		method     string
+		body       interface{}
+		wantStatus int
+	}{
+		{"no rows", "POST", map[string]interface{}{"rows": []string{}}, http.StatusBadRequest},
+		{"too many rows", "POST", map[string]interface{}{"rows": tooMany}, http.StatusBadRequest},
+		{"wrong method", "GET", nil, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, "/api/properties/batch", token, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/jobs"
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+// Background jobs run by ListenAndServe. Intervals are set with
+// SetJobInterval; a zero interval disables a job.
+const (
+	// JobCleanup removes expired magic-link tokens and sessions.
+	JobCleanup = "cleanup"
+
+	// JobListingRefresh re-fetches active listings, records change events
+	// and emails them. Each run costs one RapidAPI call per listing.
+	JobListingRefresh = "listing-refresh"
+
+	// JobTrashPurge permanently removes properties that have been in the
+	// trash longer than the retention period; see SetTrashRetention.
+	JobTrashPurge = "trash-purge"
+
+	// JobDigest emails listing events not yet sent, such as those found
+	// by a manual refresh, without re-fetching anything.
+	JobDigest = "digest"
+)
+
+// Default intervals of the jobs that are on unless configured otherwise.
+// The listing jobs only default on when alerts can be emailed.
+const (
+	defaultCleanupInterval = time.Hour
+	defaultPurgeInterval   = 24 * time.Hour
+	defaultRefreshInterval = 24 * time.Hour
+	defaultDigestInterval  = time.Hour
+)
+
+// jobShutdownGrace is how long shutdown waits for running jobs to stop.
+const jobShutdownGrace = 10 * time.Second
+
+// registerJobs adds the server's background jobs to s.jobs. Cleanup and
+// trash purge are on by default. The listing jobs need a provider that can
+// refresh, and are on by default when SMTP is configured to send alerts.
+func (s *Server) registerJobs(tokens *auth.TokenStore) error {
+	all := []jobs.Job{{
+		Name:     JobCleanup,
+		Interval: defaultCleanupInterval,
+		Run: func(ctx context.Context) error {
+			if err := tokens.Cleanup(); err != nil {
+				return err
+			}
+			return s.sessions.Cleanup()
+		},
+	}, {
+		Name:     JobTrashPurge,
+		Interval: defaultPurgeInterval,
+		Run: func(ctx context.Context) error {
+			return s.purgeTrash()
+		},
+	}}
+
+	if s.watcher != nil {
+		refresh, digest := jobs.Job{
+			Name: JobListingRefresh,
+			Run: func(ctx context.Context) error {
+				return s.watcher.Check(mls.WithCaller(ctx, "watcher"))
+			},
+		}, jobs.Job{
+			Name: JobDigest,
+			Run: func(ctx context.Context) error {
+				return s.watcher.Notify()
+			},
+		}
+		if s.smtpCfg.IsConfigured() {
+			refresh.Interval = defaultRefreshInterval
+			digest.Interval = defaultDigestInterval
+		}
+		all = append(all, refresh, digest)
+	}
+
+	for _, job := range all {
+		if err := s.jobs.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetJobInterval sets how often a background job runs; zero disables it.
+// The listing jobs are skipped with a warning when no listing provider
+// supports refresh.
+func (s *Server) SetJobInterval(name string, interval time.Duration) error {
+	if s.watcher == nil && (name == JobListingRefresh || name == JobDigest) {
+		if interval > 0 {
+			slog.Warn("job not available without a listing provider that supports refresh", "job", name)
+		}
+		return nil
+	}
+	if err := s.jobs.SetInterval(name, interval); err != nil {
+		return fmt.Errorf("scheduling job: %w", err)
+	}
+	return nil
+}
+
+// runJobs starts the scheduler and returns a function that stops it and
+// waits, up to jobShutdownGrace, for running jobs to return. Calling stop
+// again does nothing.
+func (s *Server) runJobs() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Run(ctx)
+		close(done)
+	}()
+
+	return sync.OnceFunc(func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(jobShutdownGrace):
+			slog.Warn("background jobs still running at shutdown")
+		}
+	})
+}
+
+// handleAPIJobs handles GET /api/admin/jobs. It lists each background
+// job's interval, next run and last outcome.
+func (s *Server) handleAPIJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	statuses, err := s.jobs.Status()
+	if err != nil {
+		apiError(w, fmt.Sprintf("reading job status: %v", err), http.StatusInternalServerError)
+		return
+	}
+	apiJSON(w, statuses, http.StatusOK)
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/db"
+	"github.com/evcraddock/house-finder/internal/jobs"
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestAPIJobs(t *testing.T) {
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, _, token := testAPIServerWithProvider(t, provider)
+
+	if err := srv.SetJobInterval(JobListingRefresh, 24*time.Hour); err != nil {
+		t.Fatalf("set interval: %v", err)
+	}
+	if err := srv.SetJobInterval("nope", time.Hour); err == nil {
+		t.Error("expected error for unknown job")
+	}
+
+	w := apiRequest(t, srv, "GET", "/api/admin/jobs", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var statuses []jobs.Status
+	if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	want := []struct {
+		name     string
+		interval string
+	}{
+		{JobCleanup, "1h0m0s"},
+		{JobTrashPurge, "24h0m0s"},
+		{JobListingRefresh, "24h0m0s"},
+		{JobDigest, ""},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d jobs, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for i, tt := range want {
+		st := statuses[i]
+		if st.Name != tt.name || st.Interval != tt.interval || st.Enabled != (tt.interval != "") {
+			t.Errorf("jobs[%d] = %+v, want %s every %q", i, st, tt.name, tt.interval)
+		}
+		if st.Runs != 0 || st.LastStarted != nil {
+			t.Errorf("%s has run before the server started: %+v", st.Name, st)
+		}
+	}
+
+	w = apiRequest(t, srv, "POST", "/api/admin/jobs", token, nil)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAPIJobsWithoutProvider(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	// Listing jobs are skipped, not an error, when nothing can refresh.
+	if err := srv.SetJobInterval(JobListingRefresh, time.Hour); err != nil {
+		t.Errorf("set listing-refresh interval: %v", err)
+	}
+
+	w := apiRequest(t, srv, "GET", "/api/admin/jobs", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var statuses []jobs.Status
+	if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != JobCleanup || statuses[1].Name != JobTrashPurge {
+		t.Errorf("jobs = %+v, want only cleanup and trash-purge", statuses)
+	}
+
+	userKey, _, err := srv.apiKeys.Create("user", "user@example.com")
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	w = apiRequest(t, srv, "GET", "/api/admin/jobs", userKey, nil)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("non-admin status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestListingJobsDefaultOnWithSMTP(t *testing.T) {
+	provider, err := mls.NewFileProvider("../mls/testdata")
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	d, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	cfg := auth.Config{DevMode: true, SMTPHost: "smtp.example.com", SMTPFrom: "hf@example.com"}
+	srv, err := NewServer(d, cfg, provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	intervals := func() map[string]string {
+		t.Helper()
+		statuses, err := srv.jobs.Status()
+		if err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		got := make(map[string]string)
+		for _, st := range statuses {
+			got[st.Name] = st.Interval
+		}
+		return got
+	}
+	got := intervals()
+	if got[JobListingRefresh] != "24h0m0s" || got[JobDigest] != "1h0m0s" {
+		t.Errorf("listing-refresh every %q, digest every %q; want 24h and 1h", got[JobListingRefresh], got[JobDigest])
+	}
+
+	// The environment can still turn them off.
+	if err := srv.SetJobInterval(JobListingRefresh, 0); err != nil {
+		t.Fatalf("set interval: %v", err)
+	}
+	if got := intervals(); got[JobListingRefresh] != "" || got[JobDigest] != "1h0m0s" {
+		t.Errorf("after disabling refresh: %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/media"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// photoLink is a listing photo with the URLs the UI should load it from.
+// With a photo cache configured, Src and Thumb point at /media/ so the
+// photos outlive the listing; otherwise they hotlink the MLS URL.
+type photoLink struct {
+	Index int      `json:"index"`
+	URL   string   `json:"url"`
+	Tags  []string `json:"tags,omitempty"`
+	Src   string   `json:"src"`
+	Thumb string   `json:"thumb"`
+}
+
+// CachePhotos serves listing photos from store instead of hotlinking
+// realtor.com, downloading each photo the first time it's needed.
+func (s *Server) CachePhotos(store *media.Store) {
+	s.media = store
+}
+
+// photoLinks returns the gallery entries for a property.
+func (s *Server) photoLinks(p *property.Property) []photoLink {
+	photos := p.Photos()
+	links := make([]photoLink, len(photos))
+	for i, ph := range photos {
+		links[i] = photoLink{Index: i, URL: ph.URL, Tags: ph.Tags, Src: ph.URL, Thumb: ph.URL}
+		if s.media != nil {
+			links[i].Src = fmt.Sprintf("/media/%d/%d", p.ID, i)
+			links[i].Thumb = fmt.Sprintf("/media/%d/%d/thumb", p.ID, i)
+		}
+	}
+	return links
+}
+
+// prefetchPhotos downloads a property's photos in the background so they
+// are on disk before the listing can disappear.
+func (s *Server) prefetchPhotos(p *property.Property) {
+	if s.media == nil {
+		return
+	}
+	photos := p.Photos()
+	if len(photos) == 0 {
+		return
+	}
+	urls := make([]string, len(photos))
+	for i, ph := range photos {
+		urls[i] = ph.URL
+	}
+	go func() {
+		if n := s.media.Prefetch(urls); n > 0 {
+			slog.Info("photos cached", "id", p.ID, "count", n)
+		}
+	}()
+}
+
+// apiListPhotos returns every listing photo for a property.
+func (s *Server) apiListPhotos(w http.ResponseWriter, id int64) {
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+	apiJSON(w, s.photoLinks(p), http.StatusOK)
+}
+
+// handleMedia serves cached photos: /media/{id}/{index} for the full-size
+// photo and /media/{id}/{index}/thumb for a thumbnail.
+func (s *Server) handleMedia(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.media == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/media/"), "/")
+	thumb := len(parts) == 3 && parts[2] == "thumb"
+	if len(parts) != 2 && !thumb {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	photos := p.Photos()
+	if index < 0 || index >= len(photos) {
+		http.NotFound(w, r)
+		return
+	}
+
+	var path string
+	if thumb {
+		path, err = s.media.Thumbnail(photos[index].URL)
+	} else {
+		path, err = s.media.Original(photos[index].URL)
+	}
+	if err != nil {
+		slog.Warn("photo unavailable", "id", id, "index", index, "err", err)
+		http.Error(w, "Photo unavailable", http.StatusBadGateway)
+		return
+	}
+
+	// Cached files never change for a given URL, so browsers can keep them.
+	w.Header().Set("Cache-Control", "private, max-age=604800")
+	http.ServeFile(w, r, path)
+}
@@ -0,0 +1,160 @@
+package web
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/media"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// photoHost serves a 640x480 PNG at /{name}.png.
+func photoHost(t *testing.T) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ".png") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buf.Bytes()) //nolint:errcheck // test server
+	}))
+	t.Cleanup(host.Close)
+	return host
+}
+
+func insertPhotoProperty(t *testing.T, d *sql.DB, photoBase string) int64 {
+	t.Helper()
+	raw := fmt.Sprintf(`{"data":{"photos":[
+		{"href":"%[1]s/front.png","tags":[{"label":"house_view"}]},
+		{"href":"%[1]s/kitchen.png","tags":[{"label":"kitchen"}]}
+	]}}`, photoBase)
+	p, err := property.NewRepository(d).Insert(&property.Property{
+		Address:    "9 Gallery Ln",
+		MprID:      "M9999999999",
+		RealtorURL: "https://realtor.com/test",
+		RawJSON:    json.RawMessage(raw),
+	})
+	if err != nil {
+		t.Fatalf("insert property: %v", err)
+	}
+	return p.ID
+}
+
+func TestAPIListPhotos(t *testing.T) {
+	host := photoHost(t)
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertPhotoProperty(t, d, host.URL)
+
+	var photos []photoLink
+	w := apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/photos", id), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&photos); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(photos) != 2 {
+		t.Fatalf("got %d photos, want 2", len(photos))
+	}
+	if photos[1].Tags[0] != "kitchen" {
+		t.Errorf("photos[1].Tags = %v, want [kitchen]", photos[1].Tags)
+	}
+	if photos[0].Src != host.URL+"/front.png" {
+		t.Errorf("without a cache, src = %q, want the MLS URL", photos[0].Src)
+	}
+
+	store, err := media.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	srv.CachePhotos(store)
+
+	w = apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/photos", id), token, nil)
+	if err := json.NewDecoder(w.Body).Decode(&photos); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if want := fmt.Sprintf("/media/%d/1/thumb", id); photos[1].Thumb != want {
+		t.Errorf("thumb = %q, want %q", photos[1].Thumb, want)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/properties/99999/photos", token, nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing property status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleMedia(t *testing.T) {
+	host := photoHost(t)
+	srv, d := testServerWithDB(t)
+	id := insertPhotoProperty(t, d, host.URL)
+
+	store, err := media.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	srv.CachePhotos(store)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantType   string
+	}{
+		{"full size", fmt.Sprintf("/media/%d/0", id), http.StatusOK, "image/png"},
+		{"thumbnail", fmt.Sprintf("/media/%d/1/thumb", id), http.StatusOK, "image/jpeg"},
+		{"index out of range", fmt.Sprintf("/media/%d/2", id), http.StatusNotFound, ""},
+		{"unknown property", "/media/99999/0", http.StatusNotFound, ""},
+		{"bad path", fmt.Sprintf("/media/%d/0/large", id), http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && w.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("content type = %q, want %q", w.Header().Get("Content-Type"), tt.wantType)
+			}
+		})
+	}
+
+	// Photos stay available once cached, even if the MLS host goes away.
+	host.Close()
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/media/%d/0", id), nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("after host closed, status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandleDetailShowsGallery(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	id := insertPhotoProperty(t, d, "https://photos.example.com")
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/property/%d", id), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Photos (2)") {
+		t.Error("expected gallery heading with photo count")
+	}
+	if !strings.Contains(body, "https://photos.example.com/kitchen.png") {
+		t.Error("expected kitchen photo in gallery")
+	}
+}
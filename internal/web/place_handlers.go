@@ -0,0 +1,95 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/place"
+)
+
+// handleAPIPlaces handles /api/places and /api/places/{name}.
+// GET lists places, POST adds one, DELETE /api/places/{name} removes one.
+func (s *Server) handleAPIPlaces(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/places"), "/")
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.apiListPlaces(w)
+		case http.MethodPost:
+			s.apiAddPlace(w, r)
+		default:
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	s.apiDeletePlace(w, r, name)
+}
+
+// apiListPlaces returns the household's places.
+func (s *Server) apiListPlaces(w http.ResponseWriter) {
+	places, err := s.placeRepo.List()
+	if err != nil {
+		apiError(w, fmt.Sprintf("listing places: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if places == nil {
+		places = make([]*place.Place, 0)
+	}
+	apiJSON(w, places, http.StatusOK)
+}
+
+// apiAddPlace saves a named place.
+func (s *Server) apiAddPlace(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name      string   `json:"name"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.Latitude == nil || req.Longitude == nil {
+		apiError(w, "latitude and longitude are required", http.StatusBadRequest)
+		return
+	}
+
+	p, err := s.placeRepo.Add(req.Name, *req.Latitude, *req.Longitude)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("place added", "name", p.Name, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, p, http.StatusCreated)
+}
+
+// apiDeletePlace removes a place by name.
+func (s *Server) apiDeletePlace(w http.ResponseWriter, r *http.Request, name string) {
+	if err := s.placeRepo.Delete(name); err != nil {
+		if errors.Is(err, place.ErrNotFound) {
+			apiError(w, "place not found", http.StatusNotFound)
+			return
+		}
+		apiError(w, fmt.Sprintf("deleting place: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("place deleted", "name", name, "user", auth.UserEmailFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
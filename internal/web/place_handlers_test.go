@@ -0,0 +1,152 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/place"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPIPlaces(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	w := apiRequest(t, srv, "GET", "/api/places", token, nil)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Fatalf("empty list = %d %s, want 200 []", w.Code, w.Body.String())
+	}
+
+	body := map[string]interface{}{"name": "mom's house", "latitude": 35.65, "longitude": -97.48}
+	w = apiRequest(t, srv, "POST", "/api/places", token, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add status = %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"duplicate", body},
+		{"missing coordinates", map[string]interface{}{"name": "work"}},
+		{"bad latitude", map[string]interface{}{"name": "work", "latitude": 95, "longitude": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "POST", "/api/places", token, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	var places []*place.Place
+	w = apiRequest(t, srv, "GET", "/api/places", token, nil)
+	if err := json.NewDecoder(w.Body).Decode(&places); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(places) != 1 || places[0].Name != "mom's house" {
+		t.Fatalf("places = %+v", places)
+	}
+
+	w = apiRequest(t, srv, "DELETE", "/api/places/mom%27s%20house", token, nil)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d: %s", w.Code, w.Body.String())
+	}
+	w = apiRequest(t, srv, "DELETE", "/api/places/mom%27s%20house", token, nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAPIListPropertiesWithMaxDistance(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+
+	near, far := 35.48, 35.5067
+	repo := property.NewRepository(d)
+	for i, lat := range []float64{near, far} {
+		lat, lon := lat, -97.53
+		if i == 1 {
+			lon = -97.7625
+		}
+		if _, err := repo.Insert(&property.Property{
+			Address:    fmt.Sprintf("%d Commute St", i),
+			MprID:      fmt.Sprintf("M-COMMUTE-%d", i),
+			RealtorURL: "https://realtor.com/test",
+			RawJSON:    json.RawMessage(`{}`),
+			Latitude:   &lat,
+			Longitude:  &lon,
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	body := map[string]interface{}{"name": "work", "latitude": 35.4676, "longitude": -97.5164}
+	if w := apiRequest(t, srv, "POST", "/api/places", token, body); w.Code != http.StatusCreated {
+		t.Fatalf("add place: %d %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{"no filter", "", http.StatusOK, 2},
+		{"within 5 miles", "?max_distance=work%3D5mi", http.StatusOK, 1},
+		{"within 30 km", "?max_distance=work%3D30km", http.StatusOK, 2},
+		{"invalid limit", "?max_distance=work", http.StatusBadRequest, 0},
+		{"unknown place", "?max_distance=gym%3D5mi", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "GET", "/api/properties"+tt.query, token, nil)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var props []*property.Property
+			if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(props) != tt.wantCount {
+				t.Errorf("got %d properties, want %d", len(props), tt.wantCount)
+			}
+			if len(props) > 0 && len(props[0].Distances) != 1 {
+				t.Errorf("distances = %+v, want one", props[0].Distances)
+			}
+		})
+	}
+}
+
+func TestHandleListShowsDistances(t *testing.T) {
+	srv, d := testServerWithDB(t)
+
+	lat, lon := 35.48, -97.53
+	if _, err := property.NewRepository(d).Insert(&property.Property{
+		Address:    "1 Commute St",
+		MprID:      "M-COMMUTE-WEB",
+		RealtorURL: "https://realtor.com/test",
+		RawJSON:    json.RawMessage(`{}`),
+		Latitude:   &lat,
+		Longitude:  &lon,
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if _, err := place.NewRepository(d).Add("work", 35.4676, -97.5164); err != nil {
+		t.Fatalf("add place: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "work 1.1 mi") {
+		t.Error("expected distance to work on the list page")
+	}
+}
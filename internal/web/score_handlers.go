@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/score"
+)
+
+// handleAPICriteria handles /api/criteria and /api/criteria/{name}. GET
+// lists the household's scoring criteria and POST adds one; PATCH changes
+// a criterion's weight or range and DELETE removes it.
+func (s *Server) handleAPICriteria(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/criteria"), "/")
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.apiListCriteria(w)
+		case http.MethodPost:
+			s.apiAddCriterion(w, r)
+		default:
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	switch r.Method {
+	case http.MethodPatch:
+		s.apiUpdateCriterion(w, r, name)
+	case http.MethodDelete:
+		s.apiDeleteCriterion(w, r, name)
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// apiListCriteria returns the scoring criteria, heaviest first.
+func (s *Server) apiListCriteria(w http.ResponseWriter) {
+	criteria, err := s.scoreRepo.List()
+	if err != nil {
+		apiError(w, fmt.Sprintf("listing criteria: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if criteria == nil {
+		criteria = make([]*score.Criterion, 0)
+	}
+	apiJSON(w, criteria, http.StatusOK)
+}
+
+// apiAddCriterion saves a new scoring criterion.
+func (s *Server) apiAddCriterion(w http.ResponseWriter, r *http.Request) {
+	var c score.Criterion
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	saved, err := s.scoreRepo.Add(&c)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("criterion added", "name", saved.Name, "field", saved.Field, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, saved, http.StatusCreated)
+}
+
+// apiUpdateCriterion changes a criterion's weight, best or worst value.
+func (s *Server) apiUpdateCriterion(w http.ResponseWriter, r *http.Request, name string) {
+	var u score.Update
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	c, err := s.scoreRepo.Update(name, u)
+	if errors.Is(err, score.ErrNotFound) {
+		apiError(w, "criterion not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("criterion updated", "name", c.Name, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, c, http.StatusOK)
+}
+
+// apiDeleteCriterion removes a criterion and its hand scores.
+func (s *Server) apiDeleteCriterion(w http.ResponseWriter, r *http.Request, name string) {
+	if err := s.scoreRepo.Delete(name); err != nil {
+		if errors.Is(err, score.ErrNotFound) {
+			apiError(w, "criterion not found", http.StatusNotFound)
+			return
+		}
+		apiError(w, fmt.Sprintf("deleting criterion: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("criterion deleted", "name", name, "user", auth.UserEmailFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiPropertyScore returns a property's score and its breakdown by
+// criterion. With no criteria defined the breakdown is empty.
+func (s *Server) apiPropertyScore(w http.ResponseWriter, id int64) {
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+	sc := p.Score
+	if sc == nil {
+		sc = &score.Score{Parts: make([]score.Part, 0)}
+	}
+	apiJSON(w, sc, http.StatusOK)
+}
+
+// apiSetPropertyScores records hand scores (JSON: {"scores": {"kitchen":
+// 4}}, 0 clears one) and responds with the property's updated score.
+func (s *Server) apiSetPropertyScores(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, err := s.propRepo.GetByID(id); err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Scores map[string]int `json:"scores"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Scores) == 0 {
+		apiError(w, "scores is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.scoreRepo.SetScores(id, req.Scores); err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("property scored", "id", id, "scores", req.Scores, "user", auth.UserEmailFromContext(r))
+	s.apiPropertyScore(w, id)
+}
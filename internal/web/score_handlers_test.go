@@ -0,0 +1,106 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/score"
+)
+
+func TestAPICriteriaAndScores(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	low := insertAPITestProperty(t, d)
+	high := insertAPITestProperty(t, d)
+
+	w := apiRequest(t, srv, "POST", "/api/criteria", token, map[string]interface{}{"name": "kitchen", "weight": 2})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add status = %d: %s", w.Code, w.Body.String())
+	}
+	w = apiRequest(t, srv, "POST", "/api/criteria", token, map[string]interface{}{
+		"name": "sqft", "weight": 1, "field": "sqft", "best": 2500, "worst": 1500,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add computed status = %d: %s", w.Code, w.Body.String())
+	}
+	w = apiRequest(t, srv, "PATCH", "/api/criteria/kitchen", token, map[string]int{"weight": 3})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d: %s", w.Code, w.Body.String())
+	}
+
+	for id, kitchen := range map[int64]int{low: 2, high: 5} {
+		w := apiRequest(t, srv, "PUT", fmt.Sprintf("/api/properties/%d/score", id), token, map[string]map[string]int{"scores": {"kitchen": kitchen}})
+		if w.Code != http.StatusOK {
+			t.Fatalf("score %d status = %d: %s", id, w.Code, w.Body.String())
+		}
+	}
+
+	w = apiRequest(t, srv, "GET", fmt.Sprintf("/api/properties/%d/score", high), token, nil)
+	var s score.Score
+	if err := json.NewDecoder(w.Body).Decode(&s); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// Only the kitchen is known; the listing has no sqft.
+	if s.Total == nil || *s.Total != 100 || len(s.Parts) != 2 || s.Parts[0].Criterion != "kitchen" {
+		t.Errorf("score = %+v, want total 100 with kitchen first", s)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/properties?sort=score", token, nil)
+	var props []*property.Property
+	if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(props) != 2 || props[0].ID != high || props[0].Score == nil {
+		t.Errorf("sort=score listed %+v, want #%d first with its score", props, high)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"bad sort", "GET", "/api/properties?sort=pool", nil, http.StatusBadRequest},
+		{"duplicate criterion", "POST", "/api/criteria", map[string]interface{}{"name": "Kitchen", "weight": 1}, http.StatusBadRequest},
+		{"distance to unknown place", "POST", "/api/criteria", map[string]interface{}{"name": "commute", "weight": 1, "field": "distance", "place": "work", "best": 5, "worst": 30}, http.StatusBadRequest},
+		{"score computed criterion", "PUT", fmt.Sprintf("/api/properties/%d/score", low), map[string]map[string]int{"scores": {"sqft": 3}}, http.StatusBadRequest},
+		{"score out of range", "PUT", fmt.Sprintf("/api/properties/%d/score", low), map[string]map[string]int{"scores": {"kitchen": 9}}, http.StatusBadRequest},
+		{"score missing property", "GET", "/api/properties/9999/score", nil, http.StatusNotFound},
+		{"update missing criterion", "PATCH", "/api/criteria/nope", map[string]int{"weight": 2}, http.StatusNotFound},
+		{"delete criterion", "DELETE", "/api/criteria/sqft", nil, http.StatusNoContent},
+		{"delete missing criterion", "DELETE", "/api/criteria/sqft", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, tt.path, token, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleDetailShowsScore(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "456 Oak Ave", "M-SCORE-1")
+	if _, err := score.NewRepository(d).Add(&score.Criterion{Name: "big yard", Weight: 2}); err != nil {
+		t.Fatalf("add criterion: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/property/1", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, body)
+	}
+	if !strings.Contains(body, `id="score-section"`) || !strings.Contains(body, `data-criterion="big yard"`) {
+		t.Error("expected a score breakdown with a hand-score input")
+	}
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// handleAPISearch handles GET /api/search?q=basement. It responds with
+// the matching active properties, each with a snippet of the matching
+// text, matched words marked.
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		apiError(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	results, err := s.propRepo.Search(q)
+	if errors.Is(err, property.ErrEmptySearch) {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		apiError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if results == nil {
+		results = make([]*property.SearchResult, 0)
+	}
+	apiJSON(w, results, http.StatusOK)
+}
+
+// searchData is the data for the search-results partial.
+type searchData struct {
+	Query   string
+	Results []*property.SearchResult
+}
+
+// handleSearch renders the list page's search results as the user types.
+// A query with no words renders nothing, so clearing the box clears the
+// results.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	results, err := s.propRepo.Search(q)
+	if errors.Is(err, property.ErrEmptySearch) {
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error searching properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.renderPartial(w, "search-results", searchData{Query: q, Results: results})
+}
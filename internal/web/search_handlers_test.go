@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/comment"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPISearch(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+	insertAPITestProperty(t, d)
+	if _, err := comment.NewRepository(d).Add(id, "Big unfinished basement", "admin@example.com"); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+
+	w := apiRequest(t, srv, "GET", "/api/search?q=basement", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var results []*property.SearchResult
+	if err := json.NewDecoder(w.Body).Decode(&results); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 1 || results[0].Property.ID != id {
+		t.Fatalf("results = %+v, want only #%d", results, id)
+	}
+	var matched []string
+	for _, part := range results[0].Snippet {
+		if part.Match {
+			matched = append(matched, part.Text)
+		}
+	}
+	if len(matched) != 1 || matched[0] != "basement" {
+		t.Errorf("matched = %v, want [basement]", matched)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/search?q=attic", token, nil)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("no matches = %d %s, want 200 []", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"no query", "GET", "/api/search", http.StatusBadRequest},
+		{"no words", "GET", "/api/search?q=%21%3F", http.StatusBadRequest},
+		{"post not allowed", "POST", "/api/search?q=basement", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, tt.path, token, nil)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleSearch(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "123 Main St", "M-SEARCH-1")
+	insertTestProperty(t, d, "456 Oak Ave", "M-SEARCH-2")
+	if _, err := comment.NewRepository(d).Add(2, "<b>Damp</b> basement", ""); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/search?q=basem", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{"456 Oak Ave", "&lt;b&gt;Damp&lt;/b&gt; <mark>basement</mark>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in response: %s", want, body)
+		}
+	}
+	if strings.Contains(body, "123 Main St") {
+		t.Error("expected only matching houses")
+	}
+
+	r = httptest.NewRequest("GET", "/search?q=", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("empty query = %d %q, want 200 and nothing", w.Code, w.Body.String())
+	}
+}
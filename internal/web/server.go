@@ -16,12 +16,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/evcraddock/house-finder/internal/alert"
 	"github.com/evcraddock/house-finder/internal/auth"
 	"github.com/evcraddock/house-finder/internal/comment"
 	"github.com/evcraddock/house-finder/internal/email"
+	"github.com/evcraddock/house-finder/internal/finance"
+	"github.com/evcraddock/house-finder/internal/jobs"
 	"github.com/evcraddock/house-finder/internal/logging"
+	"github.com/evcraddock/house-finder/internal/media"
 	"github.com/evcraddock/house-finder/internal/mls"
+	"github.com/evcraddock/house-finder/internal/place"
 	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/score"
+	"github.com/evcraddock/house-finder/internal/tag"
 	"github.com/evcraddock/house-finder/internal/visit"
 )
 
@@ -33,33 +40,52 @@ var staticFS embed.FS
 
 // Server is the web UI HTTP server.
 type Server struct {
-	propRepo    *property.Repository
-	propService *property.Service
-	commentRepo *comment.Repository
-	visitRepo   *visit.Repository
-	sessions    *auth.SessionStore
-	passkeys    *auth.PasskeyStore
-	apiKeys     *auth.APIKeyStore
-	users       *auth.UserStore
-	smtpCfg     email.SMTPConfig
-	authCfg     auth.Config
-	templates   *template.Template
-	handler     http.Handler
+	propRepo       *property.Repository
+	propService    *property.Service
+	commentRepo    *comment.Repository
+	visitRepo      *visit.Repository
+	placeRepo      *place.Repository
+	tagRepo        *tag.Repository
+	scoreRepo      *score.Repository
+	financeRepo    *finance.Repository
+	eventRepo      *alert.Repository
+	watcher        *alert.Watcher
+	jobs           *jobs.Scheduler
+	trashRetention time.Duration
+	mlsCache       *mls.Cache
+	mlsUsage       *mls.Usage
+	media          *media.Store
+	importOpts     property.ImportOptions // batch add throttling; zero uses the defaults
+	sessions       *auth.SessionStore
+	passkeys       *auth.PasskeyStore
+	apiKeys        *auth.APIKeyStore
+	users          *auth.UserStore
+	smtpCfg        email.SMTPConfig
+	authCfg        auth.Config
+	templates      *template.Template
+	handler        http.Handler
 }
 
 // NewServer creates a web server with the given database and auth config.
-// mlsClient is optional — if nil, the POST /api/properties endpoint returns 503.
-func NewServer(db *sql.DB, authCfg auth.Config, mlsClient ...*mls.Client) (*Server, error) {
+// provider is optional — if nil, the POST /api/properties endpoint returns 503.
+func NewServer(db *sql.DB, authCfg auth.Config, provider ...mls.Provider) (*Server, error) {
 	funcMap := template.FuncMap{
-		"formatPrice":  tmplFormatPrice,
-		"formatFloat":  tmplFormatFloat,
-		"formatInt":    tmplFormatInt,
-		"formatStr":    tmplFormatStr,
-		"formatLot":    tmplFormatLot,
-		"formatRating": tmplFormatRating,
-		"derefRating":  tmplDerefRating,
-		"seq":          tmplSeq,
-		"ratingClass":  tmplRatingClass,
+		"formatPrice":   tmplFormatPrice,
+		"formatFloat":   tmplFormatFloat,
+		"formatInt":     tmplFormatInt,
+		"formatStr":     tmplFormatStr,
+		"formatLot":     tmplFormatLot,
+		"formatMiles":   tmplFormatMiles,
+		"formatDollars": tmplFormatDollars,
+		"formatRating":  tmplFormatRating,
+		"formatStars":   tmplFormatStars,
+		"raterName":     tmplRaterName,
+		"formatScore":   tmplFormatScore,
+		"formatShare":   tmplFormatShare,
+		"handScore":     tmplHandScore,
+		"derefRating":   tmplDerefRating,
+		"seq":           tmplSeq,
+		"ratingClass":   tmplRatingClass,
 	}
 
 	tmpl, err := template.New("").Funcs(funcMap).ParseFS(templateFS, "templates/*.html")
@@ -75,6 +101,11 @@ func NewServer(db *sql.DB, authCfg auth.Config, mlsClient ...*mls.Client) (*Serv
 	mailer := auth.NewMailer(authCfg)
 
 	propRepo := property.NewRepository(db)
+	if n, err := propRepo.BackfillCanonical(); err != nil {
+		return nil, fmt.Errorf("backfilling canonical addresses: %w", err)
+	} else if n > 0 {
+		slog.Info("canonical addresses backfilled", "count", n)
+	}
 
 	smtpCfg := email.SMTPConfig{
 		Host: authCfg.SMTPHost,
@@ -85,20 +116,38 @@ func NewServer(db *sql.DB, authCfg auth.Config, mlsClient ...*mls.Client) (*Serv
 	}
 
 	s := &Server{
-		propRepo:    propRepo,
-		commentRepo: comment.NewRepository(db),
-		visitRepo:   visit.NewRepository(db),
-		sessions:    sessions,
-		passkeys:    passkeys,
-		apiKeys:     apiKeys,
-		users:       users,
-		smtpCfg:     smtpCfg,
-		authCfg:     authCfg,
-		templates:   tmpl,
+		propRepo:       propRepo,
+		commentRepo:    comment.NewRepository(db),
+		visitRepo:      visit.NewRepository(db),
+		placeRepo:      place.NewRepository(db),
+		tagRepo:        tag.NewRepository(db),
+		scoreRepo:      score.NewRepository(db),
+		financeRepo:    finance.NewRepository(db),
+		eventRepo:      alert.NewRepository(db),
+		mlsUsage:       mls.NewUsage(db),
+		jobs:           jobs.NewScheduler(db),
+		trashRetention: DefaultTrashRetention,
+		sessions:       sessions,
+		passkeys:       passkeys,
+		apiKeys:        apiKeys,
+		users:          users,
+		smtpCfg:        smtpCfg,
+		authCfg:        authCfg,
+		templates:      tmpl,
+	}
+
+	if len(provider) > 0 && provider[0] != nil {
+		s.propService = property.NewService(propRepo, provider[0])
+		if cp, ok := provider[0].(*mls.CachedProvider); ok {
+			s.mlsCache = cp.Cache()
+		}
+		if s.propService.CanRefresh() {
+			s.watcher = alert.NewWatcher(propRepo, s.propService, s.eventRepo, s.notifyHousehold, authCfg.BaseURL)
+		}
 	}
 
-	if len(mlsClient) > 0 && mlsClient[0] != nil {
-		s.propService = property.NewService(propRepo, mlsClient[0])
+	if err := s.registerJobs(tokens); err != nil {
+		return nil, fmt.Errorf("registering jobs: %w", err)
 	}
 
 	mux := http.NewServeMux()
@@ -173,16 +222,35 @@ func NewServer(db *sql.DB, authCfg auth.Config, mlsClient ...*mls.Client) (*Serv
 	mux.HandleFunc("/api/properties", s.handleAPIProperties)
 	mux.HandleFunc("/api/properties/", s.handleAPIProperties)
 	mux.HandleFunc("/api/email", s.handleAPIEmail)
+	mux.HandleFunc("/api/events", s.handleAPIEvents)
+	mux.HandleFunc("/api/cache", s.handleAPICache)
+	mux.HandleFunc("/api/suggest", s.handleAPISuggest)
+	mux.HandleFunc("/api/places", s.handleAPIPlaces)
+	mux.HandleFunc("/api/places/", s.handleAPIPlaces)
+	mux.HandleFunc("/api/tags", s.handleAPITags)
+	mux.HandleFunc("/api/tags/", s.handleAPITags)
+	mux.HandleFunc("/api/criteria", s.handleAPICriteria)
+	mux.HandleFunc("/api/criteria/", s.handleAPICriteria)
+	mux.HandleFunc("/api/compare", s.handleAPICompare)
+	mux.HandleFunc("/api/search", s.handleAPISearch)
+	mux.HandleFunc("/api/financing", s.handleAPIFinancing)
+	mux.HandleFunc("/api/duplicates", s.handleAPIDuplicates)
+	mux.HandleFunc("/api/admin/reparse", s.handleAPIReparse)
+	mux.HandleFunc("/api/admin/usage", s.handleAPIUsage)
+	mux.HandleFunc("/api/admin/jobs", s.handleAPIJobs)
 
 	// Protected routes
 	mux.HandleFunc("/", s.handleList)
 	mux.HandleFunc("/property/", s.handlePropertyRoute)
+	mux.HandleFunc("/compare", s.handleCompare)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/media/", s.handleMedia)
 	mux.HandleFunc("/settings", s.handleSettings)
 	mux.HandleFunc("/settings/passkey/delete", s.handlePasskeyDelete)
 	mux.HandleFunc("/admin/users", s.handleAdminUsers)
 
 	// Wrap everything with auth middleware if admin email is configured
-	var h http.Handler = mux
+	var h http.Handler = tagCaller(mux)
 	if authCfg.AdminEmail != "" {
 		// Web routes: session auth. API routes: bearer token or session for management.
 		webAuth := auth.RequireAuth(sessions, h)
@@ -194,6 +262,15 @@ func NewServer(db *sql.DB, authCfg auth.Config, mlsClient ...*mls.Client) (*Serv
 	return s, nil
 }
 
+// tagCaller tags each request's context with the signed-in user, so the
+// listing calls it makes are metered against them. It runs inside the
+// auth middleware, which is what identifies the user.
+func tagCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(mls.WithCaller(r.Context(), auth.UserEmailFromContext(r))))
+	})
+}
+
 // ServeHTTP implements http.Handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.handler.ServeHTTP(w, r)
@@ -209,8 +286,12 @@ func (s *Server) ListenAndServe(port int) error {
 		"smtp", s.smtpCfg.IsConfigured(),
 		"dev_mode", s.authCfg.DevMode,
 		"mls", s.propService != nil,
+		"media", s.media != nil,
 	)
 
+	stopJobs := s.runJobs()
+	defer stopJobs()
+
 	srv := &http.Server{Addr: addr, Handler: s}
 
 	quit := make(chan os.Signal, 1)
@@ -226,6 +307,7 @@ func (s *Server) ListenAndServe(port int) error {
 		return err
 	case sig := <-quit:
 		slog.Info("shutting down", "signal", sig.String())
+		stopJobs()
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
 		return srv.Shutdown(ctx)
@@ -288,11 +370,56 @@ func tmplFormatLot(f *float64) string {
 	return fmt.Sprintf("%.2f acres", *f)
 }
 
+func tmplFormatMiles(miles float64) string {
+	return fmt.Sprintf("%.1f mi", miles)
+}
+
+func tmplFormatDollars(v int64) string {
+	return "$" + formatWithCommas(v)
+}
+
 func tmplFormatRating(r *int64) string {
 	if r == nil {
 		return "—"
 	}
-	return strings.Repeat("★", int(*r)) + strings.Repeat("☆", 4-int(*r))
+	return tmplFormatStars(*r)
+}
+
+func tmplFormatStars(r int64) string {
+	return strings.Repeat("★", int(r)) + strings.Repeat("☆", 4-int(r))
+}
+
+// tmplRaterName shortens a member's email to the part before the @. A
+// rating given before ratings were kept per member has no email.
+func tmplRaterName(email string) string {
+	if email == "" {
+		return "shared"
+	}
+	name, _, _ := strings.Cut(email, "@")
+	return name
+}
+
+func tmplFormatScore(total *float64) string {
+	if total == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%.0f", *total)
+}
+
+// tmplFormatShare formats a criterion's 0-1 score as a percentage.
+func tmplFormatShare(f *float64) string {
+	if f == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%.0f%%", *f*100)
+}
+
+// tmplHandScore returns a hand score for preselecting it, or 0 if unset.
+func tmplHandScore(v *float64) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
 }
 
 func tmplDerefRating(r *int64) int {
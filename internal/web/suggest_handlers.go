@@ -0,0 +1,56 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+// maxSuggestions caps the limit query param for /api/suggest.
+const maxSuggestions = 10
+
+// handleAPISuggest handles GET /api/suggest?q=...&limit=N (default 5).
+// It only calls the free geocoder, so it is safe to hit on every keystroke.
+func (s *Server) handleAPISuggest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.propService == nil {
+		apiError(w, "address suggestions not available (no listing provider configured)", http.StatusServiceUnavailable)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		apiError(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 5
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxSuggestions {
+			apiError(w, fmt.Sprintf("limit must be 1-%d", maxSuggestions), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	candidates, err := s.propService.Suggest(r.Context(), q, limit)
+	if err != nil {
+		slog.Warn("address suggest failed", "q", q, "err", err)
+		apiLookupError(w, "suggesting addresses", err)
+		return
+	}
+
+	if candidates == nil {
+		candidates = make([]mls.Candidate, 0)
+	}
+
+	apiJSON(w, candidates, http.StatusOK)
+}
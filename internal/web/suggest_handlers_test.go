@@ -0,0 +1,59 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/mls"
+)
+
+func TestAPISuggestWithoutProvider(t *testing.T) {
+	srv, _, token := testAPIServerWithDB(t)
+
+	w := apiRequest(t, srv, "GET", "/api/suggest?q=123+Main", token, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAPISuggest(t *testing.T) {
+	provider, err := mls.NewFileProvider(filepath.Join("..", "mls", "testdata"))
+	if err != nil {
+		t.Fatalf("new file provider: %v", err)
+	}
+	srv, _, token := testAPIServerWithProvider(t, provider)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantN    int
+	}{
+		{"match", "/api/suggest?q=main+st", http.StatusOK, 1},
+		{"no match", "/api/suggest?q=elm+st", http.StatusOK, 0},
+		{"limit", "/api/suggest?q=ok&limit=1", http.StatusOK, 1},
+		{"missing q", "/api/suggest", http.StatusBadRequest, 0},
+		{"bad limit", "/api/suggest?q=main&limit=50", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, "GET", tt.path, token, nil)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var candidates []mls.Candidate
+			if err := json.NewDecoder(w.Body).Decode(&candidates); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(candidates) != tt.wantN {
+				t.Errorf("got %d candidates, want %d", len(candidates), tt.wantN)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/tag"
+)
+
+// handleAPITags handles /api/tags and /api/tags/{name}. GET lists tags
+// with their property counts and POST creates one; PATCH renames a tag
+// and DELETE removes it from every property.
+func (s *Server) handleAPITags(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/tags"), "/")
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.apiListTags(w)
+		case http.MethodPost:
+			s.apiCreateTag(w, r)
+		default:
+			apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	switch r.Method {
+	case http.MethodPatch:
+		s.apiRenameTag(w, r, name)
+	case http.MethodDelete:
+		s.apiDeleteTag(w, r, name)
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// apiListTags returns every tag with how many properties carry it.
+func (s *Server) apiListTags(w http.ResponseWriter) {
+	tags, err := s.tagRepo.List()
+	if err != nil {
+		apiError(w, fmt.Sprintf("listing tags: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if tags == nil {
+		tags = make([]*tag.Tag, 0)
+	}
+	apiJSON(w, tags, http.StatusOK)
+}
+
+// apiCreateTag adds a tag before any property carries it.
+func (s *Server) apiCreateTag(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := s.tagRepo.Create(req.Name)
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("tag created", "name", t.Name, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, t, http.StatusCreated)
+}
+
+// apiRenameTag renames a tag everywhere it is used.
+func (s *Server) apiRenameTag(w http.ResponseWriter, r *http.Request, name string) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apiError(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := s.tagRepo.Rename(name, req.Name)
+	if errors.Is(err, tag.ErrNotFound) {
+		apiError(w, "tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("tag renamed", "from", name, "to", t.Name, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, t, http.StatusOK)
+}
+
+// apiDeleteTag removes a tag from every property.
+func (s *Server) apiDeleteTag(w http.ResponseWriter, r *http.Request, name string) {
+	if err := s.tagRepo.Delete(name); err != nil {
+		if errors.Is(err, tag.ErrNotFound) {
+			apiError(w, "tag not found", http.StatusNotFound)
+			return
+		}
+		apiError(w, fmt.Sprintf("deleting tag: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("tag deleted", "name", name, "user", auth.UserEmailFromContext(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiPropertyTags handles /api/properties/{id}/tags and
+// /api/properties/{id}/tags/{name}. GET lists the property's tags, POST
+// adds some (JSON: {"tags": [...]}) and DELETE with a name removes one.
+// Each responds with the property's tags afterwards.
+func (s *Server) apiPropertyTags(w http.ResponseWriter, r *http.Request, id int64, name string) {
+	if _, err := s.propRepo.GetByID(id); err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+	case name == "" && r.Method == http.MethodPost:
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apiError(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if len(req.Tags) == 0 {
+			apiError(w, "tags is required", http.StatusBadRequest)
+			return
+		}
+		if err := s.tagRepo.Add(id, req.Tags...); err != nil {
+			apiError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Info("property tagged", "id", id, "tags", req.Tags, "user", auth.UserEmailFromContext(r))
+	case name != "" && r.Method == http.MethodDelete:
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+		if err := s.tagRepo.Remove(id, name); err != nil {
+			if errors.Is(err, tag.ErrNotFound) {
+				apiError(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			apiError(w, fmt.Sprintf("removing tag: %v", err), http.StatusInternalServerError)
+			return
+		}
+		slog.Info("property untagged", "id", id, "tag", name, "user", auth.UserEmailFromContext(r))
+	default:
+		apiError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags, err := s.tagRepo.ForProperties(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading tags: %v", err), http.StatusInternalServerError)
+		return
+	}
+	names := tags[id]
+	if names == nil {
+		names = make([]string, 0)
+	}
+	apiJSON(w, names, http.StatusOK)
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+	"github.com/evcraddock/house-finder/internal/tag"
+)
+
+func TestAPIPropertyTags(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+	other := insertAPITestProperty(t, d)
+
+	decodeTags := func(w *httptest.ResponseRecorder) []string {
+		t.Helper()
+		var tags []string
+		if err := json.NewDecoder(w.Body).Decode(&tags); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return tags
+	}
+
+	path := fmt.Sprintf("/api/properties/%d/tags", id)
+	w := apiRequest(t, srv, "POST", path, token, map[string][]string{"tags": {"needs roof", "comments"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("add status = %d: %s", w.Code, w.Body.String())
+	}
+	if got := decodeTags(w); fmt.Sprint(got) != "[comments needs roof]" {
+		t.Errorf("tags = %v", got)
+	}
+
+	// A tag named like another route still goes to the tag handler.
+	w = apiRequest(t, srv, "DELETE", path+"/comments", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("remove status = %d: %s", w.Code, w.Body.String())
+	}
+	if got := decodeTags(w); fmt.Sprint(got) != "[needs roof]" {
+		t.Errorf("tags after remove = %v", got)
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/properties?tag=needs+roof", token, nil)
+	var props []*property.Property
+	if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(props) != 1 || props[0].ID != id || fmt.Sprint(props[0].Tags) != "[needs roof]" {
+		t.Errorf("list ?tag=needs roof = %+v, want only #%d with its tag", props, id)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"unknown tag filter", "GET", "/api/properties?tag=nope", nil, http.StatusBadRequest},
+		{"remove tag not on property", "DELETE", fmt.Sprintf("/api/properties/%d/tags/needs%%20roof", other), nil, http.StatusNotFound},
+		{"add invalid tag", "POST", path, map[string][]string{"tags": {"a,b"}}, http.StatusBadRequest},
+		{"add no tags", "POST", path, map[string][]string{"tags": {}}, http.StatusBadRequest},
+		{"missing property", "GET", "/api/properties/9999/tags", nil, http.StatusNotFound},
+		{"put not allowed", "PUT", path, nil, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, tt.path, token, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPITags(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	id := insertAPITestProperty(t, d)
+
+	w := apiRequest(t, srv, "POST", "/api/tags", token, map[string]string{"name": "top pick"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", w.Code, w.Body.String())
+	}
+	if w := apiRequest(t, srv, "POST", "/api/tags", token, map[string]string{"name": "Top Pick"}); w.Code != http.StatusBadRequest {
+		t.Errorf("duplicate create status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/tags", id), token, map[string][]string{"tags": {"TOP PICK"}}); w.Code != http.StatusOK {
+		t.Fatalf("tag property status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = apiRequest(t, srv, "PATCH", "/api/tags/top%20pick", token, map[string]string{"name": "favorite"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("rename status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = apiRequest(t, srv, "GET", "/api/tags", token, nil)
+	var tags []tag.Tag
+	if err := json.NewDecoder(w.Body).Decode(&tags); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "favorite" || tags[0].Count != 1 {
+		t.Errorf("tags = %+v, want favorite on one property", tags)
+	}
+
+	if w := apiRequest(t, srv, "DELETE", "/api/tags/favorite", token, nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := apiRequest(t, srv, "DELETE", "/api/tags/favorite", token, nil); w.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := apiRequest(t, srv, "PATCH", "/api/tags/nope", token, map[string]string{"name": "x"}); w.Code != http.StatusNotFound {
+		t.Errorf("rename missing status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleListFiltersByTag(t *testing.T) {
+	srv, d := testServerWithDB(t)
+	insertTestProperty(t, d, "123 Main St", "M-TAG-1")
+	insertTestProperty(t, d, "456 Oak Ave", "M-TAG-2")
+	tagged, err := property.NewRepository(d).GetByMprID("M-TAG-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := tag.NewRepository(d).Add(tagged.ID, "near school"); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/?tag=near+school", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, body)
+	}
+	if !strings.Contains(body, "123 Main St") || strings.Contains(body, "456 Oak Ave") {
+		t.Error("expected only the tagged property")
+	}
+	if !strings.Contains(body, `class="tag-chip">near school</a>`) {
+		t.Error("expected a tag chip linking to the filter")
+	}
+
+	r = httptest.NewRequest("GET", "/?tag=nope", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown tag status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
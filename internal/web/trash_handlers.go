@@ -0,0 +1,94 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/evcraddock/house-finder/internal/auth"
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+// DefaultTrashRetention is how long a deleted property stays in the trash
+// before the purge job removes it for good.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// SetTrashRetention sets how long deleted properties are kept before they
+// are purged.
+func (s *Server) SetTrashRetention(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("trash retention must be positive, got %s", d)
+	}
+	s.trashRetention = d
+	return nil
+}
+
+// purgeTrash permanently removes properties that have been in the trash
+// longer than the retention period.
+func (s *Server) purgeTrash() error {
+	n, err := s.propRepo.Purge(time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.Info("trash purged", "count", n, "retention", s.trashRetention.String())
+	}
+	return nil
+}
+
+// apiDeleteProperty moves a property to the trash.
+func (s *Server) apiDeleteProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, ok := s.changeState(w, id, "deleting", s.propRepo.Delete, property.StateActive, property.StateArchived); !ok {
+		return
+	}
+	slog.Info("property moved to trash", "id", id, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, map[string]interface{}{"id": id, "removed": true}, http.StatusOK)
+}
+
+// apiArchiveProperty hides a property from lists, keeping its history.
+func (s *Server) apiArchiveProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	p, ok := s.changeState(w, id, "archiving", s.propRepo.Archive, property.StateActive, property.StateDeleted)
+	if !ok {
+		return
+	}
+	slog.Info("property archived", "id", id, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, p, http.StatusOK)
+}
+
+// apiRestoreProperty returns an archived or trashed property to the list.
+func (s *Server) apiRestoreProperty(w http.ResponseWriter, r *http.Request, id int64) {
+	p, ok := s.changeState(w, id, "restoring", s.propRepo.Restore, property.StateArchived, property.StateDeleted)
+	if !ok {
+		return
+	}
+	slog.Info("property restored", "id", id, "user", auth.UserEmailFromContext(r))
+	apiJSON(w, p, http.StatusOK)
+}
+
+// changeState applies change to property id if it is in one of the from
+// states and returns the updated property. On failure it writes the error
+// response and reports false.
+func (s *Server) changeState(w http.ResponseWriter, id int64, doing string, change func(int64) error, from ...property.State) (*property.Property, bool) {
+	p, err := s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, "property not found", http.StatusNotFound)
+		return nil, false
+	}
+	if !slices.Contains(from, p.State) {
+		apiError(w, fmt.Sprintf("property %d is %s", id, p.State), http.StatusConflict)
+		return nil, false
+	}
+
+	if err := change(id); err != nil {
+		apiError(w, fmt.Sprintf("%s property: %v", doing, err), http.StatusInternalServerError)
+		return nil, false
+	}
+	p, err = s.propRepo.GetByID(id)
+	if err != nil {
+		apiError(w, fmt.Sprintf("loading property: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return p, true
+}
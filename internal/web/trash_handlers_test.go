@@ -0,0 +1,122 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/evcraddock/house-finder/internal/property"
+)
+
+func TestAPIArchiveAndRestore(t *testing.T) {
+	srv, d, token := testAPIServerWithDB(t)
+	kept := insertAPITestProperty(t, d)
+	archived := insertAPITestProperty(t, d)
+	trashed := insertAPITestProperty(t, d)
+
+	w := apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/archive", archived), token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("archive status = %d: %s", w.Code, w.Body.String())
+	}
+	var p property.Property
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.State != property.StateArchived || p.ArchivedAt == nil {
+		t.Errorf("archived property: state = %q, archived_at = %v", p.State, p.ArchivedAt)
+	}
+	if w := apiRequest(t, srv, "DELETE", fmt.Sprintf("/api/properties/%d", trashed), token, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body.String())
+	}
+
+	listed := func(query string) []int64 {
+		t.Helper()
+		w := apiRequest(t, srv, "GET", "/api/properties"+query, token, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list%s status = %d: %s", query, w.Code, w.Body.String())
+		}
+		var props []*property.Property
+		if err := json.NewDecoder(w.Body).Decode(&props); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		ids := make([]int64, 0, len(props))
+		for _, p := range props {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	for query, want := range map[string][]int64{
+		"":                {kept},
+		"?state=active":   {kept},
+		"?state=archived": {archived},
+		"?state=deleted":  {trashed},
+	} {
+		if got := listed(query); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("list%s = %v, want %v", query, got, want)
+		}
+	}
+	if w := apiRequest(t, srv, "GET", "/api/properties?state=gone", token, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid state: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	for _, id := range []int64{archived, trashed} {
+		w := apiRequest(t, srv, "POST", fmt.Sprintf("/api/properties/%d/restore", id), token, nil)
+		if w.Code != http.StatusOK {
+			t.Errorf("restore %d status = %d: %s", id, w.Code, w.Body.String())
+		}
+	}
+	if got := listed(""); len(got) != 3 {
+		t.Errorf("after restore, list = %v, want all three", got)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"restore active", "POST", fmt.Sprintf("/api/properties/%d/restore", kept), http.StatusConflict},
+		{"restore missing", "POST", "/api/properties/9999/restore", http.StatusNotFound},
+		{"archive wrong method", "GET", fmt.Sprintf("/api/properties/%d/archive", kept), http.StatusMethodNotAllowed},
+		{"archive bad id", "POST", "/api/properties/abc/archive", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := apiRequest(t, srv, tt.method, tt.path, token, nil)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	srv, d, _ := testAPIServerWithDB(t)
+	old := insertAPITestProperty(t, d)
+	recent := insertAPITestProperty(t, d)
+
+	repo := property.NewRepository(d)
+	for _, id := range []int64{old, recent} {
+		if err := repo.Delete(id); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	}
+	if _, err := d.Exec("UPDATE properties SET deleted_at = datetime('now', '-31 days') WHERE id = ?", old); err != nil {
+		t.Fatalf("backdating: %v", err)
+	}
+
+	if err := srv.purgeTrash(); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if _, err := repo.GetByID(old); err == nil {
+		t.Error("property in the trash for 31 days was not purged")
+	}
+	if _, err := repo.GetByID(recent); err != nil {
+		t.Errorf("recently deleted property was purged: %v", err)
+	}
+
+	if err := srv.SetTrashRetention(0); err == nil {
+		t.Error("expected error for zero retention")
+	}
+}
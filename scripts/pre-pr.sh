@@ -10,6 +10,6 @@ echo "→ Linting..."
 golangci-lint run
 
 echo "→ Running tests..."
-go test ./...
+go test -tags sqlite_fts5 ./...
 
 echo "✓ All checks passed!"